@@ -20,15 +20,22 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 
       terraform plan -out tfplan.binary
       terraform show -json tfplan.binary > plan.json
-      infracost breakdown --path plan.json`,
+      infracost breakdown --path plan.json
+
+  Use AWS CDK cloud assembly:
+
+      cdk synth
+      infracost breakdown --path cdk.out`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
 				return err
 			}
 
-			err := loadRunFlags(ctx.Config, cmd)
-			if err != nil {
+			// This is checked after the flags are loaded since the pricing
+			// sources can be set in the config file.
+			if err := checkPricingConfig(ctx.Config, cmd); err != nil {
 				return err
 			}
 
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func cacheCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the on-disk cache of Pricing API responses",
+		Long:  "Manage the on-disk cache of Pricing API responses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(cachePruneCmd(ctx))
+
+	return cmd
+}
+
+func cachePruneCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove expired entries from the price cache",
+		Long:  "Remove expired entries from the price cache. Entries expire after the price_cache_ttl, which defaults to 24h",
+		Example: `  Remove expired entries:
+
+      infracost cache prune
+
+  Remove all entries:
+
+      infracost cache prune --all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ttl := ctx.Config.PriceCacheMaxAge()
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				ttl = 0
+			}
+
+			removed, err := prices.PrunePriceCache(ctx.Config.PriceCachePath(), ttl)
+			if err != nil {
+				return err
+			}
+
+			ui.PrintSuccessf(cmd.ErrOrStderr(), "Removed %d entries from the price cache", removed)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Remove all entries, not only expired ones")
+
+	return cmd
+}
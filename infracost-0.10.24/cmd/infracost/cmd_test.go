@@ -108,6 +108,7 @@ func GetCommandOutput(t *testing.T, args []string, testOptions *GoldenFileOption
 		enableCloud := false
 		c.Config.EnableCloud = &enableCloud
 		c.Config.EventsDisabled = true
+		c.Config.NoPriceCache = true
 		c.Config.Currency = currency
 		c.Config.NoColor = true
 		c.ErrWriter = errBuf
@@ -239,8 +239,37 @@ func readPolicyOut(v map[string]interface{}, checks *output.PolicyCheck) {
 
 	if failed {
 		checks.Failures = append(checks.Failures, msg)
+
+		if addresses := readPolicyAddresses(v); len(addresses) > 0 {
+			if checks.FailureAddresses == nil {
+				checks.FailureAddresses = make(map[string][]string)
+			}
+			checks.FailureAddresses[msg] = append(checks.FailureAddresses[msg], addresses...)
+		}
+
 		return
 	}
 
 	checks.Passed = append(checks.Passed, msg)
 }
+
+// readPolicyAddresses returns the resource addresses from the optional
+// {address: string} or {addresses: []string} properties of the policy output
+// object, so failures can be linked to the resources that caused them.
+func readPolicyAddresses(v map[string]interface{}) []string {
+	var addresses []string
+
+	if address, ok := v["address"].(string); ok && address != "" {
+		addresses = append(addresses, address)
+	}
+
+	if values, ok := v["addresses"].([]interface{}); ok {
+		for _, value := range values {
+			if address, ok := value.(string); ok && address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	return addresses
+}
@@ -26,19 +26,31 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
       # Make Terraform code changes
       infracost diff --path /code --compare-to infracost-base.json
 
+  Compare prices on two dates without any code changes:
+
+      infracost diff --path /code --compare-price-date 2024-01-01 --price-date 2024-06-01
+
   Use Terraform plan JSON:
 
       terraform plan -out tfplan.binary
       terraform show -json tfplan.binary > plan.json
-      infracost diff --path plan.json`,
+      infracost diff --path plan.json
+
+  Use CloudFormation change set:
+
+      aws cloudformation describe-change-set --change-set-name my-change-set \
+        --stack-name my-stack --include-property-values > change-set.json
+      infracost diff --path change-set.json --compare-template deployed.yml`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
 				return err
 			}
 
-			err := loadRunFlags(ctx.Config, cmd)
-			if err != nil {
+			// This is checked after the flags are loaded since the pricing
+			// sources can be set in the config file.
+			if err := checkPricingConfig(ctx.Config, cmd); err != nil {
 				return err
 			}
 
@@ -61,6 +73,7 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 	addRunFlags(cmd)
 
 	cmd.Flags().String("compare-to", "", "Path to Infracost JSON file to compare against")
+	cmd.Flags().String("compare-price-date", "", "Compare against the same projects priced using the prices effective on this date (YYYY-MM-DD)")
 	newEnumFlag(cmd, "format", "diff", "Output format", []string{"json", "diff"})
 	cmd.Flags().String("out-file", "", "Save output to a file")
 
@@ -129,7 +142,7 @@ func checkDiffConfig(cfg *config.Config) error {
 		}
 
 		projectType := providers.DetectProjectType(projectConfig.Path, projectConfig.TerraformForceCLI)
-		if (projectType == "terraform_dir" || projectType == "terragrunt_dir") && cfg.CompareTo == "" {
+		if (projectType == "terraform_dir" || projectType == "terragrunt_dir") && cfg.CompareTo == "" && cfg.ComparePriceDate == "" {
 			examplePath := "/code"
 			if projectConfig.Path != "" {
 				examplePath = projectConfig.Path
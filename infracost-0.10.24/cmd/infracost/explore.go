@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/explore"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func exploreCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explore",
+		Short: "Explore a breakdown of costs interactively",
+		Long: `Explore a breakdown of costs interactively in the terminal.
+
+Projects can be expanded to show their resources, subresources and cost
+components. Press s to sort by monthly cost, diff or name, / to filter by
+resource type (type:aws_instance), tag (tag:env=prod) or name, and o to open
+the file that the selected resource is defined in using $VISUAL or $EDITOR.`,
+		Example: `  Explore an Infracost JSON file:
+
+      infracost breakdown --path /code --format json --out-file infracost.json
+      infracost explore --path infracost.json
+
+  Explore a Terraform directory:
+
+      infracost explore --path /code --terraform-var-file my.tfvars`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			// Check this before running the projects so the user doesn't have to wait to see the error.
+			if err := explore.CheckTerminal(os.Stdin, os.Stdout); err != nil {
+				return err
+			}
+
+			ctx.SetContextValue("outputFormat", "explore")
+
+			root, err := loadExploreOutput(cmd, ctx)
+			if err != nil {
+				return err
+			}
+
+			return explore.Run(root, os.Stdin, os.Stdout)
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().String("compare-to", "", "Path to Infracost JSON file to compare against")
+
+	return cmd
+}
+
+// loadExploreOutput returns the output to explore. If the path is an Infracost
+// JSON file it is loaded, otherwise the projects are run.
+func loadExploreOutput(cmd *cobra.Command, ctx *config.RunContext) (output.Root, error) {
+	if len(ctx.Config.Projects) == 1 {
+		root, err := output.Load(ctx.Config.Projects[0].Path)
+		if err == nil {
+			if ctx.Config.CompareTo == "" {
+				return root, nil
+			}
+
+			prior, err := output.Load(ctx.Config.CompareTo)
+			if err != nil {
+				return output.Root{}, fmt.Errorf("Error loading %s used by --compare-to flag. %s", ctx.Config.CompareTo, err)
+			}
+
+			return output.CompareTo(root, prior)
+		}
+	}
+
+	if err := checkPricingConfig(ctx.Config, cmd); err != nil {
+		return output.Root{}, err
+	}
+
+	err := checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+	if err != nil {
+		ui.PrintUsage(cmd)
+		return output.Root{}, err
+	}
+
+	root, _, err := runProjects(cmd, ctx)
+	return root, err
+}
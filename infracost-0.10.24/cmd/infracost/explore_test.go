@@ -0,0 +1,15 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestExploreHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"explore", "--help"}, nil)
+}
+
+func TestExploreNotTerminal(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"explore", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
@@ -179,10 +179,14 @@ func newRootCmd(ctx *config.RunContext) *cobra.Command {
 	rootCmd.AddCommand(configureCmd(ctx))
 	rootCmd.AddCommand(diffCmd(ctx))
 	rootCmd.AddCommand(breakdownCmd(ctx))
+	rootCmd.AddCommand(exploreCmd(ctx))
 	rootCmd.AddCommand(scanCommand(ctx))
 	rootCmd.AddCommand(outputCmd(ctx))
 	rootCmd.AddCommand(uploadCmd(ctx))
+	rootCmd.AddCommand(repriceCmd(ctx))
 	rootCmd.AddCommand(commentCmd(ctx))
+	rootCmd.AddCommand(notifyCmd(ctx))
+	rootCmd.AddCommand(cacheCmd(ctx))
 	rootCmd.AddCommand(completionCmd())
 	rootCmd.AddCommand(figAutocompleteCmd())
 	rootCmd.AddCommand(newGenerateCommand())
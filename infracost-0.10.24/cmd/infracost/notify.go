@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/notify"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+var validNotifyFormats = []string{"slack-message", "teams-message", "json"}
+
+func notifyCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Post an Infracost message to a Slack, Microsoft Teams or other incoming webhook",
+		Long: `Post an Infracost message to a Slack, Microsoft Teams or other incoming webhook.
+
+The json format posts the combined Infracost JSON so it can be used with any
+tool that accepts JSON webhooks.`,
+		Example: `  Post a message to a Slack channel:
+
+      infracost notify --format slack-message --path infracost.json --webhook-url $SLACK_WEBHOOK_URL
+
+  Post an Adaptive Card to a Microsoft Teams channel:
+
+      infracost notify --format teams-message --path "infracost*.json" --webhook-url $TEAMS_WEBHOOK_URL # glob needs quotes
+
+  Show the message without posting it:
+
+      infracost notify --format teams-message --path infracost.json --webhook-url $TEAMS_WEBHOOK_URL --dry-run`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			format = strings.ToLower(format)
+			ctx.SetContextValue("outputFormat", format)
+
+			if !contains(validNotifyFormats, format) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--format only supports %s", strings.Join(validNotifyFormats, ", "))
+			}
+
+			webhookURL, _ := cmd.Flags().GetString("webhook-url")
+			if !strings.HasPrefix(webhookURL, "https://") && !strings.HasPrefix(webhookURL, "http://") {
+				ui.PrintUsage(cmd)
+				return errors.New("--webhook-url must be an http or https URL")
+			}
+
+			retries, _ := cmd.Flags().GetInt("retries")
+			if retries < 0 {
+				ui.PrintUsage(cmd)
+				return errors.New("--retries must be 0 or more")
+			}
+
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := output.LoadPaths(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs)
+			if errors.As(err, &clierror.WarningError{}) {
+				ui.PrintWarningf(cmd.ErrOrStderr(), err.Error())
+			} else if err != nil {
+				return err
+			}
+			combined.IsCIRun = ctx.IsCIRun()
+			combined.Metadata.InfracostCommand = "notify"
+
+			var policyChecks output.PolicyCheck
+			policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
+			if len(policyPaths) > 0 {
+				policyChecks, err = queryPolicy(policyPaths, combined)
+				if err != nil {
+					return err
+				}
+			}
+
+			opts := output.Options{
+				DashboardEndpoint: ctx.Config.DashboardEndpoint,
+				NoColor:           true,
+				PolicyChecks:      policyChecks,
+				TagPolicyCheck:    output.NewTagPolicyChecks(combined.TagPolicies),
+			}
+			opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
+
+			b, err := output.FormatOutput(format, combined, opts)
+			if err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				cmd.Println(string(b))
+				cmd.Println("Message not posted to webhook (--dry-run was specified)")
+				return nil
+			}
+
+			if skipNoDiff, _ := cmd.Flags().GetBool("skip-no-diff"); skipNoDiff && !combined.HasDiff() {
+				cmd.Println("Message not posted to webhook (skipped)")
+				return nil
+			}
+
+			err = notify.PostWebhook(ctx.Context(), webhookURL, b, notify.WebhookOptions{Retries: retries})
+			if err != nil {
+				return err
+			}
+
+			pricingClient := apiclient.NewPricingAPIClient(ctx)
+			err = pricingClient.AddEvent("infracost-notify", ctx.EventEnv())
+			if err != nil {
+				logging.Logger.WithError(err).Error("could not report infracost-notify event")
+			}
+
+			cmd.Println("Message posted to webhook")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	cmd.Flags().String("format", "slack-message", "Message format: slack-message, teams-message, json")
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validNotifyFormats, cobra.ShellCompDirectiveDefault
+	})
+	cmd.Flags().String("webhook-url", "", "Incoming webhook URL to post the message to")
+	_ = cmd.MarkFlagRequired("webhook-url")
+	cmd.Flags().Int("retries", 3, "Number of times to retry posting the message if the webhook is unavailable")
+	cmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental)")
+	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the message, not only the projects with cost changes")
+	cmd.Flags().Bool("skip-no-diff", false, "Skip posting the message if there are no cost changes")
+	cmd.Flags().Bool("dry-run", false, "Generate the message without posting it to the webhook")
+
+	return cmd
+}
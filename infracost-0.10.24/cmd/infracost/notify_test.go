@@ -0,0 +1,71 @@
+package main_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestNotifyHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--help"}, nil)
+}
+
+func TestNotifyInvalidFormat(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--format", "github-comment", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", "https://example.com/webhook"}, nil)
+}
+
+func TestNotifyDryRun(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"notify", "--format", "teams-message", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", "https://example.com/webhook", "--dry-run"}, nil)
+}
+
+func TestNotifyPostsToWebhook(t *testing.T) {
+	tests := []struct {
+		format string
+		path   string
+	}{
+		{format: "slack-message", path: "blocks"},
+		{format: "teams-message", path: "attachments.0.content.type"},
+		{format: "json", path: "projects.0.breakdown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var body []byte
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer ts.Close()
+
+			out := GetCommandOutput(t, []string{"notify", "--format", tt.format, "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", ts.URL}, nil)
+
+			assert.Contains(t, string(out), "Message posted to webhook")
+			require.True(t, gjson.ValidBytes(body))
+			assert.True(t, gjson.GetBytes(body, tt.path).Exists())
+		})
+	}
+}
+
+func TestNotifyWebhookError(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid_payload"))
+	}))
+	defer ts.Close()
+
+	out := GetCommandOutput(t, []string{"notify", "--path", "./testdata/terraform_v0.14_breakdown.json", "--webhook-url", ts.URL, "--retries", "2"}, nil)
+
+	assert.Contains(t, string(out), "Error posting to webhook: 400 Bad Request: invalid_payload")
+	// 4xx responses other than 429 are not retried.
+	assert.Equal(t, 1, calls)
+}
@@ -27,6 +27,12 @@ var (
 		"bitbucket-comment",
 		"bitbucket-comment-summary",
 		"slack-message",
+		"teams-message",
+		"csv",
+		"xlsx",
+		"junit",
+		"sarif",
+		"template",
 	}
 
 	validCompareToFormats = map[string]bool{
@@ -38,6 +44,7 @@ var (
 		"bitbucket-comment":         true,
 		"bitbucket-comment-summary": true,
 		"slack-message":             true,
+		"teams-message":             true,
 	}
 )
 
@@ -72,7 +79,39 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
   Create markdown report to post in a Bitbucket comment:
 
-      infracost output --format bitbucket-comment --path "out*.json" # glob needs quotes`,
+      infracost output --format bitbucket-comment --path "out*.json" # glob needs quotes
+
+  Create a Microsoft Teams Adaptive Card message, use infracost notify to post it:
+
+      infracost output --format teams-message --path "out*.json" # glob needs quotes
+
+  Show the monthly cost of each team using the team tag:
+
+      infracost output --path "out*.json" --group-by tag:team # glob needs quotes
+
+  Show resources costing over $100/month in projects whose names start with prod-:
+
+      infracost output --path "out*.json" --filter 'glob(project.name, "prod-*") && resource.monthlyCost > 100' # glob needs quotes
+
+  Show cost components whose monthly cost increased by more than 10%:
+
+      infracost output --format diff --path infracost.json --filter 'component.diffPercent > 10'
+
+  Create a spreadsheet with a row per cost component:
+
+      infracost output --format xlsx --path "out*.json" --out-file infracost.xlsx # glob needs quotes
+
+  Create a JUnit report of the cost estimates and policy checks for CI:
+
+      infracost output --format junit --path "out*.json" --policy-path policy.rego --out-file infracost-junit.xml # glob needs quotes
+
+  Create a SARIF report of policy violations to upload to a code scanning tool:
+
+      infracost output --format sarif --path "out*.json" --policy-path policy.rego --out-file infracost.sarif # glob needs quotes
+
+  Create a report using your own Go template:
+
+      infracost output --format template --template-path report.tmpl --path "out*.json" # glob needs quotes`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
@@ -86,6 +125,21 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				return fmt.Errorf("--format only supports %s", strings.Join(validOutputFormats, ", "))
 			}
 
+			templatePath, _ := cmd.Flags().GetString("template-path")
+			if format == "template" && templatePath == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--template-path is required when using --format template")
+			}
+			if templatePath != "" && format != "template" {
+				ui.PrintWarning(cmd.ErrOrStderr(), "--template-path is only used with --format template")
+			}
+
+			// xlsx is a binary format so it can't be shown in the terminal.
+			if outFile, _ := cmd.Flags().GetString("out-file"); format == "xlsx" && outFile == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--out-file is required when using --format xlsx")
+			}
+
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			inputs, err := output.LoadPaths(paths)
@@ -93,7 +147,20 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				return err
 			}
 
-			combined, err := output.Combine(inputs)
+			if cmd.Flags().Changed("exchange-rates") {
+				ctx.Config.ExchangeRatesFile, _ = cmd.Flags().GetString("exchange-rates")
+			}
+
+			if err := ctx.Config.LoadExchangeRates(); err != nil {
+				return err
+			}
+
+			var combined output.Root
+			if ctx.Config.ExchangeRates != nil {
+				combined, err = output.CombineWithExchangeRates(inputs, ctx.Config.ExchangeRates, ctx.Config.Currency)
+			} else {
+				combined, err = output.Combine(inputs)
+			}
 			if errors.As(err, &clierror.WarningError{}) {
 				if format == "json" {
 					ui.PrintWarningf(cmd.ErrOrStderr(), err.Error())
@@ -104,6 +171,18 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 			combined.IsCIRun = ctx.IsCIRun()
 			combined.Metadata.InfracostCommand = "output"
 
+			if filterFlag, _ := cmd.Flags().GetString("filter"); filterFlag != "" {
+				filter, err := output.ParseFilter(filterFlag)
+				if err != nil {
+					ui.PrintUsage(cmd)
+					return err
+				}
+
+				if err := filter.Apply(&combined); err != nil {
+					return err
+				}
+			}
+
 			includeAllFields := "all"
 			validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
 
@@ -127,15 +206,52 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				}
 			}
 
+			var policyChecks output.PolicyCheck
+			policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
+			if len(policyPaths) > 0 {
+				policyChecks, err = queryPolicy(policyPaths, combined)
+				if err != nil {
+					return err
+				}
+			}
+
+			var guardrailCheck output.GuardrailCheck
+			if guardrailCheckPath, _ := cmd.Flags().GetString("guardrail-check-path"); guardrailCheckPath != "" {
+				guardrailCheck, err = output.LoadGuardrailCheck(guardrailCheckPath)
+				if err != nil {
+					return fmt.Errorf("Error loading %s used by --guardrail-check-path flag. %s", guardrailCheckPath, err)
+				}
+			}
+
 			opts := output.Options{
 				DashboardEndpoint: ctx.Config.DashboardEndpoint,
 				NoColor:           ctx.Config.NoColor,
 				Fields:            fields,
 				CurrencyFormat:    ctx.Config.CurrencyFormat,
+				PolicyChecks:      policyChecks,
+				TagPolicyCheck:    output.NewTagPolicyChecks(combined.TagPolicies),
+				GuardrailCheck:    guardrailCheck,
+				TemplatePath:      templatePath,
 			}
 			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 			opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
 
+			if groupByFlag, _ := cmd.Flags().GetString("group-by"); groupByFlag != "" {
+				groupBy, err := output.ParseGroupBy(groupByFlag)
+				if err != nil {
+					ui.PrintUsage(cmd)
+					return err
+				}
+
+				validGroupByFormats := []string{"table", "json", "csv", "github-comment", "gitlab-comment", "azure-repos-comment", "bitbucket-comment", "bitbucket-comment-summary"}
+				if !contains(validGroupByFormats, format) {
+					ui.PrintWarningf(cmd.ErrOrStderr(), "group-by is only supported for %s output formats", strings.Join(validGroupByFormats, ", "))
+				}
+
+				combined.GroupBy = groupBy.String()
+				combined.Groups = output.GroupResources(combined, groupBy)
+			}
+
 			validFieldsFormats := []string{"table", "html"}
 
 			if cmd.Flags().Changed("fields") && !contains(validFieldsFormats, format) {
@@ -178,10 +294,17 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
 	cmd.Flags().StringP("out-file", "o", "", "Save output to a file, helpful with format flag")
 
-	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message")
+	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message, teams-message, csv, xlsx, junit, sarif, template")
+	cmd.Flags().String("group-by", "", "Aggregate resource costs by tag:<key>, resourceType, module, region, provider or project.\nSupported by table, json, csv and comment output formats")
+	cmd.Flags().String("filter", "", "Only include the projects, resources or cost components that match an HCL expression.\nVariables: project, resource, component with name, monthlyCost, pastMonthlyCost, diffMonthlyCost, diffPercent, etc.\nFunctions: glob, matches, contains, startswith, endswith, lower, upper, lookup")
+	cmd.Flags().String("template-path", "", "Path to a Go template file used by the template format, .html templates are HTML escaped")
 	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().String("exchange-rates", "", "Path to a file of exchange rates used to convert JSON files in other currencies to the output currency")
+	cmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental)")
+	cmd.Flags().String("guardrail-check-path", "", "Path to Infracost guardrail data (experimental)")
+	_ = cmd.Flags().MarkHidden("guardrail-check-path")
 
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/testutil"
 )
 
@@ -142,6 +143,18 @@ func TestOutputFormatSlackMessageMoreProjects(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "slack-message", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json"}, nil)
 }
 
+func TestOutputFormatTeamsMessage(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "teams-message", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, nil)
+}
+
+func TestOutputFormatTeamsMessageNoChange(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "teams-message", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, nil)
+}
+
+func TestOutputFormatTeamsMessageWithPolicyFailures(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "teams-message", "--path", "./testdata/terraform_v0.14_breakdown.json", "--policy-path", "./testdata/output_format_sarif/policy.rego"}, nil)
+}
+
 func TestOutputFormatTable(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
 }
@@ -199,3 +212,129 @@ func TestOutputTerraformOutFileTable(t *testing.T) {
 func TestOutputJSONArrayPath(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--path", "[\"./testdata/example_out.json\", \"./testdata/terraform_v0.14*breakdown.json\"]"}, nil)
 }
+
+func TestOutputFormatCSV(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "csv", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatDiffCSV(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "csv", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFormatJUnit(t *testing.T) {
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"output",
+			"--format", "junit",
+			"--path", "./testdata/terraform_v0.14_breakdown.json",
+			"--policy-path", path.Join(dir, "policy.rego"),
+			"--guardrail-check-path", "./testdata/comment_git_hub_with_guardrail_check_path/guardrailCheck.json",
+		}, nil)
+}
+
+func TestOutputFormatTemplate(t *testing.T) {
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"output",
+			"--format", "template",
+			"--template-path", path.Join(dir, "report.tmpl"),
+			"--path", "./testdata/example_out.json",
+			"--policy-path", path.Join(dir, "policy.rego"),
+		}, nil)
+}
+
+func TestOutputFormatTemplateHTML(t *testing.T) {
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"output",
+			"--format", "template",
+			"--template-path", path.Join(dir, "report.html"),
+			"--path", "./testdata/terraform_v0.14_breakdown.json",
+		}, nil)
+}
+
+func TestOutputFormatXlsxWithoutOutFile(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"output",
+			"--format", "xlsx",
+			"--path", "./testdata/example_out.json",
+		}, nil)
+}
+
+func TestOutputFormatTemplateWithoutPath(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"output",
+			"--format", "template",
+			"--path", "./testdata/example_out.json",
+		}, nil)
+}
+
+func TestOutputFormatSARIF(t *testing.T) {
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(),
+		[]string{
+			"output",
+			"--format", "sarif",
+			"--path", "./testdata/terraform_v0.14_breakdown.json",
+			"--policy-path", path.Join(dir, "policy.rego"),
+		}, nil)
+}
+
+func TestOutputFormatTableGroupByTag(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--group-by", "tag:Environment", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFormatGroupByModuleJSON(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "json", "--group-by", "module", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFormatGroupByResourceTypeCSV(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "csv", "--group-by", "resourceType", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFormatGitHubCommentGroupByProject(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--group-by", "project", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFormatBitbucketCommentGroupByProvider(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "bitbucket-comment", "--group-by", "provider", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputGroupByInvalid(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--group-by", "team", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputFilterResources(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--filter", `glob(project.name, "*testdata*") && resource.monthlyCost > 200`, "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFilterCostComponentsJSON(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "json", "--filter", `startswith(resource.name, "aws_instance.") && component.unit == "GB"`, "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputFilterInvalid(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--filter", "resource.monthlyCost >", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputFormatSarifFromHcl(t *testing.T) {
+	testName := testutil.CalcGoldenFileTestdataDirName()
+	dir := path.Join("./testdata", testName)
+	out := filepath.Join(t.TempDir(), "infracost.json")
+
+	GetCommandOutput(t, []string{"breakdown", "--path", dir, "--format", "json", "--out-file", out}, nil, func(ctx *config.RunContext) {
+		ctx.Config.PricingDataPath = path.Join(dir, "pricing_data")
+	})
+
+	GoldenFileCommandTest(t, testName,
+		[]string{
+			"output",
+			"--format", "sarif",
+			"--path", out,
+			"--policy-path", path.Join(dir, "policy.rego"),
+		}, nil)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/discounts"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func repriceCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reprice",
+		Short: "Look up the prices in an Infracost JSON file again and report price drift",
+		Long: `Look up the prices in an Infracost JSON file again using the price hashes
+recorded in it, and output the file with its costs recalculated. The file must
+be generated with --include-price-hashes. Any cost components whose unit prices
+have changed since the file was generated are reported, so price changes can be
+told apart from changes to the code. The rules from the discounts file are
+applied to the new prices.`,
+		Example: `  Generate a baseline that can be repriced:
+
+      infracost breakdown --path /code --format json --include-price-hashes --out-file infracost-base.json
+
+  Reprice the baseline and show the price drift:
+
+      infracost reprice --path infracost-base.json --out-file infracost-base-repriced.json
+
+  Compare against the repriced baseline so the diff only shows code changes:
+
+      infracost diff --path /code --compare-to infracost-base-repriced.json
+
+  Save the drift report as JSON:
+
+      infracost reprice --path infracost-base.json --drift-file drift.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkPricingConfig(ctx.Config, cmd); err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("price-date") {
+				ctx.Config.PriceDate, _ = cmd.Flags().GetString("price-date")
+			}
+
+			if ctx.Config.PriceDate != "" {
+				if _, err := config.ParsePriceDate(ctx.Config.PriceDate); err != nil {
+					return err
+				}
+			}
+
+			if cmd.Flags().Changed("discounts-file") {
+				ctx.Config.DiscountsFile, _ = cmd.Flags().GetString("discounts-file")
+			}
+
+			if ctx.Config.DiscountsFile != "" {
+				if _, err := discounts.LoadDiscountsFile(ctx.Config.DiscountsFile); err != nil {
+					return err
+				}
+			}
+
+			path, _ := cmd.Flags().GetString("path")
+
+			root, err := output.Load(path)
+			if err != nil {
+				return fmt.Errorf("could not load input file %s err: %w", path, err)
+			}
+
+			// Look up the prices in the currency the file was generated in.
+			if root.Currency != "" {
+				ctx.Config.Currency = root.Currency
+			}
+
+			repricing := output.NewRepricing(&root)
+			if repricing.Len() == 0 && repricing.Skipped > 0 {
+				return errors.New("Infracost JSON file has no recorded price hashes, generate it again with --include-price-hashes to reprice it")
+			}
+
+			spinnerOpts := ui.SpinnerOptions{
+				EnableLogging: ctx.Config.IsLogging(),
+				NoColor:       ctx.Config.NoColor,
+			}
+			spinner := ui.NewSpinner("Retrieving cloud prices to reprice the cost components", spinnerOpts)
+			defer spinner.Fail()
+
+			err = prices.Reprice(ctx, repricing.Resources)
+			if err != nil {
+				return err
+			}
+			report := repricing.Apply()
+			spinner.Success()
+
+			root.TimeGenerated = time.Now().UTC()
+			root.Metadata.InfracostCommand = "reprice"
+
+			cmd.PrintErrln()
+			cmd.PrintErrln(report.String())
+			cmd.PrintErrln()
+
+			if driftFile, _ := cmd.Flags().GetString("drift-file"); driftFile != "" {
+				b, err := json.Marshal(report)
+				if err != nil {
+					return err
+				}
+
+				err = saveOutFileWithMsg(ctx, cmd, driftFile, fmt.Sprintf("Drift report saved to %s", driftFile), b)
+				if err != nil {
+					return err
+				}
+			}
+
+			b, err := output.ToJSON(root, output.Options{})
+			if err != nil {
+				return err
+			}
+
+			if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
+				return saveOutFile(ctx, cmd, outFile, b)
+			}
+
+			cmd.Println(string(b))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to an Infracost JSON file")
+	cmd.Flags().StringP("out-file", "o", "", "Save the repriced Infracost JSON to a file")
+	cmd.Flags().String("drift-file", "", "Save the drift report as JSON to a file")
+	cmd.Flags().String("pricing-data-path", "", "Path to a local Cloud Pricing API data dump (.csv, .csv.gz or .sql) or directory of dumps to use instead of the Pricing API")
+	cmd.Flags().String("price-date", "", "Reprice using the prices that were effective on this date (YYYY-MM-DD)")
+	cmd.Flags().String("discounts-file", "", "Path to a file of discount rules to apply to the list prices")
+
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+	_ = cmd.MarkFlagFilename("pricing-data-path", "csv", "gz", "sql")
+	_ = cmd.MarkFlagFilename("discounts-file", "yml")
+
+	return cmd
+}
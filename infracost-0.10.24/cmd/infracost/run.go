@@ -24,8 +24,10 @@ import (
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/discounts"
 	"github.com/infracost/infracost/internal/output"
 	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/projection"
 	"github.com/infracost/infracost/internal/providers"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
@@ -41,11 +43,14 @@ type projectResult struct {
 	index      int
 	ctx        *config.ProjectContext
 	projectOut *projectOutput
+	errored    bool
 }
 
 func addRunFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice("terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
 	cmd.Flags().StringSlice("terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
+	cmd.Flags().String("cfn-parameters", "", "Path to a JSON or YAML file of parameter values for CloudFormation templates")
+	cmd.Flags().String("compare-template", "", "Path to a CloudFormation template to compare against, e.g. the deployed template. Required when the path is a CloudFormation change set")
 	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory or JSON/plan file")
 
 	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
@@ -64,6 +69,15 @@ func addRunFlags(cmd *cobra.Command) {
 	_ = cmd.Flags().MarkHidden("git-diff-target")
 
 	cmd.Flags().Bool("no-cache", false, "Don't attempt to cache Terraform plans")
+	cmd.Flags().Bool("no-price-cache", false, "Don't use or update the on-disk cache of Pricing API responses")
+
+	cmd.Flags().String("pricing-data-path", "", "Path to a local Cloud Pricing API data dump (.csv, .csv.gz or .sql) or directory of dumps to use instead of the Pricing API")
+	cmd.Flags().String("discounts-file", "", "Path to a file of discount rules to apply to the list prices")
+	cmd.Flags().String("price-date", "", "Price cost components using the prices that were effective on this date (YYYY-MM-DD)")
+	cmd.Flags().String("exchange-rates", "", "Path to a file of exchange rates used to convert USD prices to the output currency")
+	cmd.Flags().Bool("strict-pricing", false, "Fail if the price lookup of any cost component finds no prices or more than one price")
+	cmd.Flags().Bool("include-price-hashes", false, "Include the price hashes, pricing sources and price candidates of cost components in the JSON output")
+	cmd.Flags().String("projection", "", "Project monthly costs over a period, e.g. 12m or 3y, using the growth assumptions in the usage file")
 
 	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
 
@@ -71,7 +85,12 @@ func addRunFlags(cmd *cobra.Command) {
 
 	_ = cmd.MarkFlagFilename("path", "json", "tf")
 	_ = cmd.MarkFlagFilename("config-file", "yml")
+	_ = cmd.MarkFlagFilename("cfn-parameters", "json", "yml", "yaml")
+	_ = cmd.MarkFlagFilename("compare-template", "json", "yml", "yaml", "template")
 	_ = cmd.MarkFlagFilename("usage-file", "yml")
+	_ = cmd.MarkFlagFilename("pricing-data-path", "csv", "gz", "sql")
+	_ = cmd.MarkFlagFilename("discounts-file", "yml")
+	_ = cmd.MarkFlagFilename("exchange-rates", "yml")
 
 	_ = cmd.Flags().MarkHidden("terraform-force-cli")
 	// These are deprecated and will show a warning if used without --terraform-force-cli
@@ -79,11 +98,9 @@ func addRunFlags(cmd *cobra.Command) {
 	_ = cmd.Flags().MarkHidden("terraform-init-flags")
 }
 
-func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
-	if runCtx.Config.IsSelfHosted() && runCtx.IsCloudEnabled() {
-		ui.PrintWarning(cmd.ErrOrStderr(), "Infracost Cloud is part of Infracost's hosted services. Contact hello@infracost.io for help.")
-	}
-
+// runProjects runs all the projects of the run and returns the combined
+// output with the context of each project.
+func runProjects(cmd *cobra.Command, runCtx *config.RunContext) (output.Root, []*config.ProjectContext, error) {
 	repoPath := runCtx.Config.RepoPath()
 	metadata, err := vcs.MetadataFetcher.Get(repoPath, runCtx.Config.GitDiffTarget)
 	if err != nil {
@@ -93,12 +110,26 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 
 	pr, err := newParallelRunner(cmd, runCtx)
 	if err != nil {
-		return err
+		return output.Root{}, nil, err
+	}
+
+	if runCtx.Config.ComparePriceDate != "" {
+		pr.prior, err = pr.runAtPriceDate(runCtx.Config.ComparePriceDate)
+		if err != nil {
+			return output.Root{}, nil, err
+		}
 	}
 
 	projectResults, err := pr.run()
 	if err != nil {
-		return err
+		return output.Root{}, nil, err
+	}
+
+	if runCtx.Config.ProjectionMonths > 0 {
+		err = pr.projectCosts(projectResults)
+		if err != nil {
+			return output.Root{}, nil, err
+		}
 	}
 
 	projects := make([]*schema.Project, 0)
@@ -113,19 +144,39 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 
 	r, err := output.ToOutputFormat(projects)
 	if err != nil {
-		return err
+		return output.Root{}, nil, err
 	}
 
 	if pr.prior != nil {
 		r, err = output.CompareTo(r, *pr.prior)
 		if err != nil {
-			return err
+			return output.Root{}, nil, err
 		}
 	}
 
+	if !runCtx.Config.IncludePriceHashes {
+		r.RemovePriceHashes()
+	}
+
 	r.IsCIRun = runCtx.IsCIRun()
 	r.Currency = runCtx.Config.Currency
 	r.Metadata = output.NewMetadata(runCtx)
+	if pr.prior != nil {
+		r.Metadata.PastPriceDate = pr.prior.Metadata.PriceDate
+	}
+
+	return r, projectContexts, nil
+}
+
+func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
+	if runCtx.Config.IsSelfHosted() && runCtx.IsCloudEnabled() {
+		ui.PrintWarning(cmd.ErrOrStderr(), "Infracost Cloud is part of Infracost's hosted services. Contact hello@infracost.io for help.")
+	}
+
+	r, projectContexts, err := runProjects(cmd, runCtx)
+	if err != nil {
+		return err
+	}
 
 	if runCtx.IsCloudUploadExplicitlyEnabled() {
 		dashboardClient := apiclient.NewDashboardAPIClient(runCtx)
@@ -187,13 +238,22 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 
 type projectOutput struct {
 	projects []*schema.Project
+	// projection holds the projection assumptions from the usage file, it's
+	// only set when costs are projected.
+	projection *usage.Projection
+	// loadTime is the time taken to load the resources of the projects, the
+	// time taken to retrieve their prices is added to this once it's known.
+	loadTime time.Duration
 }
 
 type parallelRunner struct {
-	cmd         *cobra.Command
-	runCtx      *config.RunContext
-	pathMuxs    map[string]*sync.Mutex
-	prior       *output.Root
+	cmd      *cobra.Command
+	runCtx   *config.RunContext
+	pathMuxs map[string]*sync.Mutex
+	prior    *output.Root
+	// source is the pricing source the prices of the last run were retrieved
+	// from, it's reused to price the projected costs of the run.
+	source      prices.PricingSource
 	parallelism int
 	numJobs     int
 }
@@ -284,6 +344,7 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 					index:      job.index,
 					ctx:        ctx,
 					projectOut: configProjects,
+					errored:    err != nil,
 				}
 			}
 
@@ -312,9 +373,244 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 		return projectResults[i].index < projectResults[j].index
 	})
 
+	err = r.populatePrices(projectResults)
+	if err != nil {
+		return nil, err
+	}
+
 	return projectResults, nil
 }
 
+// runAtPriceDate runs the projects using the prices that were effective on
+// the given date. The output is used as the prior when diffing the projects
+// at two different price dates.
+func (r *parallelRunner) runAtPriceDate(date string) (*output.Root, error) {
+	priceDate := r.runCtx.Config.PriceDate
+	r.runCtx.Config.PriceDate = date
+	defer func() {
+		r.runCtx.Config.PriceDate = priceDate
+	}()
+
+	// Set an empty prior so the projects are run without their past resources,
+	// the same as they will be for the current run.
+	r.prior = &output.Root{}
+
+	projectResults, err := r.run()
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*schema.Project, 0)
+	for _, projectResult := range projectResults {
+		projects = append(projects, projectResult.projectOut.projects...)
+	}
+
+	prior, err := output.ToOutputFormat(projects)
+	if err != nil {
+		return nil, err
+	}
+
+	prior.Metadata = output.NewMetadata(r.runCtx)
+
+	return &prior, nil
+}
+
+// populatePrices gets the prices for the projects of all the results at once,
+// so that price queries shared between projects are only made once, and then
+// calculates the project costs. If the prices can't be retrieved they are
+// retrieved for each result separately, so only the projects whose prices
+// can't be retrieved are replaced with errored projects. An error is only
+// returned if the pricing source can't be created, or if strict pricing is
+// enabled and some of the prices were missing or ambiguous.
+func (r *parallelRunner) populatePrices(projectResults []projectResult) error {
+	t1 := time.Now()
+	defer func() {
+		r.setProjectRunTimes(projectResults, time.Since(t1))
+	}()
+
+	projects := nonErroredProjects(projectResults)
+	if len(projects) == 0 {
+		return nil
+	}
+
+	spinnerOpts := ui.SpinnerOptions{
+		EnableLogging: r.runCtx.Config.IsLogging(),
+		NoColor:       r.runCtx.Config.NoColor,
+		Indent:        "  ",
+	}
+	spinner := ui.NewSpinner("Retrieving cloud prices to calculate costs", spinnerOpts)
+	defer spinner.Fail()
+
+	source, err := prices.NewPricingSource(r.runCtx)
+	if err != nil {
+		spinner.Fail()
+		r.cmd.PrintErrln()
+		return err
+	}
+	r.source = source
+
+	failed := false
+	err = prices.PopulatePricesFrom(r.runCtx, r.source, projects...)
+	if err != nil {
+		var strictErr *prices.StrictPricingError
+		if !errors.As(err, &strictErr) {
+			failed, err = r.populateResultPrices(projectResults, err)
+		}
+
+		if err != nil {
+			spinner.Fail()
+			r.cmd.PrintErrln()
+			return err
+		}
+	}
+
+	if failed {
+		projects = nonErroredProjects(projectResults)
+	}
+
+	for _, project := range projects {
+		schema.CalculateCosts(project)
+
+		project.CalculateDiff()
+	}
+
+	if failed {
+		spinner.Fail()
+		r.cmd.PrintErrln()
+	} else {
+		spinner.Success()
+	}
+
+	if r.runCtx.Config.UsageActualCosts {
+		r.populateActualCosts(projects)
+	}
+
+	return nil
+}
+
+// projectCosts projects the monthly costs of the projects over the number of
+// months set by the --projection flag. The grown usage is priced from the
+// same pricing source as the run.
+func (r *parallelRunner) projectCosts(projectResults []projectResult) error {
+	spinnerOpts := ui.SpinnerOptions{
+		EnableLogging: r.runCtx.Config.IsLogging(),
+		NoColor:       r.runCtx.Config.NoColor,
+		Indent:        "  ",
+	}
+	spinner := ui.NewSpinner(fmt.Sprintf("Projecting costs over %d months", r.runCtx.Config.ProjectionMonths), spinnerOpts)
+	defer spinner.Fail()
+
+	now := time.Now()
+	for _, result := range projectResults {
+		if result.errored {
+			continue
+		}
+
+		for _, project := range result.projectOut.projects {
+			err := projection.ProjectCosts(r.runCtx, r.source, project, result.projectOut.projection, r.runCtx.Config.ProjectionMonths, now)
+			if err != nil {
+				var strictErr *prices.StrictPricingError
+				if errors.As(err, &strictErr) {
+					return err
+				}
+
+				return r.pricingError(err)
+			}
+		}
+	}
+
+	spinner.Success()
+
+	return nil
+}
+
+// populateResultPrices gets the prices for each result separately after
+// getting them for all the results at once failed with err. The results whose
+// prices can't be retrieved are replaced with errored projects and true is
+// returned if there were any. If the API key is invalid the prices can't be
+// retrieved for any of the results, so they are all replaced without trying
+// again.
+func (r *parallelRunner) populateResultPrices(projectResults []projectResult, err error) (bool, error) {
+	retry := !errors.Is(unwrapped(err), apiclient.ErrInvalidAPIKey)
+	if retry {
+		log.Debugf("Error retrieving prices for all projects, retrieving them for each project instead: %s", err)
+	}
+
+	failed := false
+	for i, result := range projectResults {
+		if result.errored {
+			continue
+		}
+
+		resultErr := err
+		if retry {
+			resultErr = prices.PopulatePricesFrom(r.runCtx, r.source, result.projectOut.projects...)
+
+			var strictErr *prices.StrictPricingError
+			if errors.As(resultErr, &strictErr) {
+				return failed, resultErr
+			}
+		}
+
+		if resultErr != nil {
+			projectResults[i].projectOut = newErroredProject(result.ctx, r.pricingError(resultErr))
+			projectResults[i].errored = true
+			failed = true
+		}
+	}
+
+	return failed, nil
+}
+
+// setProjectRunTimes sets the time taken to run the projects of the results
+// that weren't errored. This is the time taken to load their resources plus
+// the time taken to retrieve the prices, which is shared by all the results.
+func (r *parallelRunner) setProjectRunTimes(projectResults []projectResult, pricingTime time.Duration) {
+	for _, result := range projectResults {
+		if result.errored {
+			continue
+		}
+
+		result.ctx.SetContextValue("tfProjectRunTimeMs", (result.projectOut.loadTime + pricingTime).Milliseconds())
+	}
+}
+
+// nonErroredProjects returns the projects of the results that weren't errored.
+func nonErroredProjects(projectResults []projectResult) []*schema.Project {
+	projects := make([]*schema.Project, 0)
+	for _, result := range projectResults {
+		if !result.errored {
+			projects = append(projects, result.projectOut.projects...)
+		}
+	}
+
+	return projects
+}
+
+func (r *parallelRunner) pricingError(err error) error {
+	if e := unwrapped(err); errors.Is(e, apiclient.ErrInvalidAPIKey) {
+		return fmt.Errorf("%v\n%s %s %s %s %s\n%s %s.\n%s %s %s",
+			e.Error(),
+			"Please check your",
+			ui.PrimaryString(config.CredentialsFilePath()),
+			"file or",
+			ui.PrimaryString("INFRACOST_API_KEY"),
+			"environment variable.",
+			"If you recently regenerated your API key, you can retrieve it from",
+			ui.PrimaryString(r.runCtx.Config.DashboardEndpoint),
+			"See",
+			ui.PrimaryString("https://infracost.io/support"),
+			"if you continue having issues.",
+		)
+	}
+
+	if e, ok := err.(*apiclient.APIError); ok {
+		return fmt.Errorf("%v\n%s", e.Error(), "We have been notified of this issue.")
+	}
+
+	return err
+}
+
 func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectOutput, error) {
 	mux := r.pathMuxs[ctx.ProjectConfig.Path]
 	if mux != nil {
@@ -428,6 +724,14 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 	usageData := usageFile.ToUsageDataMap()
 	out := &projectOutput{}
 
+	if r.runCtx.Config.ProjectionMonths > 0 {
+		var err error
+		out.projection, err = usageFile.Projection()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	t1 := time.Now()
 	projects, err := provider.LoadResources(usageData)
 	if err != nil {
@@ -439,56 +743,7 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 
 	r.buildResources(projects)
 
-	spinnerOpts := ui.SpinnerOptions{
-		EnableLogging: r.runCtx.Config.IsLogging(),
-		NoColor:       r.runCtx.Config.NoColor,
-		Indent:        "  ",
-	}
-	spinner := ui.NewSpinner("Retrieving cloud prices to calculate costs", spinnerOpts)
-	defer spinner.Fail()
-
-	for _, project := range projects {
-		if err := prices.PopulatePrices(r.runCtx, project); err != nil {
-			spinner.Fail()
-			r.cmd.PrintErrln()
-
-			if e := unwrapped(err); errors.Is(e, apiclient.ErrInvalidAPIKey) {
-				return nil, fmt.Errorf("%v\n%s %s %s %s %s\n%s %s.\n%s %s %s",
-					e.Error(),
-					"Please check your",
-					ui.PrimaryString(config.CredentialsFilePath()),
-					"file or",
-					ui.PrimaryString("INFRACOST_API_KEY"),
-					"environment variable.",
-					"If you recently regenerated your API key, you can retrieve it from",
-					ui.PrimaryString(r.runCtx.Config.DashboardEndpoint),
-					"See",
-					ui.PrimaryString("https://infracost.io/support"),
-					"if you continue having issues.",
-				)
-			}
-
-			if e, ok := err.(*apiclient.APIError); ok {
-				return nil, fmt.Errorf("%v\n%s", e.Error(), "We have been notified of this issue.")
-			}
-
-			return nil, err
-		}
-		schema.CalculateCosts(project)
-
-		project.CalculateDiff()
-	}
-
-	t2 := time.Now()
-	taken := t2.Sub(t1).Milliseconds()
-	ctx.SetContextValue("tfProjectRunTimeMs", taken)
-
-	spinner.Success()
-
-	if r.runCtx.Config.UsageActualCosts {
-		r.populateActualCosts(projects)
-	}
-
+	out.loadTime = time.Since(t1)
 	out.projects = projects
 
 	if !r.runCtx.Config.IsLogging() && !r.runCtx.Config.SkipErrLine {
@@ -693,6 +948,20 @@ func (r *parallelRunner) generateUsageFile(ctx *config.ProjectContext, provider
 	return nil
 }
 
+// checkPricingConfig checks that prices can be retrieved for the run. Runs
+// using local pricing data never call the Pricing API so don't need an API key.
+func checkPricingConfig(cfg *config.Config, cmd *cobra.Command) error {
+	if cmd.Flags().Changed("pricing-data-path") {
+		cfg.PricingDataPath, _ = cmd.Flags().GetString("pricing-data-path")
+	}
+
+	if cfg.UsesLocalPricingData() {
+		return nil
+	}
+
+	return checkAPIKey(cfg.APIKey, cfg.PricingAPIEndpoint, cfg.DefaultPricingAPIEndpoint)
+}
+
 func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	hasPathFlag := cmd.Flags().Changed("path")
 	hasConfigFile := cmd.Flags().Changed("config-file")
@@ -721,12 +990,14 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		cmd.Flags().Changed("terraform-plan-flags") ||
 		cmd.Flags().Changed("terraform-var-file") ||
 		cmd.Flags().Changed("terraform-var") ||
+		cmd.Flags().Changed("cfn-parameters") ||
+		cmd.Flags().Changed("compare-template") ||
 		cmd.Flags().Changed("terraform-init-flags") ||
 		cmd.Flags().Changed("terraform-workspace"))
 
 	if hasConfigFile && hasProjectFlags {
 		m := "--config-file flag cannot be used with the following flags: "
-		m += "--path, --project-name, --terraform-*, --usage-file"
+		m += "--path, --project-name, --terraform-*, --cfn-parameters, --compare-template, --usage-file"
 		ui.PrintUsage(cmd)
 		return errors.New(m)
 	}
@@ -741,6 +1012,8 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		projectCfg.TerraformVarFiles, _ = cmd.Flags().GetStringSlice("terraform-var-file")
 		tfVars, _ := cmd.Flags().GetStringSlice("terraform-var")
 		projectCfg.TerraformVars = tfVarsToMap(tfVars)
+		projectCfg.CloudFormationParametersFile, _ = cmd.Flags().GetString("cfn-parameters")
+		projectCfg.CloudFormationCompareTemplate, _ = cmd.Flags().GetString("compare-template")
 		projectCfg.UsageFile, _ = cmd.Flags().GetString("usage-file")
 		projectCfg.Name, _ = cmd.Flags().GetString("project-name")
 		projectCfg.TerraformForceCLI, _ = cmd.Flags().GetBool("terraform-force-cli")
@@ -778,6 +1051,67 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	}
 
 	cfg.NoCache, _ = cmd.Flags().GetBool("no-cache")
+	if cmd.Flags().Changed("no-price-cache") {
+		cfg.NoPriceCache, _ = cmd.Flags().GetBool("no-price-cache")
+	}
+
+	if cmd.Flags().Changed("discounts-file") {
+		cfg.DiscountsFile, _ = cmd.Flags().GetString("discounts-file")
+	}
+
+	if cfg.DiscountsFile != "" {
+		// Load the file now so any errors in it are shown before the projects are run.
+		if _, err := discounts.LoadDiscountsFile(cfg.DiscountsFile); err != nil {
+			return err
+		}
+	}
+
+	if cmd.Flags().Changed("price-date") {
+		cfg.PriceDate, _ = cmd.Flags().GetString("price-date")
+	}
+
+	if cfg.PriceDate != "" {
+		if _, err := config.ParsePriceDate(cfg.PriceDate); err != nil {
+			return err
+		}
+	}
+
+	if cmd.Flags().Changed("exchange-rates") {
+		cfg.ExchangeRatesFile, _ = cmd.Flags().GetString("exchange-rates")
+	}
+
+	if err := cfg.LoadExchangeRates(); err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("strict-pricing") {
+		cfg.StrictPricing, _ = cmd.Flags().GetBool("strict-pricing")
+	}
+
+	cfg.IncludePriceHashes, _ = cmd.Flags().GetBool("include-price-hashes")
+
+	cfg.ComparePriceDate, _ = cmd.Flags().GetString("compare-price-date")
+	if cfg.ComparePriceDate != "" {
+		if cfg.CompareTo != "" {
+			ui.PrintUsage(cmd)
+			return errors.New("--compare-price-date flag cannot be used with the --compare-to flag")
+		}
+
+		if _, err := config.ParsePriceDate(cfg.ComparePriceDate); err != nil {
+			return err
+		}
+	}
+
+	if period, _ := cmd.Flags().GetString("projection"); period != "" {
+		months, err := config.ParseProjection(period)
+		if err != nil {
+			ui.PrintUsage(cmd)
+			return err
+		}
+
+		cfg.ProjectionMonths = months
+	}
+
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
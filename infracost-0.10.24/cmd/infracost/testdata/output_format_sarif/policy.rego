@@ -0,0 +1,33 @@
+package infracost
+
+deny[out] {
+	maxCost = 10.0
+
+	r := input.projects[_].breakdown.resources[_]
+	to_number(r.monthlyCost) >= maxCost
+
+	msg := sprintf(
+		"%s must cost less than $%.2f per month (actual cost is $%.2f)",
+		[r.name, maxCost, to_number(r.monthlyCost)],
+	)
+
+	out := {
+		"msg": msg,
+		"failed": true,
+		"address": r.name,
+	}
+}
+
+deny[out] {
+	maxCost = 10.0
+
+	msg := sprintf(
+		"Total monthly cost must be less than $%.2f (actual cost is $%.2f)",
+		[maxCost, to_number(input.totalMonthlyCost)],
+	)
+
+	out := {
+		"msg": msg,
+		"failed": to_number(input.totalMonthlyCost) >= maxCost,
+	}
+}
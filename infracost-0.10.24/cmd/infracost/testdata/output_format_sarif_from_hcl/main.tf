@@ -0,0 +1,21 @@
+provider "aws" {
+  region = "us-east-1"
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-674cbc1e"
+  instance_type = "t3.medium"
+
+  credit_specification {
+    cpu_credits = "standard"
+  }
+}
+
+resource "aws_instance" "worker" {
+  ami           = "ami-674cbc1e"
+  instance_type = "t3.medium"
+
+  credit_specification {
+    cpu_credits = "standard"
+  }
+}
@@ -0,0 +1,29 @@
+package infracost
+
+deny[out] {
+	maxDiff = 1500.0
+
+	msg := sprintf(
+		"Total monthly cost diff must be less than $%.2f (actual diff is $%.2f)",
+		[maxDiff, to_number(input.diffTotalMonthlyCost)],
+	)
+
+	out := {
+		"msg": msg,
+		"failed": to_number(input.diffTotalMonthlyCost) >= maxDiff,
+	}
+}
+
+deny[out] {
+	maxCost = 10.0
+
+	msg := sprintf(
+		"Total monthly cost must be less than $%.2f (actual cost is $%.2f)",
+		[maxCost, to_number(input.totalMonthlyCost)],
+	)
+
+	out := {
+		"msg": msg,
+		"failed": to_number(input.totalMonthlyCost) >= maxCost,
+	}
+}
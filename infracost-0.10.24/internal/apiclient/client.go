@@ -2,6 +2,7 @@ package apiclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -44,16 +45,26 @@ type APIErrorResponse struct {
 var ErrInvalidAPIKey = errors.New("Invalid API key")
 
 func (c *APIClient) doQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	return c.doQueriesWithContext(context.Background(), queries)
+}
+
+// doQueriesWithContext sends the queries in one GraphQL request, the request
+// is aborted if ctx is cancelled.
+func (c *APIClient) doQueriesWithContext(ctx context.Context, queries []GraphQLQuery) ([]gjson.Result, error) {
 	if len(queries) == 0 {
 		log.Debug("Skipping GraphQL request as no queries have been specified")
 		return []gjson.Result{}, nil
 	}
 
-	respBody, err := c.doRequest("POST", "/graphql", queries)
+	respBody, err := c.doRequestWithContext(ctx, "POST", "/graphql", queries)
 	return gjson.ParseBytes(respBody).Array(), err
 }
 
 func (c *APIClient) doRequest(method string, path string, d interface{}) ([]byte, error) {
+	return c.doRequestWithContext(context.Background(), method, path, d)
+}
+
+func (c *APIClient) doRequestWithContext(ctx context.Context, method string, path string, d interface{}) ([]byte, error) {
 	logging.Logger.Debugf("'%s' request to '%s' using trace_id: '%s'", method, path, c.uuid.String())
 
 	reqBody, err := json.Marshal(d)
@@ -61,7 +72,7 @@ func (c *APIClient) doRequest(method string, path string, d interface{}) ([]byte
 		return []byte{}, errors.Wrap(err, "Error generating request body")
 	}
 
-	req, err := http.NewRequest(method, c.endpoint+path, bytes.NewBuffer(reqBody))
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return []byte{}, errors.Wrap(err, "Error generating request")
 	}
@@ -1,6 +1,7 @@
 package apiclient
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -23,10 +24,40 @@ var (
 	}
 )
 
+// DefaultPricingSourceName is the name used to identify prices returned by the
+// Pricing API when no name has been configured for it.
+const DefaultPricingSourceName = "pricing_api"
+
 type PricingAPIClient struct {
 	APIClient
 	Currency       string
 	EventsDisabled bool
+	Fields         PriceQueryFields
+	name           string
+}
+
+// PriceQueryFields are the optional fields requested by price queries. They
+// are only needed by some features, so they aren't requested otherwise to
+// keep the responses small.
+type PriceQueryFields struct {
+	// Candidates are the sku and attributes of the products and the unit of
+	// the prices, which are recorded as the price candidates. The sku is also
+	// recorded with the price hash so the price can be looked up again.
+	Candidates bool `json:"candidates,omitempty"`
+	// EffectiveDates are the effectiveDateStart and startUsageAmount of the
+	// prices, which are used to find the prices effective on the price date.
+	EffectiveDates bool `json:"effectiveDates,omitempty"`
+}
+
+// NewPriceQueryFields returns the optional fields needed by the features
+// enabled in the config.
+func NewPriceQueryFields(cfg *config.Config) PriceQueryFields {
+	_, hasPriceDate := cfg.EffectivePriceDate()
+
+	return PriceQueryFields{
+		Candidates:     cfg.RecordsPriceCandidates(),
+		EffectiveDates: hasPriceDate,
+	}
 }
 
 type PriceQueryKey struct {
@@ -37,14 +68,38 @@ type PriceQueryKey struct {
 type PriceQueryResult struct {
 	PriceQueryKey
 	Result gjson.Result
+	// Source is the name of the pricing source that returned the result.
+	Source string
 }
 
-func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
-	currency := ctx.Config.Currency
-	if currency == "" {
-		currency = "USD"
+// PriceQueryKeys returns the keys for all the cost components of the resource
+// and its sub-resources, these can be used to query the prices for the resource.
+func PriceQueryKeys(r *schema.Resource) []PriceQueryKey {
+	keys := make([]PriceQueryKey, 0)
+
+	for _, component := range r.CostComponents {
+		keys = append(keys, PriceQueryKey{r, component})
 	}
 
+	for _, subresource := range r.FlattenedSubResources() {
+		for _, component := range subresource.CostComponents {
+			keys = append(keys, PriceQueryKey{subresource, component})
+		}
+	}
+
+	return keys
+}
+
+// NewPricingAPIClient returns a client for the Pricing API endpoint and API
+// key set in the config.
+func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
+	return NewNamedPricingAPIClient(ctx, DefaultPricingSourceName, ctx.Config.PricingAPIEndpoint, ctx.Config.APIKey)
+}
+
+// NewNamedPricingAPIClient returns a client for the given Pricing API endpoint.
+// The name is recorded against all the prices returned by the client so that
+// it can be distinguished from other pricing sources.
+func NewNamedPricingAPIClient(ctx *config.RunContext, name, endpoint, apiKey string) *PricingAPIClient {
 	tlsConfig := tls.Config{} // nolint: gosec
 
 	if ctx.Config.TLSCACertFile != "" {
@@ -80,12 +135,15 @@ func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
 	return &PricingAPIClient{
 		APIClient: APIClient{
 			httpClient: client.StandardClient(),
-			endpoint:   ctx.Config.PricingAPIEndpoint,
-			apiKey:     ctx.Config.APIKey,
+			endpoint:   endpoint,
+			apiKey:     apiKey,
 			uuid:       ctx.UUID(),
 		},
-		Currency:       currency,
-		EventsDisabled: ctx.Config.EventsDisabled,
+		Currency: ctx.Config.PricingCurrency(),
+		// Runs using local pricing data are expected to have no network access.
+		EventsDisabled: ctx.Config.EventsDisabled || ctx.Config.UsesLocalPricingData(),
+		Fields:         NewPriceQueryFields(ctx.Config),
+		name:           name,
 	}
 }
 
@@ -112,17 +170,27 @@ func (c *PricingAPIClient) AddEvent(name string, env map[string]interface{}) err
 	return err
 }
 
-func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, error) {
-	keys, queries := c.batchQueries(r)
+// Name returns the name of the client as a pricing source.
+func (c *PricingAPIClient) Name() string {
+	return c.name
+}
+
+// Endpoint returns the Pricing API endpoint the client queries.
+func (c *PricingAPIClient) Endpoint() string {
+	return c.endpoint
+}
 
-	if len(queries) == 0 {
-		log.Debugf("Skipping getting pricing details for %s since there are no queries to run", r.Name)
-		return []PriceQueryResult{}, nil
+// QueryPrices batches the queries for all the given keys so they can be run
+// using one GraphQL call. The results are returned in the same order as the keys.
+func (c *PricingAPIClient) QueryPrices(ctx context.Context, keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	queries := make([]GraphQLQuery, 0, len(keys))
+	for _, k := range keys {
+		queries = append(queries, c.buildQuery(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter))
 	}
 
-	log.Debugf("Getting pricing details from %s for %s", c.endpoint, r.Name)
+	log.Debugf("Getting pricing details for %d cost components from %s", len(keys), c.endpoint)
 
-	results, err := c.doQueries(queries)
+	results, err := c.doQueriesWithContext(ctx, queries)
 	if err != nil {
 		return []PriceQueryResult{}, err
 	}
@@ -135,41 +203,38 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 	v["productFilter"] = product
 	v["priceFilter"] = price
 
+	var productFields, priceFields string
+	if c.Fields.Candidates {
+		productFields = `
+				sku
+				attributes {
+					key
+					value
+				}`
+		priceFields += `
+					unit`
+	}
+
+	if c.Fields.EffectiveDates {
+		priceFields += `
+					startUsageAmount
+					effectiveDateStart`
+	}
+
 	query := fmt.Sprintf(`
 		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
-			products(filter: $productFilter) {
+			products(filter: $productFilter) {%s
 				prices(filter: $priceFilter) {
-					priceHash
+					priceHash%s
 					%s
 				}
 			}
 		}
-	`, c.Currency)
+	`, productFields, priceFields, c.Currency)
 
 	return GraphQLQuery{query, v}
 }
 
-// Batch all the queries for this resource so we can use one GraphQL call.
-// Use PriceQueryKeys to keep track of which query maps to which sub-resource and price component.
-func (c *PricingAPIClient) batchQueries(r *schema.Resource) ([]PriceQueryKey, []GraphQLQuery) {
-	keys := make([]PriceQueryKey, 0)
-	queries := make([]GraphQLQuery, 0)
-
-	for _, component := range r.CostComponents {
-		keys = append(keys, PriceQueryKey{r, component})
-		queries = append(queries, c.buildQuery(component.ProductFilter, component.PriceFilter))
-	}
-
-	for _, subresource := range r.FlattenedSubResources() {
-		for _, component := range subresource.CostComponents {
-			keys = append(keys, PriceQueryKey{subresource, component})
-			queries = append(queries, c.buildQuery(component.ProductFilter, component.PriceFilter))
-		}
-	}
-
-	return keys, queries
-}
-
 func (c *PricingAPIClient) zipQueryResults(k []PriceQueryKey, r []gjson.Result) []PriceQueryResult {
 	res := make([]PriceQueryResult, 0, len(k))
 
@@ -177,6 +242,7 @@ func (c *PricingAPIClient) zipQueryResults(k []PriceQueryKey, r []gjson.Result)
 		res = append(res, PriceQueryResult{
 			PriceQueryKey: k,
 			Result:        r[i],
+			Source:        c.name,
 		})
 	}
 
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/joho/godotenv"
@@ -13,11 +16,18 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/infracost/infracost/internal/currency"
 	"github.com/infracost/infracost/internal/logging"
 )
 
 const InfracostDir = ".infracost"
 
+// DefaultPriceCacheTTL is how long prices are cached for if no TTL is configured.
+const DefaultPriceCacheTTL = 24 * time.Hour
+
+// PriceDateFormat is the format of the dates that cost components can be priced at.
+const PriceDateFormat = "2006-01-02"
+
 // Project defines a specific terraform project config. This can be used
 // specify per folder/project configurations so that users don't have
 // to provide flags every run. Fields are documented below. More info
@@ -41,6 +51,17 @@ type Project struct {
 	TerraformVarFiles []string `yaml:"terraform_var_files,omitempty"`
 	// TerraformVars is a slice of input vars that are to be used with the project.
 	TerraformVars map[string]string `yaml:"terraform_vars,omitempty"`
+	// CloudFormationParametersFile is the path to a JSON or YAML file of parameter values for a CloudFormation template.
+	CloudFormationParametersFile string `yaml:"cfn_parameters_file,omitempty" ignored:"true"`
+	// CloudFormationParameters are parameter values for a CloudFormation template, these override the values
+	// in CloudFormationParametersFile. Pseudo parameters such as AWS::Region can also be set.
+	CloudFormationParameters map[string]string `yaml:"cfn_parameters,omitempty" ignored:"true"`
+	// CloudFormationCompareTemplate is the path to a CloudFormation template that the past resources are parsed from,
+	// e.g. the deployed template. It is required when the path is a CloudFormation change set.
+	CloudFormationCompareTemplate string `yaml:"cfn_compare_template,omitempty" ignored:"true"`
+	// CloudFormationStackTags are tags that are applied to all the resources of a CloudFormation stack, the same as
+	// the --tags of `aws cloudformation deploy`. Tags set on a resource override them.
+	CloudFormationStackTags map[string]string `yaml:"cfn_stack_tags,omitempty" ignored:"true"`
 	// TerraformForceCLI will run a project by calling out to the terraform/terragrunt binary to generate a plan JSON file.
 	TerraformForceCLI bool `yaml:"terraform_force_cli,omitempty"`
 	// TerraformPlanFlags are flags to pass to terraform plan with Terraform directory paths
@@ -66,6 +87,29 @@ type Project struct {
 	Env               map[string]string `yaml:"env,omitempty" ignored:"true"`
 }
 
+const (
+	// PricingSourceTypeAPI is a pricing source that queries a Pricing API endpoint.
+	PricingSourceTypeAPI = "pricing_api"
+	// PricingSourceTypeLocal is a pricing source that looks up prices from local pricing data.
+	PricingSourceTypeLocal = "local"
+)
+
+// PricingSource defines a source that prices are looked up from. Sources are
+// queried in the order they are defined, and any cost components whose prices
+// aren't found in a source are looked up in the next one.
+type PricingSource struct {
+	// Name identifies the source in the output. Defaults to the type of the source.
+	Name string `yaml:"name,omitempty"`
+	// Type is the type of the source, either pricing_api or local.
+	Type string `yaml:"type"`
+	// Endpoint is the Pricing API endpoint for pricing_api sources. Defaults to the pricing_api_endpoint.
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// APIKey is the API key used for pricing_api sources. Defaults to the INFRACOST_API_KEY.
+	APIKey string `yaml:"api_key,omitempty"`
+	// Path is the path to the pricing data for local sources, see pricing_data_path for the supported formats.
+	Path string `yaml:"path,omitempty"`
+}
+
 type Config struct {
 	Credentials   Credentials
 	Configuration Configuration
@@ -91,6 +135,29 @@ type Config struct {
 	EnableCloudUpload         *bool  `yaml:"enable_cloud,omitempty" envconfig:"ENABLE_CLOUD_UPLOAD"`
 	DisableHCLParsing         bool   `yaml:"disable_hcl_parsing,omitempty" envconfig:"DISABLE_HCL_PARSING"`
 
+	// PricingDataPath is the path to a local snapshot of the Cloud Pricing API products table. This can either
+	// be a single CSV (optionally gzipped) or SQL dump file, or a directory containing these. When set, prices
+	// are looked up from the snapshot and the Pricing API is never called.
+	PricingDataPath string `yaml:"pricing_data_path,omitempty" envconfig:"PRICING_DATA_PATH"`
+	// PricingSources overrides where prices are looked up from, see PricingSource.
+	PricingSources []*PricingSource `yaml:"pricing_sources,omitempty" ignored:"true"`
+	// NoPriceCache disables the on-disk cache of Pricing API responses.
+	NoPriceCache bool `yaml:"no_price_cache,omitempty" envconfig:"NO_PRICE_CACHE"`
+	// PriceCacheTTL is how long cached Pricing API responses are used for, defaults to DefaultPriceCacheTTL.
+	PriceCacheTTL time.Duration `yaml:"price_cache_ttl,omitempty" envconfig:"PRICE_CACHE_TTL"`
+	// DiscountsFile is the path to a file of discount rules that are applied to the list prices.
+	DiscountsFile string `yaml:"discounts_file,omitempty" envconfig:"DISCOUNTS_FILE"`
+	// PriceDate prices the cost components using the prices that were effective on this date, in the
+	// PriceDateFormat, instead of the current prices.
+	PriceDate string `yaml:"price_date,omitempty" envconfig:"PRICE_DATE"`
+	// ExchangeRatesFile is the path to a table of exchange rates. When set, prices are fetched in the
+	// currency.BaseCurrency and converted to the Currency using these rates.
+	ExchangeRatesFile string                  `yaml:"exchange_rates_file,omitempty" envconfig:"EXCHANGE_RATES_FILE"`
+	ExchangeRates     *currency.ExchangeRates `yaml:"-" ignored:"true"`
+	// StrictPricing fails the run if the price lookup of any cost component finds no prices or more
+	// than one price, instead of using 0.00 or the first price.
+	StrictPricing bool `yaml:"strict_pricing,omitempty" envconfig:"STRICT_PRICING"`
+
 	TLSInsecureSkipVerify *bool  `envconfig:"TLS_INSECURE_SKIP_VERIFY"`
 	TLSCACertFile         string `envconfig:"TLS_CA_CERT_FILE"`
 
@@ -111,10 +178,19 @@ type Config struct {
 	Format          string     `yaml:"format,omitempty" ignored:"true"`
 	ShowAllProjects bool       `yaml:"show_all_projects,omitempty" ignored:"true"`
 	ShowSkipped     bool       `yaml:"show_skipped,omitempty" ignored:"true"`
-	SyncUsageFile   bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
-	Fields          []string   `yaml:"fields,omitempty" ignored:"true"`
-	CompareTo       string
-	GitDiffTarget   *string
+	// IncludePriceHashes includes the price hash, pricing source and price candidates of the cost
+	// components in the output.
+	IncludePriceHashes bool     `yaml:"include_price_hashes,omitempty" ignored:"true"`
+	SyncUsageFile      bool     `yaml:"sync_usage_file,omitempty" ignored:"true"`
+	Fields             []string `yaml:"fields,omitempty" ignored:"true"`
+	CompareTo          string
+	// ComparePriceDate is the price date used to price the past breakdown when diffing the same
+	// projects at two different price dates.
+	ComparePriceDate string
+	// ProjectionMonths is the number of months to project the monthly costs of
+	// the projects over. No projection is made if this is 0.
+	ProjectionMonths int
+	GitDiffTarget    *string
 
 	// Base configuration settings
 	// RootPath defines the raw value of the `--path` flag provided by the user
@@ -191,6 +267,120 @@ func (c *Config) CachePath() string {
 	return dir
 }
 
+// PriceCachePath returns the directory that Pricing API responses are cached
+// in. This is inside the .infracost directory found by CachePath.
+func (c *Config) PriceCachePath() string {
+	return filepath.Join(c.CachePath(), InfracostDir, "price-cache")
+}
+
+// PriceCacheMaxAge returns how long cached Pricing API responses are valid for.
+func (c *Config) PriceCacheMaxAge() time.Duration {
+	if c.PriceCacheTTL <= 0 {
+		return DefaultPriceCacheTTL
+	}
+
+	return c.PriceCacheTTL
+}
+
+// LoadExchangeRates loads the ExchangeRatesFile if one is set, and checks it
+// has a rate for the Currency.
+func (c *Config) LoadExchangeRates() error {
+	if c.ExchangeRatesFile == "" {
+		c.ExchangeRates = nil
+		return nil
+	}
+
+	rates, err := currency.LoadExchangeRates(c.ExchangeRatesFile)
+	if err != nil {
+		return err
+	}
+
+	if _, err := rates.Rate(c.Currency); err != nil {
+		return err
+	}
+
+	c.ExchangeRates = rates
+	return nil
+}
+
+// PricingCurrency returns the currency that prices are fetched in. When
+// exchange rates are set this is the currency.BaseCurrency, since the prices
+// are converted locally.
+func (c *Config) PricingCurrency() string {
+	if c.ExchangeRates != nil || c.Currency == "" {
+		return currency.BaseCurrency
+	}
+
+	return c.Currency
+}
+
+// RecordsPriceCandidates returns true if the products and prices found by a
+// price lookup are recorded as candidates when there is more than one. These
+// are reported by strict pricing and included in the JSON output with the
+// price hashes.
+func (c *Config) RecordsPriceCandidates() bool {
+	return c.StrictPricing || c.IncludePriceHashes
+}
+
+// EffectivePriceDate returns the PriceDate parsed as a time, or false if no
+// price date is set.
+func (c *Config) EffectivePriceDate() (time.Time, bool) {
+	if c.PriceDate == "" {
+		return time.Time{}, false
+	}
+
+	t, err := ParsePriceDate(c.PriceDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// ParsePriceDate parses a date in the PriceDateFormat.
+func ParsePriceDate(s string) (time.Time, error) {
+	t, err := time.Parse(PriceDateFormat, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid price date %s, expected the format YYYY-MM-DD", s)
+	}
+
+	return t, nil
+}
+
+// maxProjectionMonths is the longest projection that can be made, which is
+// ten years.
+const maxProjectionMonths = 120
+
+// ParseProjection parses a projection period, e.g. 12m or 3y, and returns the
+// number of months.
+func ParseProjection(s string) (int, error) {
+	invalid := fmt.Errorf("Invalid projection %s, expected a number of months or years such as 12m or 3y", s)
+
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return 0, invalid
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, invalid
+	}
+
+	switch s[len(s)-1] {
+	case 'm':
+	case 'y':
+		n *= 12
+	default:
+		return 0, invalid
+	}
+
+	if n > maxProjectionMonths {
+		return 0, fmt.Errorf("Invalid projection %s, the maximum projection is %d months", s, maxProjectionMonths)
+	}
+
+	return n, nil
+}
+
 func (c *Config) cachePath(dir string) string {
 	for {
 		cachePath := filepath.Join(dir, InfracostDir)
@@ -215,6 +405,10 @@ func (c *Config) LoadFromConfigFile(path string, cmd *cobra.Command) error {
 	}
 
 	c.Projects = cfgFile.Projects
+	c.PricingSources = cfgFile.PricingSources
+	if cfgFile.DiscountsFile != "" {
+		c.DiscountsFile = cfgFile.DiscountsFile
+	}
 
 	// Reload the environment and global flags to overwrite any of the config file configs
 	err = c.LoadFromEnv()
@@ -380,6 +574,23 @@ func (c *Config) IsLogging() bool {
 	return c.LogLevel != ""
 }
 
+// UsesLocalPricingData returns true if prices should only be looked up from
+// local snapshots of the products table rather than the Pricing API, i.e. the
+// PricingDataPath is set or all the PricingSources are local.
+func (c *Config) UsesLocalPricingData() bool {
+	if len(c.PricingSources) == 0 {
+		return c.PricingDataPath != ""
+	}
+
+	for _, s := range c.PricingSources {
+		if s.Type != PricingSourceTypeLocal {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (c *Config) IsSelfHosted() bool {
 	return c.PricingAPIEndpoint != "" && c.PricingAPIEndpoint != c.DefaultPricingAPIEndpoint
 }
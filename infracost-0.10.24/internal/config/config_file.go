@@ -106,8 +106,10 @@ func (y *YamlError) Error() string {
 }
 
 type ConfigFileSpec struct {
-	Version  string     `yaml:"version"`
-	Projects []*Project `yaml:"projects" ignored:"true"`
+	Version        string           `yaml:"version"`
+	Projects       []*Project       `yaml:"projects" ignored:"true"`
+	PricingSources []*PricingSource `yaml:"pricing_sources,omitempty" ignored:"true"`
+	DiscountsFile  string           `yaml:"discounts_file,omitempty" ignored:"true"`
 }
 
 // UnmarshalYAML implements the yaml.v2.Unmarshaller interface. Marshalls the
@@ -194,11 +196,48 @@ func (f *ConfigFileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error
 		return &YamlError{raw: ErrorInvalidConfigFile}
 	}
 
+	sourcesError := &YamlError{
+		base: "config file is invalid, see https://infracost.io/config-file for valid options",
+	}
+
+	for i, source := range c.PricingSources {
+		if err := validatePricingSource(source); err != nil {
+			sourcesError.add(&YamlError{
+				base:   fmt.Sprintf("pricing source at index %d was invalid", i),
+				errors: []error{err},
+			})
+		}
+	}
+
+	if sourcesError.isValid() {
+		return sourcesError
+	}
+
 	f.Version = c.Version
 	f.Projects = c.Projects
+	f.PricingSources = c.PricingSources
+	f.DiscountsFile = c.DiscountsFile
 	return nil
 }
 
+func validatePricingSource(source *PricingSource) error {
+	if source == nil {
+		return fmt.Errorf("pricing source must not be empty")
+	}
+
+	switch source.Type {
+	case PricingSourceTypeAPI:
+		return nil
+	case PricingSourceTypeLocal:
+		if source.Path == "" {
+			return fmt.Errorf("local pricing source must have a path")
+		}
+		return nil
+	default:
+		return fmt.Errorf("type '%s' is not valid, valid types are %s, %s", source.Type, PricingSourceTypeAPI, PricingSourceTypeLocal)
+	}
+}
+
 func LoadConfigFile(path string) (ConfigFileSpec, error) {
 	var cfgFile ConfigFileSpec
 
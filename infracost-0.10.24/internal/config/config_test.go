@@ -132,6 +132,89 @@ projects:
 	}
 }
 
+func TestConfigLoadFromConfigFilePricingSources(t *testing.T) {
+	tmp := t.TempDir()
+	tests := []struct {
+		name     string
+		contents []byte
+		expected []*PricingSource
+		error    error
+	}{
+		{
+			name: "should parse valid pricing sources",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+pricing_sources:
+  - name: negotiated
+    type: local
+    path: path/to/negotiated.csv
+  - name: self_hosted
+    type: pricing_api
+    endpoint: http://localhost:4000
+    api_key: self-hosted-key
+  - type: pricing_api
+`),
+			expected: []*PricingSource{
+				{Name: "negotiated", Type: PricingSourceTypeLocal, Path: "path/to/negotiated.csv"},
+				{Name: "self_hosted", Type: PricingSourceTypeAPI, Endpoint: "http://localhost:4000", APIKey: "self-hosted-key"},
+				{Type: PricingSourceTypeAPI},
+			},
+		},
+		{
+			name: "should error invalid pricing sources",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+pricing_sources:
+  - type: local
+  - type: other
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					&YamlError{
+						base:   "pricing source at index 0 was invalid",
+						errors: []error{errors.New("local pricing source must have a path")},
+					},
+					&YamlError{
+						base:   "pricing source at index 1 was invalid",
+						errors: []error{errors.New("type 'other' is not valid, valid types are pricing_api, local")},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			path := filepath.Join(tmp, fmt.Sprintf("conf-%d.yaml", i))
+			err := os.WriteFile(path, tt.contents, os.ModePerm)
+			require.NoError(t, err)
+
+			err = c.LoadFromConfigFile(path, &cobra.Command{})
+
+			require.Equal(t, tt.error, err)
+			require.EqualValues(t, tt.expected, c.PricingSources)
+		})
+	}
+}
+
+func TestConfigUsesLocalPricingData(t *testing.T) {
+	local := &PricingSource{Type: PricingSourceTypeLocal, Path: "prices.csv"}
+	api := &PricingSource{Type: PricingSourceTypeAPI}
+
+	assert.False(t, (&Config{}).UsesLocalPricingData())
+	assert.True(t, (&Config{PricingDataPath: "prices.csv"}).UsesLocalPricingData())
+	assert.True(t, (&Config{PricingSources: []*PricingSource{local, local}}).UsesLocalPricingData())
+	assert.False(t, (&Config{PricingSources: []*PricingSource{local, api}}).UsesLocalPricingData())
+}
+
 func TestConfig_CachePath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -170,3 +253,34 @@ func TestConfig_CachePath(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProjection(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		err      bool
+	}{
+		{input: "12m", expected: 12},
+		{input: "3y", expected: 36},
+		{input: " 6M ", expected: 6},
+		{input: "10y", expected: 120},
+		{input: "11y", err: true},
+		{input: "0m", err: true},
+		{input: "12", err: true},
+		{input: "12w", err: true},
+		{input: "m", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := ParseProjection(tt.input)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -125,8 +125,13 @@ func (r *RunContext) GetParallelism() (int, error) {
 	return parallelism, nil
 }
 
-// Context returns the underlying context.
+// Context returns the underlying context. This is context.Background if the
+// RunContext wasn't created with a context.
 func (r *RunContext) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+
 	return r.ctx
 }
 
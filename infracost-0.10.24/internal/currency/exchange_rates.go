@@ -0,0 +1,161 @@
+// Package currency converts costs between currencies using a user-supplied
+// table of exchange rates, rather than relying on the Pricing API having
+// prices in the output currency.
+package currency
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/mod/semver"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// BaseCurrency is the currency that prices are fetched in when converting
+// them with exchange rates. All the rates in an exchange rates file are
+// relative to it.
+const BaseCurrency = "USD"
+
+const minExchangeRatesFileVersion = "0.1"
+const maxExchangeRatesFileVersion = "0.1"
+
+// ExchangeRates is a table of exchange rates, usually published for a period
+// such as a month. Each rate is the amount of the currency that one unit of
+// the BaseCurrency buys, e.g. a rate of 0.92 for EUR means 1 USD = 0.92 EUR.
+type ExchangeRates struct {
+	Version string `yaml:"version"`
+	// Date is the date the rates were published or are effective from, in the
+	// format YYYY-MM-DD.
+	Date  string            `yaml:"date,omitempty"`
+	Rates map[string]string `yaml:"rates"`
+
+	rates map[string]decimal.Decimal
+}
+
+// LoadExchangeRates reads and validates the exchange rates file at path.
+func LoadExchangeRates(path string) (*ExchangeRates, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading exchange rates file")
+	}
+
+	r, err := LoadExchangeRatesFromString(string(contents))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading exchange rates file")
+	}
+
+	return r, nil
+}
+
+// LoadExchangeRatesFromString parses and validates the exchange rates file
+// contents.
+func LoadExchangeRatesFromString(s string) (*ExchangeRates, error) {
+	r := &ExchangeRates{}
+
+	err := yamlv3.Unmarshal([]byte(s), r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing exchange rates YAML")
+	}
+
+	if !r.checkVersion() {
+		return nil, fmt.Errorf("Invalid exchange rates file version. Supported versions are %s ≤ x ≤ %s", minExchangeRatesFileVersion, maxExchangeRatesFileVersion)
+	}
+
+	if r.Date != "" {
+		if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+			return nil, fmt.Errorf("Invalid date %s, expected the format YYYY-MM-DD", r.Date)
+		}
+	}
+
+	r.rates = make(map[string]decimal.Decimal, len(r.Rates))
+	for code, v := range r.Rates {
+		code = strings.ToUpper(code)
+		if money.GetCurrency(code) == nil {
+			return nil, fmt.Errorf("Invalid currency %s", code)
+		}
+
+		rate, err := decimal.NewFromString(v)
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("Invalid rate %s for %s, rates must be positive numbers", v, code)
+		}
+
+		r.rates[code] = rate
+	}
+
+	if rate, ok := r.rates[BaseCurrency]; ok && !rate.Equal(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("Invalid rate %s for %s, the rate for the base currency must be 1", rate, BaseCurrency)
+	}
+
+	return r, nil
+}
+
+func (r *ExchangeRates) checkVersion() bool {
+	v := r.Version
+	if !strings.HasPrefix(r.Version, "v") {
+		v = "v" + r.Version
+	}
+	return semver.Compare(v, "v"+minExchangeRatesFileVersion) >= 0 && semver.Compare(v, "v"+maxExchangeRatesFileVersion) <= 0
+}
+
+// Rate returns the amount of the currency that one unit of the BaseCurrency
+// buys.
+func (r *ExchangeRates) Rate(currency string) (decimal.Decimal, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == BaseCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rate, ok := r.rates[currency]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("No exchange rate for %s, the exchange rates file has rates for %s", currency, strings.Join(r.currencies(), ", "))
+	}
+
+	return rate, nil
+}
+
+// ConversionRate returns the rate to multiply amounts in the from currency by
+// to convert them to the to currency.
+func (r *ExchangeRates) ConversionRate(from, to string) (decimal.Decimal, error) {
+	fromRate, err := r.Rate(from)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	toRate, err := r.Rate(to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return toRate.Div(fromRate), nil
+}
+
+// Convert converts the amount from one currency to another.
+func (r *ExchangeRates) Convert(d decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	fromRate, err := r.Rate(from)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	toRate, err := r.Rate(to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return d.Mul(toRate).Div(fromRate), nil
+}
+
+func (r *ExchangeRates) currencies() []string {
+	currencies := make([]string, 0, len(r.rates))
+	for code := range r.rates {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+
+	return currencies
+}
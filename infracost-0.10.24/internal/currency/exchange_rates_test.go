@@ -0,0 +1,86 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExchangeRatesFromString(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{
+			name:     "valid",
+			contents: "version: 0.1\ndate: 2024-01-31\nrates:\n  EUR: 0.92\n  gbp: 0.79\n",
+		},
+		{
+			name:     "unsupported version",
+			contents: "version: 0.2\nrates:\n  EUR: 0.92\n",
+			err:      "Invalid exchange rates file version. Supported versions are 0.1 ≤ x ≤ 0.1",
+		},
+		{
+			name:     "invalid date",
+			contents: "version: 0.1\ndate: 31/01/2024\nrates:\n  EUR: 0.92\n",
+			err:      "Invalid date 31/01/2024, expected the format YYYY-MM-DD",
+		},
+		{
+			name:     "unknown currency",
+			contents: "version: 0.1\nrates:\n  XYZ: 0.92\n",
+			err:      "Invalid currency XYZ",
+		},
+		{
+			name:     "negative rate",
+			contents: "version: 0.1\nrates:\n  EUR: -1\n",
+			err:      "Invalid rate -1 for EUR, rates must be positive numbers",
+		},
+		{
+			name:     "base currency rate",
+			contents: "version: 0.1\nrates:\n  USD: 2\n",
+			err:      "Invalid rate 2 for USD, the rate for the base currency must be 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadExchangeRatesFromString(tt.contents)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	rates, err := LoadExchangeRatesFromString("version: 0.1\nrates:\n  EUR: 0.92\n  GBP: 0.79\n")
+	require.NoError(t, err)
+
+	tests := []struct {
+		from     string
+		to       string
+		amount   string
+		expected string
+	}{
+		{"USD", "EUR", "100", "92"},
+		{"EUR", "USD", "92", "100"},
+		{"EUR", "GBP", "0.184", "0.158"},
+		{"USD", "USD", "1.5", "1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"-"+tt.to, func(t *testing.T) {
+			v, err := rates.Convert(decimal.RequireFromString(tt.amount), tt.from, tt.to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v.String())
+		})
+	}
+
+	_, err = rates.Convert(decimal.NewFromInt(1), "USD", "JPY")
+	assert.EqualError(t, err, "No exchange rate for JPY, the exchange rates file has rates for EUR, GBP")
+}
@@ -0,0 +1,220 @@
+// Package discounts applies negotiated rates, such as enterprise discount
+// programs, to the list prices returned by the pricing sources.
+package discounts
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/mod/semver"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const minDiscountsFileVersion = "0.1"
+const maxDiscountsFileVersion = "0.1"
+
+// DiscountsFile is a list of rules that change the prices of any cost
+// components they match. The rules are checked in order and only the first
+// matching rule is applied to each cost component.
+type DiscountsFile struct {
+	Version   string  `yaml:"version"`
+	Discounts []*Rule `yaml:"discounts"`
+}
+
+// Rule discounts the price of the cost components it matches, either by a
+// percentage of the list price or by replacing it with a negotiated unit price.
+type Rule struct {
+	Name  string `yaml:"name"`
+	Match Match  `yaml:"match"`
+	// Percent is the percentage off the list price, e.g. 9 for a 9% discount.
+	Percent *float64 `yaml:"percent,omitempty"`
+	// UnitPrice is the price per unit to use instead of the list price.
+	UnitPrice *float64 `yaml:"unit_price,omitempty"`
+
+	// patterns holds the compiled Match fields in the order they are checked.
+	patterns []*regexp.Regexp
+}
+
+// Match specifies which cost components a Rule applies to. Every field that
+// is set must match, empty fields match anything. Values are glob patterns
+// where * matches any characters and ? matches a single character, e.g.
+// "Instance usage*".
+type Match struct {
+	VendorName    string `yaml:"vendor_name,omitempty"`
+	Service       string `yaml:"service,omitempty"`
+	ProductFamily string `yaml:"product_family,omitempty"`
+	Region        string `yaml:"region,omitempty"`
+	ResourceType  string `yaml:"resource_type,omitempty"`
+	CostComponent string `yaml:"cost_component,omitempty"`
+}
+
+// LoadDiscountsFile reads and validates the discounts file at path.
+func LoadDiscountsFile(path string) (*DiscountsFile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading discounts file")
+	}
+
+	f, err := LoadDiscountsFileFromString(string(contents))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading discounts file")
+	}
+
+	return f, nil
+}
+
+// LoadDiscountsFileFromString parses and validates the discounts file contents.
+func LoadDiscountsFileFromString(s string) (*DiscountsFile, error) {
+	f := &DiscountsFile{}
+
+	err := yamlv3.Unmarshal([]byte(s), f)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing discounts YAML")
+	}
+
+	if !f.checkVersion() {
+		return nil, fmt.Errorf("Invalid discounts file version. Supported versions are %s ≤ x ≤ %s", minDiscountsFileVersion, maxDiscountsFileVersion)
+	}
+
+	for i, r := range f.Discounts {
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("Invalid discount at index %d: %w", i, err)
+		}
+	}
+
+	return f, nil
+}
+
+func (f *DiscountsFile) checkVersion() bool {
+	v := f.Version
+	if !strings.HasPrefix(f.Version, "v") {
+		v = "v" + f.Version
+	}
+	return semver.Compare(v, "v"+minDiscountsFileVersion) >= 0 && semver.Compare(v, "v"+maxDiscountsFileVersion) <= 0
+}
+
+// compile validates the rule and compiles its match patterns.
+func (r *Rule) compile() error {
+	if r == nil {
+		return errors.New("discount must not be empty")
+	}
+
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if (r.Percent == nil) == (r.UnitPrice == nil) {
+		return fmt.Errorf("%s must set exactly one of percent or unit_price", r.Name)
+	}
+
+	if r.Percent != nil && (*r.Percent < 0 || *r.Percent > 100) {
+		return fmt.Errorf("%s percent must be between 0 and 100", r.Name)
+	}
+
+	if r.UnitPrice != nil && *r.UnitPrice < 0 {
+		return fmt.Errorf("%s unit_price must not be negative", r.Name)
+	}
+
+	r.patterns = make([]*regexp.Regexp, 0, 6)
+	for _, pattern := range r.Match.values() {
+		r.patterns = append(r.patterns, globToRegexp(pattern))
+	}
+
+	return nil
+}
+
+func (m Match) values() []string {
+	return []string{m.VendorName, m.Service, m.ProductFamily, m.Region, m.ResourceType, m.CostComponent}
+}
+
+// globToRegexp converts the glob pattern to an anchored regexp, an empty
+// pattern matches anything.
+func globToRegexp(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// Apply applies the first matching rule to each cost component of the
+// resources and their sub-resources. It must be called after the prices have
+// been set and before the costs are calculated.
+func (f *DiscountsFile) Apply(resources []*schema.Resource) {
+	for _, r := range resources {
+		f.applyToResource(r, r.ResourceType)
+	}
+}
+
+// applyToResource applies the rules to the cost components of r. Sub-resources
+// don't usually have a resource type so are matched with the resource type of
+// their parent.
+func (f *DiscountsFile) applyToResource(r *schema.Resource, resourceType string) {
+	if r.ResourceType != "" {
+		resourceType = r.ResourceType
+	}
+
+	for _, c := range r.CostComponents {
+		listPrice := c.Price()
+		if c.Discount() != nil {
+			listPrice = c.Discount().ListPrice
+		}
+
+		for _, rule := range f.Discounts {
+			if rule.matches(resourceType, c) {
+				c.ApplyDiscount(rule.Name, rule.price(c, listPrice))
+				break
+			}
+		}
+	}
+
+	for _, s := range r.SubResources {
+		f.applyToResource(s, resourceType)
+	}
+}
+
+func (r *Rule) matches(resourceType string, c *schema.CostComponent) bool {
+	var vendorName, service, productFamily, region string
+	if c.ProductFilter != nil {
+		vendorName = schema.StrValue(c.ProductFilter.VendorName)
+		service = schema.StrValue(c.ProductFilter.Service)
+		productFamily = schema.StrValue(c.ProductFilter.ProductFamily)
+		region = schema.StrValue(c.ProductFilter.Region)
+	}
+
+	values := Match{vendorName, service, productFamily, region, resourceType, c.Name}.values()
+	for i, re := range r.patterns {
+		if re != nil && !re.MatchString(values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// price returns the discounted price of the cost component. Unit prices are
+// given per unit shown in the output, e.g. per 1M requests, so are divided by
+// the unit multiplier to get the price per unit used by the pricing source.
+func (r *Rule) price(c *schema.CostComponent, listPrice decimal.Decimal) decimal.Decimal {
+	if r.UnitPrice != nil {
+		p := decimal.NewFromFloat(*r.UnitPrice)
+		if !c.UnitMultiplier.IsZero() {
+			p = p.Div(c.UnitMultiplier)
+		}
+
+		return p
+	}
+
+	mul := decimal.NewFromInt(100).Sub(decimal.NewFromFloat(*r.Percent)).Div(decimal.NewFromInt(100))
+	return listPrice.Mul(mul)
+}
@@ -0,0 +1,172 @@
+package discounts
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func newComponent(name, service string, price string, unitMultiplier int64) *schema.CostComponent {
+	c := &schema.CostComponent{
+		Name:           name,
+		UnitMultiplier: decimal.NewFromInt(unitMultiplier),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Service:       strPtr(service),
+			ProductFamily: strPtr("Compute Instance"),
+			Region:        strPtr("us-east-1"),
+		},
+	}
+	c.SetPrice(decimal.RequireFromString(price))
+
+	return c
+}
+
+func TestLoadDiscountsFileFromString(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{
+			name: "valid",
+			contents: `version: 0.1
+discounts:
+  - name: EDP
+    match:
+      vendor_name: aws
+    percent: 9
+  - name: Negotiated
+    match:
+      resource_type: aws_lambda_function
+      cost_component: Requests
+    unit_price: 0.15
+`,
+		},
+		{
+			name:     "unsupported version",
+			contents: "version: 0.2\ndiscounts: []\n",
+			err:      "Invalid discounts file version. Supported versions are 0.1 ≤ x ≤ 0.1",
+		},
+		{
+			name:     "missing name",
+			contents: "version: 0.1\ndiscounts:\n  - percent: 10\n",
+			err:      "Invalid discount at index 0: name is required",
+		},
+		{
+			name:     "percent and unit price",
+			contents: "version: 0.1\ndiscounts:\n  - name: EDP\n    percent: 10\n    unit_price: 1\n",
+			err:      "Invalid discount at index 0: EDP must set exactly one of percent or unit_price",
+		},
+		{
+			name:     "neither percent nor unit price",
+			contents: "version: 0.1\ndiscounts:\n  - name: EDP\n",
+			err:      "Invalid discount at index 0: EDP must set exactly one of percent or unit_price",
+		},
+		{
+			name:     "percent out of range",
+			contents: "version: 0.1\ndiscounts:\n  - name: EDP\n    percent: 110\n",
+			err:      "Invalid discount at index 0: EDP percent must be between 0 and 100",
+		},
+		{
+			name:     "negative unit price",
+			contents: "version: 0.1\ndiscounts:\n  - name: EDP\n    unit_price: -1\n",
+			err:      "Invalid discount at index 0: EDP unit_price must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadDiscountsFileFromString(tt.contents)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	f, err := LoadDiscountsFileFromString(`version: 0.1
+discounts:
+  - name: Lambda requests
+    match:
+      resource_type: aws_lambda_function
+      cost_component: Requests
+    unit_price: 0.15
+  - name: Linux instances
+    match:
+      service: AmazonEC2
+      cost_component: "Instance usage (Linux/UNIX*"
+    percent: 20
+  - name: EDP
+    match:
+      vendor_name: aws
+    percent: 10
+`)
+	require.NoError(t, err)
+
+	requests := newComponent("Requests", "AWSLambda", "0.0000002", 1000000)
+	instance := newComponent("Instance usage (Linux/UNIX, on-demand, t3.medium)", "AmazonEC2", "0.0416", 1)
+	storage := newComponent("Storage (general purpose SSD, gp2)", "AmazonEC2", "0.1", 1)
+
+	resources := []*schema.Resource{
+		{
+			Name:           "aws_lambda_function.hello",
+			ResourceType:   "aws_lambda_function",
+			CostComponents: []*schema.CostComponent{requests},
+		},
+		{
+			Name:           "aws_instance.web",
+			ResourceType:   "aws_instance",
+			CostComponents: []*schema.CostComponent{instance},
+			SubResources: []*schema.Resource{
+				{
+					Name:           "root_block_device",
+					CostComponents: []*schema.CostComponent{storage},
+				},
+			},
+		},
+	}
+
+	f.Apply(resources)
+
+	assert.Equal(t, "0.00000015", requests.Price().String())
+	assert.Equal(t, "Lambda requests", requests.Discount().Name)
+	assert.Equal(t, "0.0000002", requests.Discount().ListPrice.String())
+
+	assert.Equal(t, "0.03328", instance.Price().String())
+	assert.Equal(t, "Linux instances", instance.Discount().Name)
+
+	assert.Equal(t, "0.09", storage.Price().String())
+	assert.Equal(t, "EDP", storage.Discount().Name)
+
+	f.Apply(resources)
+	assert.Equal(t, "0.03328", instance.Price().String(), "applying discounts again should not compound them")
+}
+
+func TestApplyNoMatch(t *testing.T) {
+	f, err := LoadDiscountsFileFromString(`version: 0.1
+discounts:
+  - name: Google
+    match:
+      vendor_name: gcp
+    percent: 10
+`)
+	require.NoError(t, err)
+
+	c := newComponent("Instance usage", "AmazonEC2", "0.0416", 1)
+	f.Apply([]*schema.Resource{{Name: "aws_instance.web", CostComponents: []*schema.CostComponent{c}}})
+
+	assert.Equal(t, "0.0416", c.Price().String())
+	assert.Nil(t, c.Discount())
+}
@@ -0,0 +1,524 @@
+// Package explore implements an interactive terminal explorer for Infracost
+// breakdowns. Projects can be expanded to show their resources, subresources
+// and cost components, sorted by cost or diff and filtered by resource type
+// or tag.
+package explore
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// Key is a key pressed by the user. Printable characters are the character
+// itself and other keys are one of the Key constants.
+type Key string
+
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyPgUp      Key = "pgup"
+	KeyPgDown    Key = "pgdown"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyCtrlC     Key = "ctrl+c"
+)
+
+const (
+	costWidth = 14
+	// pageSize is the number of rows moved by page up and page down before
+	// the explorer has been rendered.
+	pageSize = 10
+)
+
+const helpText = "↑/↓ move  ←/→ collapse/expand  e/c expand/collapse all  s sort  / filter  o open source  q quit"
+
+// row is a visible row of the tree.
+type row struct {
+	node   *node
+	depth  int
+	parent int
+	// source is the resource that the row belongs to if its source location is known.
+	source *node
+}
+
+// Explorer holds the state of the explorer. It doesn't read from or write to
+// the terminal so it can be used with any input and output.
+type Explorer struct {
+	currency string
+	projects []*node
+	hasDiff  bool
+
+	sortMode sortMode
+	query    string
+	filter   filter
+
+	editingFilter bool
+	previousQuery string
+
+	rows   []row
+	cursor int
+	offset int
+	height int
+	status string
+
+	// OpenSource is called to open the file that the selected resource is
+	// defined in. If it is nil the location is shown in the status bar.
+	OpenSource func(filename string, line int) error
+}
+
+// New returns an explorer of the projects in the Infracost output.
+func New(out output.Root) *Explorer {
+	e := &Explorer{
+		currency: out.Currency,
+		projects: buildTree(out),
+	}
+
+	for _, p := range out.Projects {
+		if p.Diff != nil {
+			e.hasDiff = true
+			break
+		}
+	}
+
+	if len(e.projects) == 1 {
+		e.projects[0].expanded = true
+	}
+
+	sortTree(e.projects, e.sortMode)
+	e.refresh()
+
+	return e
+}
+
+// refresh rebuilds the visible rows after the tree, sort or filter has
+// changed, keeping the cursor on the selected node if it is still visible.
+func (e *Explorer) refresh() {
+	var selected *node
+	if e.cursor < len(e.rows) {
+		selected = e.rows[e.cursor].node
+	}
+
+	e.rows = e.rows[:0]
+	for _, p := range e.projects {
+		resources := p.children
+		if len(e.filter) > 0 {
+			resources = nil
+			for _, r := range p.children {
+				if e.filter.matches(r) {
+					resources = append(resources, r)
+				}
+			}
+
+			if len(resources) == 0 {
+				continue
+			}
+		}
+
+		e.rows = append(e.rows, row{node: p, parent: -1})
+		if p.expanded {
+			e.appendRows(resources, 1, len(e.rows)-1, nil)
+		}
+	}
+
+	e.cursor = 0
+	for i, r := range e.rows {
+		if r.node == selected {
+			e.cursor = i
+			break
+		}
+	}
+}
+
+func (e *Explorer) appendRows(nodes []*node, depth int, parent int, source *node) {
+	for _, n := range nodes {
+		s := source
+		if n.filename != "" {
+			s = n
+		}
+
+		e.rows = append(e.rows, row{node: n, depth: depth, parent: parent, source: s})
+		if n.expanded {
+			e.appendRows(n.children, depth+1, len(e.rows)-1, s)
+		}
+	}
+}
+
+// HandleKey updates the explorer for a key press. It returns false when the
+// user quits the explorer.
+func (e *Explorer) HandleKey(k Key) bool {
+	e.status = ""
+
+	if e.editingFilter {
+		e.handleFilterKey(k)
+		return true
+	}
+
+	switch k {
+	case "q", KeyCtrlC:
+		return false
+	case "k", KeyUp:
+		e.moveCursor(-1)
+	case "j", KeyDown:
+		e.moveCursor(1)
+	case KeyPgUp:
+		e.moveCursor(-e.pageSize())
+	case KeyPgDown:
+		e.moveCursor(e.pageSize())
+	case "g", KeyHome:
+		e.moveCursor(-len(e.rows))
+	case "G", KeyEnd:
+		e.moveCursor(len(e.rows))
+	case "l", KeyRight, KeyEnter:
+		e.expand()
+	case "h", KeyLeft:
+		e.collapse()
+	case " ":
+		if r, ok := e.selected(); ok && r.node.expanded {
+			e.collapse()
+		} else {
+			e.expand()
+		}
+	case "e":
+		setExpanded(e.projects, true)
+		e.refresh()
+	case "c":
+		setExpanded(e.projects, false)
+		e.refresh()
+	case "s":
+		e.sortMode = (e.sortMode + 1) % 3
+		if e.sortMode == sortByDiff && !e.hasDiff {
+			e.sortMode++
+		}
+		sortTree(e.projects, e.sortMode)
+		e.refresh()
+		e.status = fmt.Sprintf("Sorted by %s", e.sortMode)
+	case "/":
+		e.editingFilter = true
+		e.previousQuery = e.query
+	case "o":
+		e.openSource()
+	}
+
+	return true
+}
+
+func (e *Explorer) handleFilterKey(k Key) {
+	switch k {
+	case KeyEnter:
+		e.editingFilter = false
+		return
+	case KeyEsc, KeyCtrlC:
+		e.editingFilter = false
+		e.query = e.previousQuery
+	case KeyBackspace:
+		if e.query != "" {
+			_, size := utf8.DecodeLastRuneInString(e.query)
+			e.query = e.query[:len(e.query)-size]
+		}
+	default:
+		if utf8.RuneCountInString(string(k)) != 1 {
+			return
+		}
+		e.query += string(k)
+	}
+
+	e.setFilter(e.query)
+}
+
+func (e *Explorer) setFilter(query string) {
+	e.query = query
+	e.filter = parseFilter(query)
+
+	// Expand the projects so the matching resources are shown.
+	if len(e.filter) > 0 {
+		for _, p := range e.projects {
+			p.expanded = true
+		}
+	}
+
+	e.refresh()
+}
+
+func (e *Explorer) selected() (row, bool) {
+	if e.cursor >= len(e.rows) {
+		return row{}, false
+	}
+
+	return e.rows[e.cursor], true
+}
+
+func (e *Explorer) moveCursor(delta int) {
+	e.cursor += delta
+	if e.cursor >= len(e.rows) {
+		e.cursor = len(e.rows) - 1
+	}
+	if e.cursor < 0 {
+		e.cursor = 0
+	}
+}
+
+func (e *Explorer) pageSize() int {
+	if e.height > 1 {
+		return e.height - 1
+	}
+
+	return pageSize
+}
+
+func (e *Explorer) expand() {
+	r, ok := e.selected()
+	if !ok || len(r.node.children) == 0 {
+		return
+	}
+
+	if r.node.expanded {
+		e.moveCursor(1)
+		return
+	}
+
+	r.node.expanded = true
+	e.refresh()
+}
+
+func (e *Explorer) collapse() {
+	r, ok := e.selected()
+	if !ok {
+		return
+	}
+
+	if r.node.expanded {
+		r.node.expanded = false
+		e.refresh()
+		return
+	}
+
+	if r.parent >= 0 {
+		e.cursor = r.parent
+	}
+}
+
+func setExpanded(nodes []*node, expanded bool) {
+	for _, n := range nodes {
+		n.expanded = expanded && len(n.children) > 0
+		setExpanded(n.children, expanded)
+	}
+}
+
+func (e *Explorer) openSource() {
+	r, ok := e.selected()
+	if !ok || r.source == nil {
+		e.status = "No source location for this row"
+		return
+	}
+
+	location := sourceLocation(r.source)
+	if e.OpenSource == nil {
+		e.status = location
+		return
+	}
+
+	if err := e.OpenSource(r.source.filename, r.source.line); err != nil {
+		e.status = fmt.Sprintf("Could not open %s: %s", location, err)
+	}
+}
+
+func sourceLocation(n *node) string {
+	if n.line > 0 {
+		return fmt.Sprintf("%s:%d", n.filename, n.line)
+	}
+
+	return n.filename
+}
+
+// View renders the explorer to fit in a terminal of the given size. Lines
+// are separated by "\n" and styled with the ui package.
+func (e *Explorer) View(width, height int) string {
+	if height < 4 {
+		height = 4
+	}
+
+	columns := 1
+	if e.hasDiff {
+		columns = 2
+	}
+	nameWidth := width - columns*(costWidth+2)
+	if nameWidth < 10 {
+		nameWidth = 10
+	}
+
+	e.height = height - 3
+	if e.cursor < e.offset {
+		e.offset = e.cursor
+	}
+	if e.cursor >= e.offset+e.height {
+		e.offset = e.cursor - e.height + 1
+	}
+
+	lines := make([]string, 0, height)
+
+	info := fmt.Sprintf("%d %s · sorted by %s", len(e.projects), pluralize("project", len(e.projects)), e.sortMode)
+	if e.query != "" {
+		info += " · filter: " + e.query
+	}
+	lines = append(lines, ui.BoldString("Infracost explorer")+"  "+ui.FaintString(info))
+
+	header := ui.UnderlineString("Name") + strings.Repeat(" ", nameWidth-len("Name"))
+	header += "  " + strings.Repeat(" ", costWidth-len("Monthly cost")) + ui.UnderlineString("Monthly cost")
+	if e.hasDiff {
+		header += "  " + strings.Repeat(" ", costWidth-len("Diff")) + ui.UnderlineString("Diff")
+	}
+	lines = append(lines, header)
+
+	for i := e.offset; i < len(e.rows) && i < e.offset+e.height; i++ {
+		lines = append(lines, e.renderRow(e.rows[i], i == e.cursor, nameWidth))
+	}
+
+	if len(e.rows) == 0 {
+		lines = append(lines, ui.FaintString("No resources match the filter"))
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, e.footer(width))
+
+	return strings.Join(lines, "\n")
+}
+
+func (e *Explorer) renderRow(r row, selected bool, nameWidth int) string {
+	n := r.node
+
+	marker := "  "
+	if len(n.children) > 0 {
+		marker = "▸ "
+		if n.expanded {
+			marker = "▾ "
+		}
+	}
+
+	name := n.name
+	if n.detail != "" {
+		name += " (" + n.detail + ")"
+	}
+
+	// Deeply nested rows in a narrow terminal can have a prefix wider than the
+	// name column, in which case the name is dropped rather than padded.
+	prefix := strings.Repeat("  ", r.depth) + marker
+	width := nameWidth - utf8.RuneCountInString(prefix)
+	if width < 0 {
+		width = 0
+	}
+	name = truncate(name, width)
+	padding := strings.Repeat(" ", width-utf8.RuneCountInString(name))
+
+	cost := padLeft(output.FormatCost2DP(e.currency, n.cost), costWidth)
+	diff := ""
+	if e.hasDiff {
+		diff = padLeft(formatDiff(e.currency, n.diff), costWidth)
+	}
+
+	if selected {
+		line := prefix + name + padding + "  " + cost
+		if e.hasDiff {
+			line += "  " + diff
+		}
+		return ui.PrimaryString(line)
+	}
+
+	switch n.kind {
+	case projectNode:
+		name = ui.BoldString(name)
+	case costComponentNode:
+		name = ui.FaintString(name)
+	}
+
+	line := ui.FaintString(prefix) + name + padding + "  " + cost
+	if e.hasDiff {
+		switch {
+		case n.diff != nil && n.diff.IsPositive():
+			diff = ui.WarningString(diff)
+		case n.diff != nil && n.diff.IsNegative():
+			diff = ui.SuccessString(diff)
+		}
+		line += "  " + diff
+	}
+
+	return line
+}
+
+func (e *Explorer) footer(width int) string {
+	if e.editingFilter {
+		return ui.PrimaryString("Filter (type:<type> tag:<key>=<value> <name>): ") + e.query + "_"
+	}
+
+	if e.status != "" {
+		return truncate(e.status, width)
+	}
+
+	text := helpText
+	if r, ok := e.selected(); ok && r.source != nil {
+		text = sourceLocation(r.source) + "  " + text
+	}
+
+	return ui.FaintString(truncate(text, width))
+}
+
+func formatDiff(currency string, d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	abs := d.Abs()
+	sym := "+"
+	if d.IsNegative() {
+		sym = "-"
+	}
+
+	return sym + output.FormatCost2DP(currency, &abs)
+}
+
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	if n <= 3 {
+		return string(r[:n])
+	}
+
+	return string(r[:n-3]) + "..."
+}
+
+func padLeft(s string, n int) string {
+	l := utf8.RuneCountInString(s)
+	if l >= n {
+		return s
+	}
+
+	return strings.Repeat(" ", n-l) + s
+}
+
+func pluralize(s string, count int) string {
+	if count == 1 {
+		return s
+	}
+
+	return s + "s"
+}
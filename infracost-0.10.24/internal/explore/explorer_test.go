@@ -0,0 +1,309 @@
+package explore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func decimalPtr(f float64) *decimal.Decimal {
+	d := decimal.NewFromFloat(f)
+	return &d
+}
+
+func testOutput() output.Root {
+	return output.Root{
+		Currency: "USD",
+		Projects: []output.Project{
+			{
+				Name:     "infra",
+				Metadata: &schema.ProjectMetadata{Path: "infra"},
+				Breakdown: &output.Breakdown{
+					TotalMonthlyCost: decimalPtr(130),
+					Resources: []output.Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							Tags:         map[string]string{"env": "prod"},
+							Metadata:     map[string]interface{}{"filename": "main.tf", "startLine": float64(12)},
+							MonthlyCost:  decimalPtr(100),
+							CostComponents: []output.CostComponent{
+								{Name: "Instance usage", Unit: "hours", MonthlyQuantity: decimalPtr(730), MonthlyCost: decimalPtr(90)},
+							},
+							SubResources: []output.Resource{
+								{
+									Name:        "root_block_device",
+									MonthlyCost: decimalPtr(10),
+									CostComponents: []output.CostComponent{
+										{Name: "Storage", Unit: "GB", MonthlyQuantity: decimalPtr(100), MonthlyCost: decimalPtr(10)},
+									},
+								},
+							},
+						},
+						{
+							Name:        "module.db.aws_db_instance.main",
+							Tags:        map[string]string{"env": "dev"},
+							MonthlyCost: decimalPtr(30),
+						},
+					},
+				},
+				Diff: &output.Breakdown{
+					TotalMonthlyCost: decimalPtr(20),
+					Resources: []output.Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(-5)},
+						{Name: "module.db.aws_db_instance.main", MonthlyCost: decimalPtr(25)},
+					},
+				},
+			},
+			{
+				Name:      "empty",
+				Breakdown: &output.Breakdown{TotalMonthlyCost: decimalPtr(0)},
+			},
+		},
+	}
+}
+
+func rowNames(e *Explorer) []string {
+	names := make([]string, 0, len(e.rows))
+	for _, r := range e.rows {
+		names = append(names, strings.Repeat("  ", r.depth)+r.node.name)
+	}
+
+	return names
+}
+
+func pressKeys(e *Explorer, keys ...Key) {
+	for _, k := range keys {
+		e.HandleKey(k)
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	projects := buildTree(testOutput())
+	require.Len(t, projects, 2)
+
+	web := projects[0].children[0]
+	assert.Equal(t, "aws_instance", web.resourceType)
+	assert.Equal(t, "infra/main.tf", web.filename)
+	assert.Equal(t, 12, web.line)
+	assert.Equal(t, "-5", web.diff.String())
+	assert.Equal(t, "730 hours", web.children[0].detail)
+	assert.Equal(t, subresourceNode, web.children[1].kind)
+
+	db := projects[0].children[1]
+	assert.Equal(t, "aws_db_instance", db.resourceType)
+	assert.Equal(t, "25", db.diff.String())
+}
+
+func TestExpandAndCollapse(t *testing.T) {
+	e := New(testOutput())
+	assert.Equal(t, []string{"infra", "empty"}, rowNames(e))
+
+	pressKeys(e, KeyEnter, KeyDown, KeyRight)
+	assert.Equal(t, []string{
+		"infra",
+		"  aws_instance.web",
+		"    Instance usage",
+		"    root_block_device",
+		"  module.db.aws_db_instance.main",
+		"empty",
+	}, rowNames(e))
+
+	// Collapsing a cost component moves the cursor to its resource.
+	pressKeys(e, KeyDown, KeyLeft)
+	assert.Equal(t, "aws_instance.web", e.rows[e.cursor].node.name)
+
+	pressKeys(e, KeyLeft)
+	assert.Equal(t, []string{"infra", "  aws_instance.web", "  module.db.aws_db_instance.main", "empty"}, rowNames(e))
+
+	pressKeys(e, "c")
+	assert.Equal(t, []string{"infra", "empty"}, rowNames(e))
+
+	pressKeys(e, "e")
+	assert.Len(t, e.rows, 7)
+}
+
+func TestSort(t *testing.T) {
+	e := New(testOutput())
+	pressKeys(e, "e")
+	assert.Equal(t, "aws_instance.web", e.projects[0].children[0].name)
+
+	pressKeys(e, "s")
+	assert.Equal(t, sortByDiff, e.sortMode)
+	assert.Equal(t, "module.db.aws_db_instance.main", e.projects[0].children[0].name)
+	assert.Equal(t, "Sorted by diff", e.status)
+
+	pressKeys(e, "s")
+	assert.Equal(t, sortByName, e.sortMode)
+	assert.Equal(t, []string{"empty", "infra"}, []string{e.projects[0].name, e.projects[1].name})
+
+	pressKeys(e, "s")
+	assert.Equal(t, sortByCost, e.sortMode)
+}
+
+func TestSortSkipsDiffWithoutDiffs(t *testing.T) {
+	out := testOutput()
+	out.Projects[0].Diff = nil
+
+	e := New(out)
+	pressKeys(e, "s")
+	assert.Equal(t, sortByName, e.sortMode)
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"type:aws_instance", []string{"infra", "  aws_instance.web"}},
+		{"type:aws_*", []string{"infra", "  aws_instance.web", "  module.db.aws_db_instance.main"}},
+		{"tag:env", []string{"infra", "  aws_instance.web", "  module.db.aws_db_instance.main"}},
+		{"tag:env=dev", []string{"infra", "  module.db.aws_db_instance.main"}},
+		{"type:aws_instance tag:env=dev", []string{}},
+		{"DB", []string{"infra", "  module.db.aws_db_instance.main"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			e := New(testOutput())
+
+			pressKeys(e, "/")
+			for _, r := range tt.query {
+				pressKeys(e, Key(string(r)))
+			}
+			pressKeys(e, KeyEnter)
+
+			assert.False(t, e.editingFilter)
+			assert.Equal(t, tt.expected, rowNames(e))
+		})
+	}
+}
+
+func TestFilterCancel(t *testing.T) {
+	e := New(testOutput())
+
+	pressKeys(e, "/", "d", "b", KeyBackspace, KeyEsc)
+	assert.Equal(t, "", e.query)
+	assert.Equal(t, []string{"infra", "  aws_instance.web", "  module.db.aws_db_instance.main", "empty"}, rowNames(e))
+}
+
+func TestOpenSource(t *testing.T) {
+	e := New(testOutput())
+	pressKeys(e, "e", KeyDown, KeyDown)
+
+	pressKeys(e, "o")
+	assert.Equal(t, "infra/main.tf:12", e.status)
+
+	var filename string
+	var line int
+	e.OpenSource = func(f string, l int) error {
+		filename, line = f, l
+		return nil
+	}
+
+	pressKeys(e, "o")
+	assert.Equal(t, "infra/main.tf", filename)
+	assert.Equal(t, 12, line)
+
+	pressKeys(e, "G", "k", "o")
+	assert.Equal(t, "No source location for this row", e.status)
+}
+
+func TestQuit(t *testing.T) {
+	e := New(testOutput())
+	assert.True(t, e.HandleKey("j"))
+	assert.False(t, e.HandleKey("q"))
+}
+
+func TestView(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	e := New(testOutput())
+	pressKeys(e, "e")
+
+	view := e.View(70, 8)
+	lines := strings.Split(view, "\n")
+	require.Len(t, lines, 8)
+
+	assert.Equal(t, []string{
+		"Infracost explorer  2 projects · sorted by monthly cost",
+		"Name                                      Monthly cost            Diff",
+		"▾ infra                                        $130.00         +$20.00",
+		"  ▾ aws_instance.web                           $100.00          -$5.00",
+		"      Instance usage (730 hours)                $90.00                ",
+		"    ▾ root_block_device                         $10.00                ",
+		"        Storage (100 GB)                        $10.00                ",
+		"↑/↓ move  ←/→ collapse/expand  e/c expand/collapse all  s sort  / f...",
+	}, lines)
+
+	// The rows scroll to keep the cursor visible and the footer shows the
+	// source location of the selected resource.
+	pressKeys(e, "G")
+	e.View(70, 8)
+	pressKeys(e, "k")
+	lines = strings.Split(e.View(70, 8), "\n")
+	assert.Equal(t, "      Instance usage (730 hours)                $90.00                ", lines[2])
+	assert.Equal(t, "  empty                                          $0.00                ", lines[6])
+	assert.Equal(t, "↑/↓ move  ←/→ collapse/expand  e/c expand/collapse all  s sort  / f...", lines[7])
+
+	pressKeys(e, "g", "j")
+	lines = strings.Split(e.View(70, 8), "\n")
+	assert.Equal(t, "infra/main.tf:12  ↑/↓ move  ←/→ collapse/expand  e/c expand/collaps...", lines[7])
+}
+
+func TestRenderRowDeeperThanNameWidth(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	e := New(testOutput())
+	n := &node{kind: costComponentNode, name: "Storage", detail: "100 GB"}
+
+	// The prefix of a row nested 6 deep is wider than the 10 character name
+	// column, so the name is dropped.
+	line := e.renderRow(row{node: n, depth: 6}, false, 10)
+	assert.True(t, strings.HasPrefix(line, "              "), line)
+	assert.NotContains(t, line, "Storage")
+}
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []Key{KeyUp, KeyDown, "j", KeyEnter, KeyPgDown, KeyEsc, "é", KeyBackspace, KeyCtrlC},
+		parseKeys([]byte("\x1b[A\x1b[Bj\r\x1b[6~\x1b[1;5Pé\x7f\x03")))
+}
+
+func TestEditorArgs(t *testing.T) {
+	assert.Equal(t, []string{"+12", "main.tf"}, editorArgs("vim", "main.tf", 12))
+	assert.Equal(t, []string{"--goto", "main.tf:12"}, editorArgs("/usr/bin/code", "main.tf", 12))
+	assert.Equal(t, []string{"main.tf"}, editorArgs("nano", "main.tf", 0))
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", " code --wait ")
+
+	editor, flags := editorCommand()
+	assert.Equal(t, "code", editor)
+	assert.Equal(t, []string{"--wait"}, flags)
+	assert.Equal(t, []string{"--goto", "main.tf:12"}, editorArgs(editor, "main.tf", 12))
+
+	t.Setenv("VISUAL", "vim")
+
+	editor, flags = editorCommand()
+	assert.Equal(t, "vim", editor)
+	assert.Empty(t, flags)
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	editor, _ = editorCommand()
+	assert.Equal(t, "", editor)
+}
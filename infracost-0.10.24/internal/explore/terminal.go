@@ -0,0 +1,200 @@
+package explore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// ErrNotTerminal is returned when the explorer isn't run in a terminal.
+var ErrNotTerminal = errors.New("infracost explore must be run in an interactive terminal")
+
+// CheckTerminal returns ErrNotTerminal if the input or output isn't a terminal.
+func CheckTerminal(in *os.File, w *os.File) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(w.Fd())) {
+		return ErrNotTerminal
+	}
+
+	return nil
+}
+
+// Run runs the explorer in the terminal until the user quits. The input and
+// output must be a terminal.
+func Run(out output.Root, in *os.File, w *os.File) error {
+	if err := CheckTerminal(in, w); err != nil {
+		return err
+	}
+
+	inFd, outFd := int(in.Fd()), int(w.Fd())
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return errors.Wrap(err, "Error setting up terminal")
+	}
+	fmt.Fprint(w, enterAltScreen)
+
+	restore := func() {
+		fmt.Fprint(w, exitAltScreen)
+		_ = term.Restore(inFd, state)
+	}
+	defer restore()
+
+	e := New(out)
+	if editor, editorFlags := editorCommand(); editor != "" {
+		e.OpenSource = func(filename string, line int) error {
+			restore()
+			defer func() {
+				state, _ = term.MakeRaw(inFd)
+				fmt.Fprint(w, enterAltScreen)
+			}()
+
+			args := append(append([]string{}, editorFlags...), editorArgs(editor, filename, line)...)
+			cmd := exec.Command(editor, args...) // nolint:gosec
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = in, w, w
+			return cmd.Run()
+		}
+	}
+
+	buf := make([]byte, 64)
+	for {
+		width, height, err := term.GetSize(outFd)
+		if err != nil {
+			width, height = 80, 24
+		}
+
+		fmt.Fprint(w, clearScreen+strings.ReplaceAll(e.View(width, height), "\n", "\r\n"))
+
+		n, err := in.Read(buf)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "Error reading from terminal")
+		}
+
+		for _, k := range parseKeys(buf[:n]) {
+			if !e.HandleKey(k) {
+				return nil
+			}
+		}
+	}
+}
+
+// editorCommand returns the editor set by $VISUAL or $EDITOR and any flags
+// set with it, e.g. "code --wait".
+func editorCommand() (string, []string) {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields[0], fields[1:]
+		}
+	}
+
+	return "", nil
+}
+
+// editorArgs returns the arguments to open the file at the line. Most
+// terminal editors accept +<line>, VS Code and its forks use --goto.
+func editorArgs(editor, filename string, line int) []string {
+	if line <= 0 {
+		return []string{filename}
+	}
+
+	switch strings.TrimSuffix(filepath.Base(editor), ".exe") {
+	case "code", "code-insiders", "codium", "cursor":
+		return []string{"--goto", fmt.Sprintf("%s:%d", filename, line)}
+	}
+
+	return []string{fmt.Sprintf("+%d", line), filename}
+}
+
+var escapeSequences = map[string]Key{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1b[C":  KeyRight,
+	"\x1b[D":  KeyLeft,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+	"\x1bOC":  KeyRight,
+	"\x1bOD":  KeyLeft,
+	"\x1b[5~": KeyPgUp,
+	"\x1b[6~": KeyPgDown,
+	"\x1b[H":  KeyHome,
+	"\x1b[F":  KeyEnd,
+	"\x1b[1~": KeyHome,
+	"\x1b[4~": KeyEnd,
+}
+
+// parseKeys returns the keys in the input read from a terminal in raw mode.
+func parseKeys(b []byte) []Key {
+	var keys []Key
+
+	s := string(b)
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			matched := false
+			for seq, k := range escapeSequences {
+				if strings.HasPrefix(s, seq) {
+					keys = append(keys, k)
+					s = s[len(seq):]
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				keys = append(keys, KeyEsc)
+				s = skipEscapeSequence(s)
+			}
+
+			continue
+		}
+
+		switch s[0] {
+		case '\r', '\n':
+			keys = append(keys, KeyEnter)
+		case 0x7f, '\b':
+			keys = append(keys, KeyBackspace)
+		case 0x03:
+			keys = append(keys, KeyCtrlC)
+		default:
+			r := []rune(s)[0]
+			keys = append(keys, Key(string(r)))
+			s = s[len(string(r)):]
+			continue
+		}
+
+		s = s[1:]
+	}
+
+	return keys
+}
+
+// skipEscapeSequence skips an unknown escape sequence so its characters
+// aren't treated as key presses.
+func skipEscapeSequence(s string) string {
+	if len(s) < 2 || (s[1] != '[' && s[1] != 'O') {
+		return s[1:]
+	}
+
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return s[i+1:]
+		}
+	}
+
+	return ""
+}
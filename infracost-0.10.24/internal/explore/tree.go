@@ -0,0 +1,284 @@
+package explore
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+type nodeKind int
+
+const (
+	projectNode nodeKind = iota
+	resourceNode
+	subresourceNode
+	costComponentNode
+)
+
+var moduleAddressRegex = regexp.MustCompile(`^((?:module\.[^.\[]+(?:\[[^\]]*\])?\.)*)`)
+
+// node is a row of the tree that can be expanded to show its children:
+// projects contain resources, which contain subresources and cost components.
+type node struct {
+	kind nodeKind
+	name string
+	// detail is shown after the name, e.g. the quantity of a cost component.
+	detail string
+	cost   *decimal.Decimal
+	diff   *decimal.Decimal
+
+	resourceType string
+	tags         map[string]string
+	filename     string
+	line         int
+
+	children []*node
+	expanded bool
+}
+
+// buildTree returns a project node for each project of the output. Diffs are
+// matched to the resources, subresources and cost components by name.
+func buildTree(out output.Root) []*node {
+	projects := make([]*node, 0, len(out.Projects))
+
+	for _, p := range out.Projects {
+		n := &node{kind: projectNode, name: p.Label()}
+
+		if p.Breakdown != nil {
+			n.cost = p.Breakdown.TotalMonthlyCost
+		}
+
+		diffs := make(map[string]output.Resource)
+		if p.Diff != nil {
+			n.diff = p.Diff.TotalMonthlyCost
+			for _, r := range p.Diff.Resources {
+				diffs[r.Name] = r
+			}
+		}
+
+		if p.Breakdown != nil {
+			projectDir := ""
+			if p.Metadata != nil {
+				projectDir = p.Metadata.Path
+			}
+
+			for _, r := range p.Breakdown.Resources {
+				var diff *output.Resource
+				if d, ok := diffs[r.Name]; ok {
+					diff = &d
+				}
+
+				n.children = append(n.children, resourceTree(r, diff, resourceNode, projectDir))
+			}
+		}
+
+		projects = append(projects, n)
+	}
+
+	return projects
+}
+
+func resourceTree(r output.Resource, diff *output.Resource, kind nodeKind, projectDir string) *node {
+	n := &node{
+		kind:         kind,
+		name:         r.Name,
+		cost:         r.MonthlyCost,
+		resourceType: resourceType(r),
+		tags:         r.Tags,
+	}
+
+	if diff != nil {
+		n.diff = diff.MonthlyCost
+	}
+
+	if filename, ok := r.Metadata["filename"].(string); ok && filename != "" {
+		n.filename = sourcePath(filename, projectDir)
+		n.line = metadataInt(r.Metadata["startLine"])
+	}
+
+	for _, c := range r.CostComponents {
+		child := &node{
+			kind: costComponentNode,
+			name: c.Name,
+			cost: c.MonthlyCost,
+		}
+
+		if c.MonthlyQuantity != nil {
+			child.detail = strings.TrimSpace(c.MonthlyQuantity.Round(2).String() + " " + c.Unit)
+		}
+
+		if diff != nil {
+			for _, dc := range diff.CostComponents {
+				if dc.Name == c.Name {
+					child.diff = dc.MonthlyCost
+					break
+				}
+			}
+		}
+
+		n.children = append(n.children, child)
+	}
+
+	for _, s := range r.SubResources {
+		var subDiff *output.Resource
+		if diff != nil {
+			for i := range diff.SubResources {
+				if diff.SubResources[i].Name == s.Name {
+					subDiff = &diff.SubResources[i]
+					break
+				}
+			}
+		}
+
+		n.children = append(n.children, resourceTree(s, subDiff, subresourceNode, ""))
+	}
+
+	return n
+}
+
+// resourceType returns the type of the resource. Infracost JSON files from
+// older versions don't include the type so it is taken from the address.
+func resourceType(r output.Resource) string {
+	if r.ResourceType != "" {
+		return r.ResourceType
+	}
+
+	address := strings.TrimPrefix(r.Name, moduleAddressRegex.FindString(r.Name))
+	if i := strings.Index(address, "."); i > 0 {
+		return address[:i]
+	}
+
+	return ""
+}
+
+// sourcePath returns the path of the file that a resource is defined in.
+// Relative filenames are relative to the project directory.
+func sourcePath(filename, projectDir string) string {
+	if filepath.IsAbs(filename) || projectDir == "" || strings.HasSuffix(projectDir, ".json") {
+		return filename
+	}
+
+	return filepath.Join(projectDir, filename)
+}
+
+func metadataInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+
+	return 0
+}
+
+type sortMode int
+
+const (
+	sortByCost sortMode = iota
+	sortByDiff
+	sortByName
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortByDiff:
+		return "diff"
+	case sortByName:
+		return "name"
+	}
+
+	return "monthly cost"
+}
+
+// sortTree sorts the children of every node, costs are sorted in descending
+// order with nodes without a cost last.
+func sortTree(nodes []*node, mode sortMode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+
+		switch mode {
+		case sortByCost:
+			if c := compareCosts(a.cost, b.cost); c != 0 {
+				return c > 0
+			}
+		case sortByDiff:
+			if c := compareCosts(a.diff, b.diff); c != 0 {
+				return c > 0
+			}
+		}
+
+		return a.name < b.name
+	})
+
+	for _, n := range nodes {
+		sortTree(n.children, mode)
+	}
+}
+
+func compareCosts(a, b *decimal.Decimal) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	return a.Cmp(*b)
+}
+
+// filter matches resources by a query of space separated terms which must
+// all match:
+//
+//	type:<resource type>   the resource type, * matches any characters
+//	tag:<key>              resources with the tag
+//	tag:<key>=<value>      resources with the tag set to the value
+//	<text>                 resources with the text in their address
+type filter []string
+
+func parseFilter(query string) filter {
+	return strings.Fields(query)
+}
+
+func (f filter) matches(n *node) bool {
+	for _, term := range f {
+		if !matchesTerm(n, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesTerm(n *node, term string) bool {
+	switch {
+	case strings.HasPrefix(term, "type:"):
+		pattern := strings.TrimPrefix(term, "type:")
+		if ok, _ := filepath.Match(pattern, n.resourceType); ok {
+			return true
+		}
+
+		return n.resourceType == pattern
+	case strings.HasPrefix(term, "tag:"):
+		tag := strings.TrimPrefix(term, "tag:")
+		key, value, hasValue := strings.Cut(tag, "=")
+
+		v, ok := n.tags[key]
+		if !ok {
+			return false
+		}
+
+		return !hasValue || v == value
+	}
+
+	return strings.Contains(strings.ToLower(n.name), strings.ToLower(term))
+}
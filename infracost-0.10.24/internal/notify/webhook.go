@@ -0,0 +1,84 @@
+// Package notify posts Infracost messages to chat and automation tools using
+// incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/version"
+)
+
+// maxErrorBodySize is the maximum length of a response body that is included
+// in an error message.
+const maxErrorBodySize = 512
+
+// WebhookOptions configures how a webhook request is sent.
+type WebhookOptions struct {
+	// Retries is the number of times a request is retried if it fails with a
+	// connection error, a 429 or a 5xx response.
+	Retries int
+	// RetryWaitMin and RetryWaitMax bound the exponential backoff between
+	// retries. The retryablehttp defaults are used if they are zero.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	// Timeout is the timeout of each request attempt.
+	Timeout time.Duration
+}
+
+// PostWebhook POSTs the JSON body to the incoming webhook URL, retrying
+// failed requests. An error is returned if the final response doesn't have a
+// 2xx status code.
+func PostWebhook(ctx context.Context, url string, body []byte, opts WebhookOptions) error {
+	client := retryablehttp.NewClient()
+	client.Logger = &apiclient.LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
+	client.RetryMax = opts.Retries
+	if opts.RetryWaitMin > 0 {
+		client.RetryWaitMin = opts.RetryWaitMin
+	}
+	if opts.RetryWaitMax > 0 {
+		client.RetryWaitMax = opts.RetryWaitMax
+	}
+	client.HTTPClient.Timeout = 30 * time.Second
+	if opts.Timeout > 0 {
+		client.HTTPClient.Timeout = opts.Timeout
+	}
+	// Return the last response rather than a generic "giving up" error so that
+	// the error message from the webhook can be shown.
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Error generating webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("infracost-%s", version.Version))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error posting to webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		msg := strings.TrimSpace(string(respBody))
+		if msg == "" {
+			return fmt.Errorf("Error posting to webhook: %s", resp.Status)
+		}
+
+		return fmt.Errorf("Error posting to webhook: %s: %s", resp.Status, msg)
+	}
+
+	return nil
+}
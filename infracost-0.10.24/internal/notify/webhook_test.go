@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetries = WebhookOptions{RetryWaitMin: time.Millisecond, RetryWaitMax: time.Millisecond}
+
+func TestPostWebhook(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer ts.Close()
+
+	err := PostWebhook(context.Background(), ts.URL, []byte(`{"text":"hello"}`), fastRetries)
+	require.NoError(t, err)
+	assert.Equal(t, `{"text":"hello"}`, body)
+}
+
+func TestPostWebhookRetries(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		// The body must be resent with every retry.
+		b, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{}`, string(b))
+	}))
+	defer ts.Close()
+
+	opts := fastRetries
+	opts.Retries = 3
+
+	err := PostWebhook(context.Background(), ts.URL, []byte(`{}`), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPostWebhookRetriesExhausted(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("rate limited"))
+	}))
+	defer ts.Close()
+
+	opts := fastRetries
+	opts.Retries = 2
+
+	err := PostWebhook(context.Background(), ts.URL, []byte(`{}`), opts)
+	require.Error(t, err)
+	assert.Equal(t, "Error posting to webhook: 429 Too Many Requests: rate limited", err.Error())
+	assert.Equal(t, 3, calls)
+}
@@ -278,6 +278,7 @@ func Combine(inputs []ReportInput) (Root, error) {
 	combined.DiffTotalMonthlyCost = diffTotalMonthlyCost
 	combined.TimeGenerated = time.Now().UTC()
 	combined.Summary = MergeSummaries(summaries)
+	combined.Projection = sumProjections(projects)
 	combined.Metadata = metadata
 	if len(inputs) > 0 {
 		combined.CloudURL = inputs[len(inputs)-1].Root.CloudURL
@@ -344,6 +345,18 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		b, err = ToMarkdown(r, opts, MarkdownOptions{BasicSyntax: true, OmitDetails: true})
 	case "slack-message":
 		b, err = ToSlackMessage(r, opts)
+	case "teams-message":
+		b, err = ToTeamsMessage(r, opts)
+	case "csv":
+		b, err = ToCSV(r, opts)
+	case "xlsx":
+		b, err = ToXLSX(r, opts)
+	case "junit":
+		b, err = ToJUnit(r, opts)
+	case "sarif":
+		b, err = ToSARIF(r, opts)
+	case "template":
+		b, err = ToTemplate(r, opts)
 	default:
 		b, err = ToTable(r, opts)
 	}
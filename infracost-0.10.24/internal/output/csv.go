@@ -0,0 +1,334 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// cell is a value of a row in the csv and xlsx formats. Numbers are kept as
+// decimals so that spreadsheets can treat them as numbers rather than
+// formatted costs.
+type cell struct {
+	text   string
+	number *decimal.Decimal
+}
+
+func textCell(s string) cell {
+	return cell{text: s}
+}
+
+func numberCell(d *decimal.Decimal) cell {
+	return cell{number: d}
+}
+
+func (c cell) String() string {
+	if c.number != nil {
+		return c.number.String()
+	}
+
+	return c.text
+}
+
+// costComponentRow is a cost component flattened into a row of the csv and
+// xlsx formats. Past is the matching cost component of the past breakdown,
+// so either Current or Past can be nil if the cost component was added or
+// removed.
+type costComponentRow struct {
+	Project       string
+	Resource      string
+	ResourceType  string
+	Tags          map[string]string
+	CostComponent string
+	Current       *CostComponent
+	Past          *CostComponent
+}
+
+// flatCostComponent is a cost component of a resource or one of its
+// sub-resources, along with the top-level resource it belongs to.
+type flatCostComponent struct {
+	key      string
+	resource *Resource
+	name     string
+	c        *CostComponent
+}
+
+// hasPastBreakdown returns true if any of the projects have a past breakdown,
+// in which case the past and diff columns are shown.
+func hasPastBreakdown(out Root) bool {
+	for _, p := range out.Projects {
+		if p.PastBreakdown != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// costComponentRows returns a row for every cost component of the projects.
+// Cost components that only exist in the past breakdowns are listed after
+// the others of their project.
+func costComponentRows(out Root) []costComponentRow {
+	rows := make([]costComponentRow, 0)
+
+	for _, p := range out.Projects {
+		var current, past []flatCostComponent
+		if p.Breakdown != nil {
+			current = flattenCostComponents(p.Breakdown.Resources)
+		}
+		if p.PastBreakdown != nil {
+			past = flattenCostComponents(p.PastBreakdown.Resources)
+		}
+
+		pastByKey := make(map[string]*CostComponent, len(past))
+		for _, f := range past {
+			pastByKey[f.key] = f.c
+		}
+
+		seen := make(map[string]bool, len(current))
+		for _, f := range current {
+			seen[f.key] = true
+			rows = append(rows, newCostComponentRow(p.Label(), f, f.c, pastByKey[f.key]))
+		}
+
+		for _, f := range past {
+			if !seen[f.key] {
+				rows = append(rows, newCostComponentRow(p.Label(), f, nil, f.c))
+			}
+		}
+	}
+
+	return rows
+}
+
+func newCostComponentRow(project string, f flatCostComponent, current, past *CostComponent) costComponentRow {
+	return costComponentRow{
+		Project:       project,
+		Resource:      f.resource.Name,
+		ResourceType:  f.resource.ResourceType,
+		Tags:          f.resource.Tags,
+		CostComponent: f.name,
+		Current:       current,
+		Past:          past,
+	}
+}
+
+// flattenCostComponents returns the cost components of the resources and
+// their sub-resources. The names of cost components of sub-resources are
+// prefixed with the sub-resource names, e.g. "root_block_device → Storage".
+func flattenCostComponents(resources []Resource) []flatCostComponent {
+	flat := make([]flatCostComponent, 0)
+
+	for i := range resources {
+		r := &resources[i]
+		counts := make(map[string]int)
+		flat = appendFlatCostComponents(flat, counts, r, r, "")
+	}
+
+	return flat
+}
+
+func appendFlatCostComponents(flat []flatCostComponent, counts map[string]int, top *Resource, r *Resource, prefix string) []flatCostComponent {
+	for i := range r.CostComponents {
+		c := &r.CostComponents[i]
+		name := prefix + c.Name
+
+		// Resources can have more than one cost component with the same name so
+		// the key includes how many have been seen so far.
+		counts[name]++
+		key := fmt.Sprintf("%s\x00%s\x00%d", top.Name, name, counts[name])
+
+		flat = append(flat, flatCostComponent{key: key, resource: top, name: name, c: c})
+	}
+
+	for i := range r.SubResources {
+		s := &r.SubResources[i]
+		flat = appendFlatCostComponents(flat, counts, top, s, prefix+s.Name+" → ")
+	}
+
+	return flat
+}
+
+func costComponentHeaders(currency string, withPast bool) []string {
+	headers := []string{
+		"Project",
+		"Resource",
+		"Resource type",
+		"Tags",
+		"Cost component",
+		"Unit",
+		"Monthly quantity",
+		fmt.Sprintf("Price (%s)", currency),
+		fmt.Sprintf("Hourly cost (%s)", currency),
+		fmt.Sprintf("Monthly cost (%s)", currency),
+	}
+
+	if withPast {
+		headers = append(headers,
+			"Past monthly quantity",
+			fmt.Sprintf("Past price (%s)", currency),
+			fmt.Sprintf("Past hourly cost (%s)", currency),
+			fmt.Sprintf("Past monthly cost (%s)", currency),
+			fmt.Sprintf("Diff hourly cost (%s)", currency),
+			fmt.Sprintf("Diff monthly cost (%s)", currency),
+		)
+	}
+
+	return headers
+}
+
+func (r costComponentRow) cells(withPast bool) []cell {
+	unit := ""
+	if r.Current != nil {
+		unit = r.Current.Unit
+	} else if r.Past != nil {
+		unit = r.Past.Unit
+	}
+
+	cells := []cell{
+		textCell(r.Project),
+		textCell(r.Resource),
+		textCell(r.ResourceType),
+		textCell(formatTags(r.Tags)),
+		textCell(r.CostComponent),
+		textCell(unit),
+	}
+	cells = append(cells, costComponentCells(r.Current)...)
+
+	if withPast {
+		cells = append(cells, costComponentCells(r.Past)...)
+
+		var hourlyCost, monthlyCost, pastHourlyCost, pastMonthlyCost *decimal.Decimal
+		if r.Current != nil {
+			hourlyCost, monthlyCost = r.Current.HourlyCost, r.Current.MonthlyCost
+		}
+		if r.Past != nil {
+			pastHourlyCost, pastMonthlyCost = r.Past.HourlyCost, r.Past.MonthlyCost
+		}
+
+		cells = append(cells,
+			numberCell(diffDecimals(hourlyCost, pastHourlyCost)),
+			numberCell(diffDecimals(monthlyCost, pastMonthlyCost)),
+		)
+	}
+
+	return cells
+}
+
+func costComponentCells(c *CostComponent) []cell {
+	if c == nil {
+		return []cell{{}, {}, {}, {}}
+	}
+
+	return []cell{
+		numberCell(c.MonthlyQuantity),
+		numberCell(decimalPtr(c.Price)),
+		numberCell(c.HourlyCost),
+		numberCell(c.MonthlyCost),
+	}
+}
+
+// diffDecimals returns a minus b, treating nil as zero unless both are nil.
+func diffDecimals(a, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil && b == nil {
+		return nil
+	}
+
+	return decimalPtr(zeroIfNil(a).Sub(*zeroIfNil(b)))
+}
+
+// formatTags returns the tags sorted by key, e.g. "env=prod; team=infra".
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, tags[k]))
+	}
+
+	return strings.Join(pairs, "; ")
+}
+
+// ToCSV returns the cost components of the projects as CSV, with one row per
+// cost component. If the resources are grouped there is a row per group
+// instead.
+func ToCSV(out Root, opts Options) ([]byte, error) {
+	withPast := hasPastBreakdown(out)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	headers := costComponentHeaders(out.Currency, withPast)
+	var rows [][]cell
+	if out.GroupBy != "" {
+		headers = groupHeaders(out, withPast)
+		rows = groupRows(out, withPast)
+	} else {
+		for _, row := range costComponentRows(out) {
+			rows = append(rows, row.cells(withPast))
+		}
+	}
+
+	err := w.Write(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cells := range rows {
+		record := make([]string, 0, len(cells))
+		for _, c := range cells {
+			record = append(record, c.String())
+		}
+
+		err = w.Write(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func groupHeaders(out Root, withPast bool) []string {
+	headers := []string{out.groupByTitle(), "Resources"}
+	if withPast {
+		headers = append(headers, fmt.Sprintf("Past monthly cost (%s)", out.Currency))
+	}
+	headers = append(headers, fmt.Sprintf("Monthly cost (%s)", out.Currency))
+	if withPast {
+		headers = append(headers, fmt.Sprintf("Diff monthly cost (%s)", out.Currency))
+	}
+
+	return headers
+}
+
+func groupRows(out Root, withPast bool) [][]cell {
+	rows := make([][]cell, 0, len(out.Groups))
+	for _, g := range out.Groups {
+		row := []cell{textCell(g.Name), numberCell(decimalPtr(decimal.NewFromInt(int64(g.ResourceCount))))}
+		if withPast {
+			row = append(row, numberCell(g.PastMonthlyCost))
+		}
+		row = append(row, numberCell(g.MonthlyCost))
+		if withPast {
+			row = append(row, numberCell(g.DiffMonthlyCost))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
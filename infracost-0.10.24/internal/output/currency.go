@@ -0,0 +1,157 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/currency"
+)
+
+// ExchangeRate records an exchange rate that was used to convert costs to the
+// output currency.
+type ExchangeRate struct {
+	From string          `json:"from"`
+	To   string          `json:"to"`
+	Rate decimal.Decimal `json:"rate"`
+	// Date is the date of the exchange rates file the rate is from.
+	Date string `json:"date,omitempty"`
+}
+
+// CombineWithExchangeRates combines the inputs the same as Combine, but first
+// converts the costs of any inputs that are not in the given currency using
+// the exchange rates. The rates used are recorded in the combined metadata.
+func CombineWithExchangeRates(inputs []ReportInput, rates *currency.ExchangeRates, to string) (Root, error) {
+	if to == "" {
+		to = currency.BaseCurrency
+	}
+
+	converted := make([]ReportInput, 0, len(inputs))
+	applied := make([]ExchangeRate, 0)
+	seen := map[string]bool{}
+
+	for _, input := range inputs {
+		from := input.Root.Currency
+		if from == "" {
+			from = currency.BaseCurrency
+		}
+
+		if from != to {
+			rate, err := rates.ConversionRate(from, to)
+			if err != nil {
+				return Root{}, fmt.Errorf("Error converting Infracost JSON from %s to %s. %w", from, to, err)
+			}
+
+			convert := currencyConverter(func(d decimal.Decimal) decimal.Decimal {
+				// Rates are relative to the base currency, so convert through it
+				// rather than multiplying by the rate to avoid rounding errors.
+				v, _ := rates.Convert(d, from, to)
+				return v
+			})
+			input.Root = convert.root(input.Root, to)
+
+			if !seen[from] {
+				seen[from] = true
+				applied = append(applied, ExchangeRate{From: from, To: to, Rate: rate, Date: rates.Date})
+			}
+		}
+
+		converted = append(converted, input)
+	}
+
+	combined, err := Combine(converted)
+	if len(applied) > 0 {
+		combined.Metadata.ExchangeRates = applied
+	}
+
+	return combined, err
+}
+
+// currencyConverter converts an amount from one currency to another.
+type currencyConverter func(decimal.Decimal) decimal.Decimal
+
+// root returns r with all its costs and prices converted to the currency. The
+// projects of r are modified in place.
+func (convert currencyConverter) root(r Root, to string) Root {
+	r.Currency = to
+	r.TotalHourlyCost = convert.ptr(r.TotalHourlyCost)
+	r.TotalMonthlyCost = convert.ptr(r.TotalMonthlyCost)
+	r.PastTotalHourlyCost = convert.ptr(r.PastTotalHourlyCost)
+	r.PastTotalMonthlyCost = convert.ptr(r.PastTotalMonthlyCost)
+	r.DiffTotalHourlyCost = convert.ptr(r.DiffTotalHourlyCost)
+	r.DiffTotalMonthlyCost = convert.ptr(r.DiffTotalMonthlyCost)
+
+	convert.projection(r.Projection)
+
+	for _, p := range r.Projects {
+		convert.breakdown(p.PastBreakdown)
+		convert.breakdown(p.Breakdown)
+		convert.breakdown(p.Diff)
+		convert.projection(p.Projection)
+	}
+
+	return r
+}
+
+func (convert currencyConverter) projection(months []ProjectedMonth) {
+	for i := range months {
+		months[i].MonthlyCost = convert.ptr(months[i].MonthlyCost)
+		months[i].CumulativeCost = convert.ptr(months[i].CumulativeCost)
+	}
+}
+
+func (convert currencyConverter) breakdown(b *Breakdown) {
+	if b == nil {
+		return
+	}
+
+	b.TotalHourlyCost = convert.ptr(b.TotalHourlyCost)
+	b.TotalMonthlyCost = convert.ptr(b.TotalMonthlyCost)
+	convert.resources(b.Resources)
+}
+
+func (convert currencyConverter) resources(resources []Resource) {
+	for i := range resources {
+		r := &resources[i]
+		r.HourlyCost = convert.ptr(r.HourlyCost)
+		r.MonthlyCost = convert.ptr(r.MonthlyCost)
+		convert.costComponents(r.CostComponents)
+
+		for _, ac := range r.ActualCosts {
+			convert.costComponents(ac.CostComponents)
+		}
+
+		convert.resources(r.SubResources)
+	}
+}
+
+func (convert currencyConverter) costComponents(costComponents []CostComponent) {
+	for i := range costComponents {
+		c := &costComponents[i]
+		c.Price = convert(c.Price)
+		c.HourlyCost = convert.ptr(c.HourlyCost)
+		c.MonthlyCost = convert.ptr(c.MonthlyCost)
+		c.ListPrice = convert.ptr(c.ListPrice)
+
+		if c.Discount != nil {
+			c.Discount = &Discount{
+				Name:        c.Discount.Name,
+				Price:       convert(c.Discount.Price),
+				HourlyCost:  convert.ptr(c.Discount.HourlyCost),
+				MonthlyCost: convert.ptr(c.Discount.MonthlyCost),
+			}
+		}
+
+		for j := range c.PriceCandidates {
+			c.PriceCandidates[j].Price = convert(c.PriceCandidates[j].Price)
+		}
+	}
+}
+
+func (convert currencyConverter) ptr(d *decimal.Decimal) *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+
+	return decimalPtr(convert(*d))
+}
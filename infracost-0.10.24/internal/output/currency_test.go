@@ -0,0 +1,67 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/currency"
+)
+
+func newCurrencyTestRoot(name, currency string, monthlyCost int64) Root {
+	cost := decimalPtr(decimal.NewFromInt(monthlyCost))
+
+	return Root{
+		Currency:         currency,
+		TotalMonthlyCost: cost,
+		Projects: Projects{
+			{
+				Name: name,
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: cost,
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							MonthlyCost: cost,
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Price: decimal.NewFromInt(monthlyCost), MonthlyCost: cost},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCombineWithExchangeRates(t *testing.T) {
+	rates, err := currency.LoadExchangeRatesFromString("version: 0.1\ndate: 2024-01-31\nrates:\n  EUR: 0.8\n")
+	require.NoError(t, err)
+
+	inputs := []ReportInput{
+		{Root: newCurrencyTestRoot("usd", "USD", 100)},
+		{Root: newCurrencyTestRoot("eur", "EUR", 80)},
+	}
+
+	_, err = Combine(inputs)
+	assert.EqualError(t, err, "Invalid Infracost JSON file currency mismatch.  Can't combine USD and EUR")
+
+	combined, err := CombineWithExchangeRates(inputs, rates, "EUR")
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", combined.Currency)
+	assert.Equal(t, "160", combined.TotalMonthlyCost.String())
+	assert.Equal(t, "80", combined.Projects[0].Breakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "80", combined.Projects[0].Breakdown.Resources[0].CostComponents[0].Price.String())
+	require.Len(t, combined.Metadata.ExchangeRates, 1)
+	rate := combined.Metadata.ExchangeRates[0]
+	assert.Equal(t, "USD", rate.From)
+	assert.Equal(t, "EUR", rate.To)
+	assert.Equal(t, "0.8", rate.Rate.String())
+	assert.Equal(t, "2024-01-31", rate.Date)
+
+	_, err = CombineWithExchangeRates([]ReportInput{{Root: newCurrencyTestRoot("gbp", "GBP", 1)}}, rates, "EUR")
+	assert.EqualError(t, err, "Error converting Infracost JSON from GBP to EUR. No exchange rate for GBP, the exchange rates file has rates for EUR")
+}
@@ -294,6 +294,17 @@ func costComponentToDiff(currency string, diffComponent CostComponent, oldCompon
 		)
 	}
 
+	// Show the discount of the cost component, or of the past cost component
+	// if it was removed.
+	discounted := newComponent
+	if discounted == nil {
+		discounted = oldComponent
+	}
+
+	if discounted != nil && discounted.Discount != nil {
+		s += fmt.Sprintf("  %s\n", ui.FaintString(formatDiscount(currency, *discounted)))
+	}
+
 	return s
 }
 
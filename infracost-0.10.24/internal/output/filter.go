@@ -0,0 +1,596 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/shopspring/decimal"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type filterLevel int
+
+const (
+	filterProjects filterLevel = iota
+	filterResources
+	filterCostComponents
+)
+
+// Filter is an expression used to filter the projects, resources or cost
+// components of an output, e.g.
+//
+//	glob(project.name, "prod-*") && resource.monthlyCost > 100
+//
+// Expressions use the HCL expression syntax. What is filtered depends on the
+// variables the expression references: if it references component then cost
+// components are filtered, if it references resource then resources are
+// filtered, otherwise projects are filtered.
+type Filter struct {
+	source string
+	expr   hcl.Expression
+	level  filterLevel
+}
+
+// ParseFilter parses a filter expression and checks it only references the
+// project, resource and component variables.
+func ParseFilter(s string) (*Filter, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(s), "filter", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("Invalid filter %q: %s", s, diagsMessage(diags))
+	}
+
+	f := &Filter{source: s, expr: expr}
+
+	for _, traversal := range expr.Variables() {
+		switch name := traversal.RootName(); name {
+		case "project":
+		case "resource":
+			if f.level < filterResources {
+				f.level = filterResources
+			}
+		case "component":
+			f.level = filterCostComponents
+		default:
+			return nil, fmt.Errorf("Invalid filter %q: unknown variable %s, valid variables are project, resource and component", s, name)
+		}
+	}
+
+	return f, nil
+}
+
+// Apply removes the projects, resources or cost components of r that don't
+// match the filter and recalculates the costs and summaries of r. Projects
+// that have no matching resources are removed when filtering resources or
+// cost components. Projected costs can't be split by resource so they are
+// only kept when filtering projects.
+func (f *Filter) Apply(r *Root) error {
+	projects := make([]Project, 0, len(r.Projects))
+
+	for _, p := range r.Projects {
+		projectVal := filterProjectValue(p)
+
+		if f.level == filterProjects {
+			ok, err := f.match(map[string]cty.Value{"project": projectVal})
+			if err != nil {
+				return err
+			}
+			if ok {
+				projects = append(projects, p)
+			}
+			continue
+		}
+
+		keep, err := f.matchResources(p, projectVal)
+		if err != nil {
+			return err
+		}
+
+		p.Breakdown = filterBreakdown(p.Breakdown, keep)
+		p.PastBreakdown = filterBreakdown(p.PastBreakdown, keep)
+		p.Projection = nil
+
+		if breakdownLen(p.Breakdown) == 0 && breakdownLen(p.PastBreakdown) == 0 {
+			continue
+		}
+
+		projects = append(projects, p)
+	}
+
+	r.Projects = projects
+	r.RecalculateCosts()
+	r.Projection = sumProjections(projects)
+
+	if f.level == filterProjects {
+		summaries := make([]*Summary, 0, len(projects))
+		for _, p := range projects {
+			summaries = append(summaries, p.Summary)
+		}
+		r.Summary = MergeSummaries(summaries)
+
+		return nil
+	}
+
+	return r.recalculateSummaries()
+}
+
+// matchResources returns the keys of the resources that match the filter,
+// and the cost components of each resource that match when filtering cost
+// components.
+func (f *Filter) matchResources(p Project, projectVal cty.Value) (map[string]*filterMatch, error) {
+	keep := make(map[string]*filterMatch)
+
+	for _, pair := range pairResources(p) {
+		resourceVal := filterResourceValue(pair.current, pair.past)
+		vars := map[string]cty.Value{
+			"project":  projectVal,
+			"resource": resourceVal,
+		}
+
+		if f.level == filterResources {
+			ok, err := f.match(vars)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				keep[pair.key] = nil
+			}
+			continue
+		}
+
+		m := &filterMatch{components: make(map[string]bool)}
+		for _, c := range pairCostComponents("", pair.current, pair.past) {
+			vars["component"] = filterComponentValue(c.current, c.past)
+
+			ok, err := f.match(vars)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				m.components[c.key] = true
+			}
+		}
+
+		if len(m.components) > 0 {
+			keep[pair.key] = m
+		}
+	}
+
+	return keep, nil
+}
+
+func (f *Filter) match(vars map[string]cty.Value) (bool, error) {
+	ctx := &hcl.EvalContext{
+		Variables: vars,
+		Functions: filterFunctions,
+	}
+
+	v, diags := f.expr.Value(ctx)
+	if diags.HasErrors() {
+		return false, fmt.Errorf("Error evaluating filter %q: %s", f.source, diagsMessage(diags))
+	}
+
+	v, err := convert.Convert(v, cty.Bool)
+	if err != nil || v.IsNull() {
+		return false, fmt.Errorf("Error evaluating filter %q: the filter must be a bool", f.source)
+	}
+
+	return v.True(), nil
+}
+
+func diagsMessage(diags hcl.Diagnostics) string {
+	msgs := make([]string, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity != hcl.DiagError {
+			continue
+		}
+
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return strings.Join(msgs, ", ")
+}
+
+// filterMatch is a resource that matched the filter. components is the keys
+// of the matching cost components, it's nil when filtering resources.
+type filterMatch struct {
+	components map[string]bool
+}
+
+func filterBreakdown(b *Breakdown, keep map[string]*filterMatch) *Breakdown {
+	if b == nil {
+		return nil
+	}
+
+	keys := filterKeys{}
+	resources := make([]Resource, 0, len(b.Resources))
+	for _, r := range b.Resources {
+		m, ok := keep[keys.next(r.Name)]
+		if !ok {
+			continue
+		}
+
+		if m != nil {
+			var hasComponents bool
+			r, hasComponents = filterResourceCostComponents("", r, m.components)
+			if !hasComponents {
+				continue
+			}
+		}
+
+		resources = append(resources, r)
+	}
+
+	return &Breakdown{Resources: resources}
+}
+
+// filterResourceCostComponents returns a copy of the resource with only the
+// matching cost components, subresources without any are removed.
+func filterResourceCostComponents(prefix string, r Resource, components map[string]bool) (Resource, bool) {
+	componentKeys := filterKeys{}
+	costComponents := make([]CostComponent, 0, len(r.CostComponents))
+	for _, c := range r.CostComponents {
+		if components[prefix+componentKeys.next(c.Name)] {
+			costComponents = append(costComponents, c)
+		}
+	}
+
+	subresourceKeys := filterKeys{}
+	subresources := make([]Resource, 0, len(r.SubResources))
+	for _, s := range r.SubResources {
+		if filtered, ok := filterResourceCostComponents(prefix+subresourceKeys.next(s.Name)+"/", s, components); ok {
+			subresources = append(subresources, filtered)
+		}
+	}
+
+	r.CostComponents = costComponents
+	r.SubResources = subresources
+	r.HourlyCost = nil
+	r.MonthlyCost = nil
+
+	return r, len(costComponents) > 0 || len(subresources) > 0
+}
+
+func breakdownLen(b *Breakdown) int {
+	if b == nil {
+		return 0
+	}
+
+	return len(b.Resources)
+}
+
+// recalculateSummaries rebuilds the summaries of the projects and r from the
+// resources of the projects. Unsupported and free resources aren't included
+// in the resources so they are no longer counted.
+func (r *Root) recalculateSummaries() error {
+	summaries := make([]*Summary, 0, len(r.Projects))
+
+	for i := range r.Projects {
+		p := &r.Projects[i]
+
+		var resources []*schema.Resource
+		if p.Breakdown != nil {
+			resources = convertOutputResources(p.Breakdown.Resources)
+		}
+
+		summary, err := BuildSummary(resources, SummaryOptions{OnlyFields: projectSummaryFields})
+		if err != nil {
+			return err
+		}
+		p.Summary = summary
+		summaries = append(summaries, summary)
+	}
+
+	r.Summary = MergeSummaries(summaries)
+
+	return nil
+}
+
+// filterKeys gives each of a list of names a key that is unique within the
+// list. The key is the name followed by how many times the name has come
+// before it, so items with the same name, e.g. two cost components called
+// Storage, can still be told apart and are paired in the order they appear.
+type filterKeys map[string]int
+
+func (k filterKeys) next(name string) string {
+	n := k[name]
+	k[name]++
+
+	return fmt.Sprintf("%s#%d", name, n)
+}
+
+// resourcePair is a resource with the same key in the current and past
+// breakdown of a project. Either can be nil if the resource was added or
+// removed.
+type resourcePair struct {
+	key     string
+	current *Resource
+	past    *Resource
+}
+
+func pairResources(p Project) []resourcePair {
+	var pairs []resourcePair
+	index := make(map[string]int)
+
+	if p.Breakdown != nil {
+		keys := filterKeys{}
+		for i := range p.Breakdown.Resources {
+			r := &p.Breakdown.Resources[i]
+			key := keys.next(r.Name)
+			index[key] = len(pairs)
+			pairs = append(pairs, resourcePair{key: key, current: r})
+		}
+	}
+
+	if p.PastBreakdown != nil {
+		keys := filterKeys{}
+		for i := range p.PastBreakdown.Resources {
+			r := &p.PastBreakdown.Resources[i]
+			key := keys.next(r.Name)
+			if j, ok := index[key]; ok {
+				pairs[j].past = r
+				continue
+			}
+			index[key] = len(pairs)
+			pairs = append(pairs, resourcePair{key: key, past: r})
+		}
+	}
+
+	return pairs
+}
+
+// costComponentPair is a cost component with the same key in the current and
+// past versions of a resource. The key is the filter key of the cost
+// component prefixed by the keys of the subresources it's nested in.
+type costComponentPair struct {
+	key     string
+	current *CostComponent
+	past    *CostComponent
+}
+
+func pairCostComponents(prefix string, current, past *Resource) []costComponentPair {
+	var pairs []costComponentPair
+	index := make(map[string]int)
+
+	add := func(key string, c *CostComponent, isPast bool) {
+		key = prefix + key
+		j, ok := index[key]
+		if !ok {
+			j = len(pairs)
+			index[key] = j
+			pairs = append(pairs, costComponentPair{key: key})
+		}
+
+		if isPast {
+			pairs[j].past = c
+		} else {
+			pairs[j].current = c
+		}
+	}
+
+	if current != nil {
+		keys := filterKeys{}
+		for i := range current.CostComponents {
+			c := &current.CostComponents[i]
+			add(keys.next(c.Name), c, false)
+		}
+	}
+	if past != nil {
+		keys := filterKeys{}
+		for i := range past.CostComponents {
+			c := &past.CostComponents[i]
+			add(keys.next(c.Name), c, true)
+		}
+	}
+
+	for _, s := range pairResources(Project{
+		Breakdown:     subresourceBreakdown(current),
+		PastBreakdown: subresourceBreakdown(past),
+	}) {
+		pairs = append(pairs, pairCostComponents(prefix+s.key+"/", s.current, s.past)...)
+	}
+
+	return pairs
+}
+
+func subresourceBreakdown(r *Resource) *Breakdown {
+	if r == nil {
+		return nil
+	}
+
+	return &Breakdown{Resources: r.SubResources}
+}
+
+func filterProjectValue(p Project) cty.Value {
+	var path, workspace, modulePath string
+	if p.Metadata != nil {
+		path = p.Metadata.Path
+		workspace = p.Metadata.TerraformWorkspace
+		modulePath = p.Metadata.TerraformModulePath
+	}
+
+	var current, past *decimal.Decimal
+	if p.Breakdown != nil {
+		current = p.Breakdown.TotalMonthlyCost
+	}
+	if p.PastBreakdown != nil {
+		past = p.PastBreakdown.TotalMonthlyCost
+	}
+
+	attrs := map[string]cty.Value{
+		"name":       cty.StringVal(p.Name),
+		"path":       cty.StringVal(path),
+		"workspace":  cty.StringVal(workspace),
+		"modulePath": cty.StringVal(modulePath),
+	}
+	addFilterCosts(attrs, current, past)
+
+	return cty.ObjectVal(attrs)
+}
+
+func filterResourceValue(current, past *Resource) cty.Value {
+	r := current
+	if r == nil {
+		r = past
+	}
+
+	tags := make(map[string]cty.Value, len(r.Tags))
+	for k, v := range r.Tags {
+		tags[k] = cty.StringVal(v)
+	}
+	tagsVal := cty.MapValEmpty(cty.String)
+	if len(tags) > 0 {
+		tagsVal = cty.MapVal(tags)
+	}
+
+	attrs := map[string]cty.Value{
+		"name": cty.StringVal(r.Name),
+		"type": cty.StringVal(resourceType(*r)),
+		"tags": tagsVal,
+	}
+
+	var currentCost, pastCost *decimal.Decimal
+	if current != nil {
+		currentCost = current.MonthlyCost
+	}
+	if past != nil {
+		pastCost = past.MonthlyCost
+	}
+	addFilterCosts(attrs, currentCost, pastCost)
+
+	return cty.ObjectVal(attrs)
+}
+
+func filterComponentValue(current, past *CostComponent) cty.Value {
+	c := current
+	if c == nil {
+		c = past
+	}
+
+	attrs := map[string]cty.Value{
+		"name":            cty.StringVal(c.Name),
+		"unit":            cty.StringVal(c.Unit),
+		"price":           decimalVal(&c.Price),
+		"monthlyQuantity": decimalVal(c.MonthlyQuantity),
+	}
+
+	var currentCost, pastCost *decimal.Decimal
+	if current != nil {
+		currentCost = current.MonthlyCost
+	}
+	if past != nil {
+		pastCost = past.MonthlyCost
+	}
+	addFilterCosts(attrs, currentCost, pastCost)
+
+	return cty.ObjectVal(attrs)
+}
+
+// addFilterCosts adds the monthly cost, past monthly cost and diff attributes.
+// diffPercent is the diff as a percentage of the past monthly cost, or 100 if
+// there was no past cost and the cost has increased.
+func addFilterCosts(attrs map[string]cty.Value, current, past *decimal.Decimal) {
+	c := decimal.Zero
+	if current != nil {
+		c = *current
+	}
+	p := decimal.Zero
+	if past != nil {
+		p = *past
+	}
+
+	diff := c.Sub(p)
+	percent := decimal.Zero
+	if !p.IsZero() {
+		percent = diff.Div(p).Mul(decimal.NewFromInt(100))
+	} else if diff.IsPositive() {
+		percent = decimal.NewFromInt(100)
+	}
+
+	attrs["monthlyCost"] = decimalVal(&c)
+	attrs["pastMonthlyCost"] = decimalVal(&p)
+	attrs["diffMonthlyCost"] = decimalVal(&diff)
+	attrs["diffPercent"] = decimalVal(&percent)
+}
+
+func decimalVal(d *decimal.Decimal) cty.Value {
+	if d == nil {
+		return cty.Zero
+	}
+
+	return cty.NumberVal(d.BigFloat())
+}
+
+var filterFunctions = map[string]function.Function{
+	"glob":       globFunc,
+	"matches":    matchesFunc,
+	"contains":   stringPredicateFunc(strings.Contains),
+	"startswith": stringPredicateFunc(strings.HasPrefix),
+	"endswith":   stringPredicateFunc(strings.HasSuffix),
+	"lower":      stdlib.LowerFunc,
+	"upper":      stdlib.UpperFunc,
+	"lookup":     stdlib.LookupFunc,
+}
+
+// globFunc matches a string against a glob pattern where * matches any
+// characters, including /, and ? matches a single character.
+var globFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+		{Name: "pattern", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		pattern := regexp.QuoteMeta(args[1].AsString())
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+
+		re, err := regexp.Compile("^" + pattern + "$")
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), err
+		}
+
+		return cty.BoolVal(re.MatchString(args[0].AsString())), nil
+	},
+})
+
+// matchesFunc matches a string against a regular expression.
+var matchesFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+		{Name: "pattern", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		re, err := regexp.Compile(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), err
+		}
+
+		return cty.BoolVal(re.MatchString(args[0].AsString())), nil
+	},
+})
+
+func stringPredicateFunc(fn func(s, substr string) bool) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "substr", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.BoolVal(fn(args[0].AsString(), args[1].AsString())), nil
+		},
+	})
+}
@@ -0,0 +1,211 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func filterTestRoot() Root {
+	component := func(name string, cost int64) CostComponent {
+		return CostComponent{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	r := Root{
+		Projects: []Project{
+			{
+				Name: "prod-app",
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							CostComponents: []CostComponent{
+								component("Instance usage", 100),
+							},
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{component("Storage", 10)}},
+							},
+						},
+						{Name: "aws_s3_bucket.logs", ResourceType: "aws_s3_bucket", CostComponents: []CostComponent{component("Storage", 5)}},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							Tags:         map[string]string{"team": "web"},
+							CostComponents: []CostComponent{
+								component("Instance usage", 105),
+							},
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{component("Storage", 20)}},
+							},
+						},
+						{Name: "aws_s3_bucket.logs", ResourceType: "aws_s3_bucket", CostComponents: []CostComponent{component("Storage", 5)}},
+					},
+				},
+				Diff:       &Breakdown{},
+				Projection: []ProjectedMonth{{Month: "2023-01", MonthlyCost: decimalPtr(decimal.NewFromInt(130))}},
+			},
+			{
+				Name: "dev-app",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []CostComponent{component("Instance usage", 200)}},
+					},
+				},
+				Projection: []ProjectedMonth{{Month: "2023-01", MonthlyCost: decimalPtr(decimal.NewFromInt(200))}},
+			},
+		},
+	}
+	r.RecalculateCosts()
+
+	return r
+}
+
+func filterResourceNames(r Root) []string {
+	var names []string
+	for _, p := range r.Projects {
+		for _, res := range p.Breakdown.Resources {
+			names = append(names, p.Name+":"+res.Name)
+		}
+	}
+
+	return names
+}
+
+func TestFilterProjects(t *testing.T) {
+	r := filterTestRoot()
+
+	f, err := ParseFilter(`glob(project.name, "prod-*")`)
+	require.NoError(t, err)
+	require.NoError(t, f.Apply(&r))
+
+	assert.Equal(t, []string{"prod-app:aws_instance.web", "prod-app:aws_s3_bucket.logs"}, filterResourceNames(r))
+	assert.Equal(t, "130", r.TotalMonthlyCost.String())
+	assert.Equal(t, "115", r.PastTotalMonthlyCost.String())
+	assert.Equal(t, "15", r.DiffTotalMonthlyCost.String())
+	require.Len(t, r.Projection, 1)
+	assert.Equal(t, "130", r.Projection[0].MonthlyCost.String())
+}
+
+func TestFilterResources(t *testing.T) {
+	r := filterTestRoot()
+
+	f, err := ParseFilter(`resource.monthlyCost > 100 && lookup(resource.tags, "team", "") == "web"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Apply(&r))
+
+	assert.Equal(t, []string{"prod-app:aws_instance.web"}, filterResourceNames(r))
+	assert.Equal(t, "125", r.TotalMonthlyCost.String())
+	assert.Equal(t, "110", r.PastTotalMonthlyCost.String())
+	assert.Nil(t, r.Projection)
+	assert.Equal(t, 1, *r.Summary.TotalSupportedResources)
+}
+
+func TestFilterCostComponents(t *testing.T) {
+	r := filterTestRoot()
+
+	f, err := ParseFilter(`component.diffPercent > 10`)
+	require.NoError(t, err)
+	require.NoError(t, f.Apply(&r))
+
+	// The dev project has no past costs so its cost component is a 100% increase.
+	assert.Equal(t, []string{"prod-app:aws_instance.web", "dev-app:aws_instance.web"}, filterResourceNames(r))
+
+	web := r.Projects[0].Breakdown.Resources[0]
+	assert.Empty(t, web.CostComponents)
+	require.Len(t, web.SubResources, 1)
+	assert.Equal(t, "20", web.MonthlyCost.String())
+	assert.Equal(t, "20", r.Projects[0].Breakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "10", r.Projects[0].PastBreakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "10", r.Projects[0].Diff.TotalMonthlyCost.String())
+	assert.Equal(t, "220", r.TotalMonthlyCost.String())
+}
+
+func TestFilterCostComponentsWithSameName(t *testing.T) {
+	component := func(name string, cost int64) CostComponent {
+		return CostComponent{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	r := Root{
+		Projects: []Project{
+			{
+				Name: "prod-app",
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", CostComponents: []CostComponent{component("Storage", 10), component("Storage", 50)}},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", CostComponents: []CostComponent{component("Storage", 10), component("Storage", 80)}},
+					},
+				},
+				Diff: &Breakdown{},
+			},
+		},
+	}
+	r.RecalculateCosts()
+
+	f, err := ParseFilter(`component.diffMonthlyCost > 0`)
+	require.NoError(t, err)
+	require.NoError(t, f.Apply(&r))
+
+	// Only the second Storage cost component has changed, so the first is
+	// removed from both breakdowns.
+	web := r.Projects[0].Breakdown.Resources[0]
+	require.Len(t, web.CostComponents, 1)
+	assert.Equal(t, "80", web.CostComponents[0].MonthlyCost.String())
+
+	pastWeb := r.Projects[0].PastBreakdown.Resources[0]
+	require.Len(t, pastWeb.CostComponents, 1)
+	assert.Equal(t, "50", pastWeb.CostComponents[0].MonthlyCost.String())
+	assert.Equal(t, "30", r.Projects[0].Diff.TotalMonthlyCost.String())
+}
+
+func TestFilterFunctions(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected []string
+	}{
+		{`matches(resource.name, "^aws_s3_")`, []string{"prod-app:aws_s3_bucket.logs"}},
+		{`startswith(upper(project.name), "DEV")`, []string{"dev-app:aws_instance.web"}},
+		{`endswith(resource.type, "bucket") || contains(project.name, "dev")`, []string{"prod-app:aws_s3_bucket.logs", "dev-app:aws_instance.web"}},
+		{`glob(resource.name, "aws_?nstance.*") && project.monthlyCost < 150`, []string{"prod-app:aws_instance.web"}},
+		{`lower(component.name) == "storage" && component.monthlyCost >= 20`, []string{"prod-app:aws_instance.web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			r := filterTestRoot()
+
+			f, err := ParseFilter(tt.filter)
+			require.NoError(t, err)
+			require.NoError(t, f.Apply(&r))
+
+			assert.Equal(t, tt.expected, filterResourceNames(r))
+		})
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	_, err := ParseFilter(`resource.monthlyCost >`)
+	assert.ErrorContains(t, err, `Invalid filter "resource.monthlyCost >"`)
+
+	_, err = ParseFilter(`cost > 100`)
+	assert.EqualError(t, err, `Invalid filter "cost > 100": unknown variable cost, valid variables are project, resource and component`)
+
+	r := filterTestRoot()
+	f, err := ParseFilter(`resource.name`)
+	require.NoError(t, err)
+	assert.EqualError(t, f.Apply(&r), `Error evaluating filter "resource.name": the filter must be a bool`)
+
+	f, err = ParseFilter(`resource.cost > 100`)
+	require.NoError(t, err)
+	assert.ErrorContains(t, f.Apply(&r), "Unsupported attribute")
+}
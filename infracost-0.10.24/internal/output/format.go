@@ -44,6 +44,72 @@ func formatPrice(currency string, d decimal.Decimal) string {
 	return formatRoundedDecimalCurrency(currency, d)
 }
 
+// formatDiscount describes how a discount changed the price of the cost
+// component, e.g. "EDP: list price $0.0416, discount $0.0042, effective price
+// $0.0374 per hours".
+func formatDiscount(currency string, c CostComponent) string {
+	if c.Discount == nil || c.ListPrice == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s: list price %s, discount %s, effective price %s per %s",
+		c.Discount.Name,
+		formatPrice(currency, *c.ListPrice),
+		formatPrice(currency, c.Discount.Price),
+		formatPrice(currency, c.Price),
+		c.Unit,
+	)
+}
+
+// discountedCostComponent is a cost component of a resource that had a
+// discount rule applied to its price.
+type discountedCostComponent struct {
+	Resource      string
+	CostComponent CostComponent
+}
+
+// discountedCostComponents returns the discounted cost components of the
+// resources that changed in the projects that are shown, so the discounts
+// behind the cost changes can be listed alongside them. The resource names of
+// sub-resources include the names of their parents.
+func discountedCostComponents(out Root, opts Options) []discountedCostComponent {
+	var discounted []discountedCostComponent
+
+	for _, p := range out.Projects {
+		if !showProject(p, opts, true) || p.Diff == nil || p.Breakdown == nil {
+			continue
+		}
+
+		for _, diffResource := range p.Diff.Resources {
+			r := findResourceByName(p.Breakdown.Resources, diffResource.Name)
+			if r != nil {
+				discounted = appenddiscountedCostComponents(discounted, *r, "")
+			}
+		}
+	}
+
+	return discounted
+}
+
+func appenddiscountedCostComponents(discounted []discountedCostComponent, r Resource, parent string) []discountedCostComponent {
+	name := r.Name
+	if parent != "" {
+		name = fmt.Sprintf("%s → %s", parent, r.Name)
+	}
+
+	for _, c := range r.CostComponents {
+		if c.Discount != nil && c.ListPrice != nil {
+			discounted = append(discounted, discountedCostComponent{Resource: name, CostComponent: c})
+		}
+	}
+
+	for _, s := range r.SubResources {
+		discounted = appenddiscountedCostComponents(discounted, s, name)
+	}
+
+	return discounted
+}
+
 func formatFullDecimalCurrency(currency string, d decimal.Decimal) string {
 	formatter := money.GetCurrency(currency).Formatter()
 	scaledInt := decimalToScaledInt(d, formatter.Fraction, 10)
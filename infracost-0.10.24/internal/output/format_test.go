@@ -8,6 +8,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
 )
 
 func TestFormatCost(t *testing.T) {
@@ -166,3 +168,66 @@ func TestFormatPrice(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatDiscount(t *testing.T) {
+	listPrice := decimal.RequireFromString("0.0416")
+
+	c := CostComponent{
+		Name:      "Instance usage",
+		Unit:      "hours",
+		Price:     decimal.RequireFromString("0.03744"),
+		ListPrice: &listPrice,
+		Discount: &Discount{
+			Name:  "EDP",
+			Price: decimal.RequireFromString("0.00416"),
+		},
+	}
+
+	require.Equal(t, "EDP: list price $0.0416, discount $0.00416, effective price $0.03744 per hours", formatDiscount("USD", c))
+	require.Equal(t, "", formatDiscount("USD", CostComponent{Name: "Instance usage"}))
+}
+
+func TestDiscountsInMarkdownAndSlackMessage(t *testing.T) {
+	listPrice := decimal.RequireFromString("0.0416")
+	discounted := CostComponent{
+		Name:        "Instance usage",
+		Unit:        "hours",
+		Price:       decimal.RequireFromString("0.03744"),
+		MonthlyCost: decimalPtr(decimal.RequireFromString("27.3312")),
+		ListPrice:   &listPrice,
+		Discount: &Discount{
+			Name:  "EDP",
+			Price: decimal.RequireFromString("0.00416"),
+		},
+	}
+
+	resources := []Resource{
+		{
+			Name:           "aws_instance.web",
+			MonthlyCost:    discounted.MonthlyCost,
+			CostComponents: []CostComponent{discounted},
+		},
+	}
+
+	out := Root{
+		Currency:         "USD",
+		TotalMonthlyCost: discounted.MonthlyCost,
+		Projects: []Project{
+			{
+				Name:          "infracost/infracost/examples",
+				Metadata:      &schema.ProjectMetadata{},
+				PastBreakdown: &Breakdown{Resources: []Resource{}},
+				Breakdown:     &Breakdown{Resources: resources, TotalMonthlyCost: discounted.MonthlyCost},
+				Diff:          &Breakdown{Resources: resources, TotalMonthlyCost: discounted.MonthlyCost},
+			},
+		},
+	}
+
+	md, err := ToMarkdown(out, Options{}, MarkdownOptions{BasicSyntax: true})
+	require.NoError(t, err)
+	require.Contains(t, string(md), "| aws_instance.web | Instance usage | EDP | $0.0416 | $0.00416 | $0.03744 per hours |")
+
+	msg, err := ToSlackMessage(out, Options{})
+	require.NoError(t, err)
+	require.Contains(t, string(msg), "`aws_instance.web` → Instance usage: EDP: list price $0.0416, discount $0.00416, effective price $0.03744 per hours")
+}
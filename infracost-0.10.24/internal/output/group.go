@@ -0,0 +1,263 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	GroupByTag          = "tag"
+	GroupByResourceType = "resourceType"
+	GroupByModule       = "module"
+	GroupByRegion       = "region"
+	GroupByProvider     = "provider"
+	GroupByProject      = "project"
+)
+
+// ValidGroupBys are the values supported by the --group-by flag. Tags are
+// specified as tag:<key>.
+var ValidGroupBys = []string{"tag:<key>", GroupByResourceType, GroupByModule, GroupByRegion, GroupByProvider, GroupByProject}
+
+var moduleAddressRegex = regexp.MustCompile(`^((?:module\.[^.\[]+(?:\[[^\]]*\])?\.)*)`)
+
+// GroupBy is how resource costs are aggregated. Key is only used when
+// grouping by tag and is the tag key.
+type GroupBy struct {
+	Kind string
+	Key  string
+}
+
+// CostGroup is the aggregated cost of the resources with the same group
+// value, e.g. the resources tagged team=payments.
+type CostGroup struct {
+	Name            string           `json:"name"`
+	ResourceCount   int              `json:"resourceCount"`
+	PastMonthlyCost *decimal.Decimal `json:"pastMonthlyCost"`
+	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	DiffMonthlyCost *decimal.Decimal `json:"diffMonthlyCost"`
+}
+
+// ParseGroupBy parses a --group-by value, e.g. tag:team or resourceType.
+func ParseGroupBy(s string) (GroupBy, error) {
+	if strings.HasPrefix(s, GroupByTag+":") {
+		key := strings.TrimPrefix(s, GroupByTag+":")
+		if key == "" {
+			return GroupBy{}, fmt.Errorf("missing tag key in group by %q, use tag:<key>", s)
+		}
+
+		return GroupBy{Kind: GroupByTag, Key: key}, nil
+	}
+
+	for _, kind := range []string{GroupByResourceType, GroupByModule, GroupByRegion, GroupByProvider, GroupByProject} {
+		if strings.EqualFold(s, kind) {
+			return GroupBy{Kind: kind}, nil
+		}
+	}
+
+	return GroupBy{}, fmt.Errorf("invalid group by %q, supported values are %s", s, strings.Join(ValidGroupBys, ", "))
+}
+
+func (g GroupBy) String() string {
+	if g.Kind == GroupByTag {
+		return GroupByTag + ":" + g.Key
+	}
+
+	return g.Kind
+}
+
+// Title returns the heading of the group column.
+func (g GroupBy) Title() string {
+	switch g.Kind {
+	case GroupByTag:
+		return "Tag: " + g.Key
+	case GroupByResourceType:
+		return "Resource type"
+	case GroupByModule:
+		return "Module"
+	case GroupByRegion:
+		return "Region"
+	case GroupByProvider:
+		return "Provider"
+	case GroupByProject:
+		return "Project"
+	}
+
+	return g.Kind
+}
+
+// fallback returns the name of the group for resources that don't have a
+// value, e.g. untagged resources.
+func (g GroupBy) fallback() string {
+	switch g.Kind {
+	case GroupByTag:
+		return "untagged"
+	case GroupByModule:
+		return "root module"
+	case GroupByRegion:
+		return "no region"
+	}
+
+	return "unknown"
+}
+
+func (g GroupBy) value(p Project, r Resource) string {
+	switch g.Kind {
+	case GroupByTag:
+		return r.Tags[g.Key]
+	case GroupByResourceType:
+		return resourceType(r)
+	case GroupByModule:
+		return strings.TrimSuffix(moduleAddressRegex.FindString(r.Name), ".")
+	case GroupByRegion:
+		return resourceRegion(r)
+	case GroupByProvider:
+		t := resourceType(r)
+		if i := strings.Index(t, "_"); i > 0 {
+			return t[:i]
+		}
+	case GroupByProject:
+		return p.Label()
+	}
+
+	return ""
+}
+
+// resourceType returns the type of the resource. Infracost JSON files from
+// older versions don't include the type so it is taken from the address.
+func resourceType(r Resource) string {
+	if r.ResourceType != "" {
+		return r.ResourceType
+	}
+
+	address := strings.TrimPrefix(r.Name, moduleAddressRegex.FindString(r.Name))
+	if i := strings.Index(address, "."); i > 0 {
+		return address[:i]
+	}
+
+	return ""
+}
+
+// resourceRegion returns the region recorded in the resource metadata. JSON
+// outputs from older versions don't have it, so the region of the products
+// that the prices of the resource were found for is used instead, which is
+// only recorded with the price hashes.
+func resourceRegion(r Resource) string {
+	if region, ok := r.Metadata["region"].(string); ok && region != "" {
+		return region
+	}
+
+	for _, c := range r.CostComponents {
+		if c.PriceProduct != nil && c.PriceProduct.Region != "" {
+			return c.PriceProduct.Region
+		}
+	}
+
+	for _, s := range r.SubResources {
+		if region := resourceRegion(s); region != "" {
+			return region
+		}
+	}
+
+	return ""
+}
+
+// GroupResources aggregates the past and current monthly costs of the
+// resources of all the projects by the group by value. Resources that have
+// changed group, e.g. because a tag was changed, count towards the past cost
+// of their old group and the current cost of their new group. The groups are
+// sorted by monthly cost with the group of resources without a value last.
+func GroupResources(out Root, groupBy GroupBy) []CostGroup {
+	withPast := hasPastBreakdown(out)
+
+	type groupTotals struct {
+		pastCost  decimal.Decimal
+		cost      decimal.Decimal
+		resources map[string]bool
+	}
+
+	totals := make(map[string]*groupTotals)
+
+	add := func(p Project, r Resource, past bool) {
+		name := groupBy.value(p, r)
+		if name == "" {
+			name = groupBy.fallback()
+		}
+
+		t, ok := totals[name]
+		if !ok {
+			t = &groupTotals{resources: make(map[string]bool)}
+			totals[name] = t
+		}
+
+		t.resources[p.Label()+"\x00"+r.Name] = true
+
+		if r.MonthlyCost == nil {
+			return
+		}
+
+		if past {
+			t.pastCost = t.pastCost.Add(*r.MonthlyCost)
+		} else {
+			t.cost = t.cost.Add(*r.MonthlyCost)
+		}
+	}
+
+	for _, p := range out.Projects {
+		if p.PastBreakdown != nil {
+			for _, r := range p.PastBreakdown.Resources {
+				add(p, r, true)
+			}
+		}
+
+		if p.Breakdown != nil {
+			for _, r := range p.Breakdown.Resources {
+				add(p, r, false)
+			}
+		}
+	}
+
+	groups := make([]CostGroup, 0, len(totals))
+	for name, t := range totals {
+		g := CostGroup{
+			Name:          name,
+			ResourceCount: len(t.resources),
+			MonthlyCost:   decimalPtr(t.cost),
+		}
+
+		if withPast {
+			g.PastMonthlyCost = decimalPtr(t.pastCost)
+			g.DiffMonthlyCost = decimalPtr(t.cost.Sub(t.pastCost))
+		}
+
+		groups = append(groups, g)
+	}
+
+	fallback := groupBy.fallback()
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Name == fallback) != (groups[j].Name == fallback) {
+			return groups[j].Name == fallback
+		}
+
+		if !groups[i].MonthlyCost.Equal(*groups[j].MonthlyCost) {
+			return groups[i].MonthlyCost.GreaterThan(*groups[j].MonthlyCost)
+		}
+
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups
+}
+
+// groupByTitle returns the heading of the group column of the output.
+func (r *Root) groupByTitle() string {
+	g, err := ParseGroupBy(r.GroupBy)
+	if err != nil {
+		return r.GroupBy
+	}
+
+	return g.Title()
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	g, err := ParseGroupBy("tag:team")
+	require.NoError(t, err)
+	assert.Equal(t, GroupBy{Kind: GroupByTag, Key: "team"}, g)
+	assert.Equal(t, "tag:team", g.String())
+
+	g, err = ParseGroupBy("resourcetype")
+	require.NoError(t, err)
+	assert.Equal(t, GroupBy{Kind: GroupByResourceType}, g)
+
+	_, err = ParseGroupBy("tag:")
+	assert.Error(t, err)
+
+	_, err = ParseGroupBy("team")
+	assert.Error(t, err)
+}
+
+func TestGroupResources(t *testing.T) {
+	r := Root{
+		Projects: []Project{
+			{
+				Name: "dev",
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", Tags: map[string]string{"team": "web"}, MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							Tags:        map[string]string{"team": "payments"},
+							MonthlyCost: decimalPtr(decimal.NewFromInt(20)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", PriceProduct: &PriceProduct{Region: "eu-west-1"}},
+							},
+						},
+						{Name: "module.db.aws_db_instance.this[0]", Tags: map[string]string{"team": "payments"}, MonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+						{Name: "aws_s3_bucket.logs"},
+					},
+				},
+			},
+		},
+	}
+
+	groups := GroupResources(r, GroupBy{Kind: GroupByTag, Key: "team"})
+	require.Len(t, groups, 3)
+	assert.Equal(t, "payments", groups[0].Name)
+	assert.Equal(t, 2, groups[0].ResourceCount)
+	assert.Equal(t, "0", groups[0].PastMonthlyCost.String())
+	assert.Equal(t, "25", groups[0].MonthlyCost.String())
+	assert.Equal(t, "web", groups[1].Name)
+	assert.Equal(t, "-10", groups[1].DiffMonthlyCost.String())
+	assert.Equal(t, "untagged", groups[2].Name)
+	assert.Equal(t, 1, groups[2].ResourceCount)
+
+	groups = GroupResources(r, GroupBy{Kind: GroupByRegion})
+	require.Len(t, groups, 2)
+	assert.Equal(t, "eu-west-1", groups[0].Name)
+	assert.Equal(t, "no region", groups[1].Name)
+	assert.Equal(t, 3, groups[1].ResourceCount)
+
+	groups = GroupResources(r, GroupBy{Kind: GroupByModule})
+	require.Len(t, groups, 2)
+	assert.Equal(t, "root module", groups[1].Name)
+	assert.Equal(t, "module.db", groups[0].Name)
+
+	groups = GroupResources(r, GroupBy{Kind: GroupByProvider})
+	require.Len(t, groups, 1)
+	assert.Equal(t, "aws", groups[0].Name)
+}
+
+func TestGroupResourcesByRegionWithoutPriceHashes(t *testing.T) {
+	partial := func(resourceType, address, region, productRegion string) *schema.PartialResource {
+		raw := gjson.Result{}
+		if region != "" {
+			raw = schema.AddRawValue(raw, "region", region)
+		}
+
+		return &schema.PartialResource{
+			ResourceData: schema.NewResourceData(resourceType, "", address, nil, raw),
+			Resource: &schema.Resource{
+				Name: address,
+				CostComponents: []*schema.CostComponent{
+					{
+						Name:           "Usage",
+						UnitMultiplier: decimal.NewFromInt(1),
+						ProductFilter:  &schema.ProductFilter{Region: &productRegion},
+					},
+				},
+			},
+		}
+	}
+
+	project := schema.NewProject("dev", &schema.ProjectMetadata{})
+	project.HasDiff = false
+	project.Resources = []*schema.Resource{
+		schema.BuildResource(partial("aws_instance", "aws_instance.web", "eu-west-1", "eu-west-1"), nil),
+		schema.BuildResource(partial("azurerm_linux_virtual_machine", "azurerm_linux_virtual_machine.app", "", "westeurope"), nil),
+		schema.BuildResource(partial("aws_s3_bucket", "aws_s3_bucket.logs", "", ""), nil),
+	}
+
+	r, err := ToOutputFormat([]*schema.Project{project})
+	require.NoError(t, err)
+
+	groups := GroupResources(r, GroupBy{Kind: GroupByRegion})
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+
+	assert.ElementsMatch(t, []string{"eu-west-1", "westeurope", "no region"}, names)
+}
@@ -46,8 +46,16 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 		"filterZeroValResources":  filterZeroValResources,
 		"formatCost2DP":           func(d *decimal.Decimal) string { return FormatCost2DP(out.Currency, d) },
 		"formatPrice":             func(d decimal.Decimal) string { return formatPrice(out.Currency, d) },
+		"formatDiscount":          func(c CostComponent) string { return formatDiscount(out.Currency, c) },
 		"formatTitleWithCurrency": func(title string) string { return formatTitleWithCurrency(title, out.Currency) },
 		"formatQuantity":          formatQuantity,
+		"resourceSearchText":      resourceSearchText,
+		"costValue": func(d *decimal.Decimal) string {
+			if d == nil {
+				return "0"
+			}
+			return d.String()
+		},
 		"projectLabel": func(p Project) string {
 			return p.Label()
 		},
@@ -69,7 +77,8 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 		Root           Root
 		SummaryMessage string
 		Options        Options
-	}{out, summaryMessage, opts})
+		Treemap        treemap
+	}{out, summaryMessage, opts, buildTreemap(out)})
 	if err != nil {
 		return []byte{}, err
 	}
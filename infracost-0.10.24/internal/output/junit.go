@@ -0,0 +1,240 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	junitClassCostEstimate = "infracost.cost-estimate"
+	junitClassPolicy       = "infracost.policy"
+	junitClassGuardrail    = "infracost.guardrail"
+	junitClassTagPolicy    = "infracost.tag-policy"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+// junitText is written as CDATA so multi-line messages stay readable.
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.TestCases = append(s.TestCases, tc)
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+}
+
+// ToJUnit returns the cost estimates and the results of the policy,
+// guardrail and tag policy checks as JUnit XML so that CI systems can show
+// them as test results. Each project is a test suite with a test case for
+// its cost estimate and for each resource of it that fails or warns on a tag
+// policy. The policy and guardrail checks, and the tag policies that passed,
+// apply to the whole run so are added as a separate test suite.
+func ToJUnit(out Root, opts Options) ([]byte, error) {
+	root := junitTestSuites{Name: "Infracost"}
+
+	for _, p := range out.Projects {
+		root.Suites = append(root.Suites, junitProjectSuite(p, out.Currency, opts.TagPolicyCheck))
+	}
+
+	if checks := junitChecksSuite(opts); checks.Tests > 0 {
+		root.Suites = append(root.Suites, checks)
+	}
+
+	for _, s := range root.Suites {
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+	}
+
+	b, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func junitProjectSuite(p Project, currency string, tagPolicyCheck TagPolicyCheck) junitTestSuite {
+	suite := junitTestSuite{Name: p.Label()}
+
+	pastCost, cost, diffCost := projectMonthlyCosts(p)
+	suite.Properties = &junitProperties{Properties: []junitProperty{
+		{Name: "currency", Value: currency},
+		{Name: "monthlyCost", Value: decimalString(cost)},
+		{Name: "pastMonthlyCost", Value: decimalString(pastCost)},
+		{Name: "diffMonthlyCost", Value: decimalString(diffCost)},
+	}}
+
+	estimate := junitTestCase{Name: "Cost estimate", ClassName: junitClassCostEstimate}
+	if p.Metadata != nil && p.Metadata.HasErrors() {
+		messages := make([]string, 0, len(p.Metadata.Errors))
+		for _, e := range p.Metadata.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		estimate.Failure = &junitFailure{
+			Message: "The cost estimate could not be generated",
+			Type:    "ProjectError",
+			Text:    strings.Join(messages, "\n"),
+		}
+	} else {
+		estimate.SystemOut = &junitText{Text: fmt.Sprintf("Monthly cost: %s\nPast monthly cost: %s\nDiff: %s",
+			formatCost(currency, cost),
+			formatCost(currency, pastCost),
+			formatCostChange(currency, diffCost),
+		)}
+	}
+	suite.add(estimate)
+
+	for _, tp := range tagPolicyCheck.FailingTagPolicies {
+		for _, r := range tp.Resources {
+			if !contains(r.ProjectNames, p.Name) {
+				continue
+			}
+
+			suite.add(junitTestCase{
+				Name:      fmt.Sprintf("%s: %s", tp.Name, r.Address),
+				ClassName: junitClassTagPolicy,
+				Failure: &junitFailure{
+					Message: tp.Message,
+					Type:    "TagPolicy",
+					Text:    tagPolicyResourceText(r),
+				},
+			})
+		}
+	}
+
+	for _, tp := range tagPolicyCheck.WarningTagPolicies {
+		for _, r := range tp.Resources {
+			if !contains(r.ProjectNames, p.Name) {
+				continue
+			}
+
+			suite.add(junitTestCase{
+				Name:      fmt.Sprintf("%s: %s", tp.Name, r.Address),
+				ClassName: junitClassTagPolicy,
+				SystemOut: &junitText{Text: fmt.Sprintf("Warning: %s\n%s", tp.Message, tagPolicyResourceText(r))},
+			})
+		}
+	}
+
+	return suite
+}
+
+// junitChecksSuite returns a test suite for the policy and guardrail checks,
+// and the tag policies that passed, which aren't specific to a project.
+func junitChecksSuite(opts Options) junitTestSuite {
+	suite := junitTestSuite{Name: "Infracost policies"}
+
+	for _, tp := range opts.TagPolicyCheck.PassingTagPolicies {
+		suite.add(junitTestCase{Name: tp.Name, ClassName: junitClassTagPolicy})
+	}
+
+	for _, name := range opts.PolicyChecks.Passed {
+		suite.add(junitTestCase{Name: name, ClassName: junitClassPolicy})
+	}
+
+	for _, f := range opts.PolicyChecks.Failures {
+		suite.add(junitTestCase{
+			Name:      f,
+			ClassName: junitClassPolicy,
+			Failure:   &junitFailure{Message: f, Type: "PolicyCheck"},
+		})
+	}
+
+	for _, e := range opts.GuardrailCheck.GuardrailEvents {
+		// Events that are neither reported nor blocking are hidden, the same
+		// as in PR comments.
+		if !e.PRComment && !e.BlockPR {
+			continue
+		}
+
+		tc := junitTestCase{Name: e.TriggerReason, ClassName: junitClassGuardrail}
+		if e.BlockPR {
+			tc.Failure = &junitFailure{Message: e.TriggerReason, Type: "GuardrailCheck"}
+		} else {
+			tc.SystemOut = &junitText{Text: "Warning: " + e.TriggerReason}
+		}
+
+		suite.add(tc)
+	}
+
+	return suite
+}
+
+func projectMonthlyCosts(p Project) (pastCost, cost, diffCost *decimal.Decimal) {
+	if p.PastBreakdown != nil {
+		pastCost = p.PastBreakdown.TotalMonthlyCost
+	}
+	if p.Breakdown != nil {
+		cost = p.Breakdown.TotalMonthlyCost
+	}
+	if p.Diff != nil {
+		diffCost = p.Diff.TotalMonthlyCost
+	}
+
+	return pastCost, cost, diffCost
+}
+
+func tagPolicyResourceText(r TagPolicyResource) string {
+	lines := []string{r.Address}
+	if r.Path != "" {
+		lines[0] = fmt.Sprintf("%s (%s:%d)", r.Address, r.Path, r.Line)
+	}
+
+	for _, f := range r.Failures() {
+		lines = append(lines, "- "+f)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func decimalString(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.String()
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToJUnitTagPolicies(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{Name: "dev", Metadata: &schema.ProjectMetadata{}},
+			{Name: "prod", Metadata: &schema.ProjectMetadata{Errors: []schema.ProjectDiag{{Message: "could not parse main.tf"}}}},
+		},
+	}
+
+	opts := Options{
+		TagPolicyCheck: NewTagPolicyChecks([]TagPolicy{
+			{
+				Name:      "Mandatory tags",
+				Message:   "All resources must be tagged",
+				PrComment: true,
+				BlockPr:   true,
+				Resources: []TagPolicyResource{
+					{Address: "aws_instance.web", Path: "main.tf", Line: 3, ProjectNames: []string{"dev"}, MissingMandatoryTags: []string{"team"}},
+				},
+			},
+			{
+				Name:      "Cost center",
+				Message:   "Resources should have a cost center",
+				PrComment: true,
+				Resources: []TagPolicyResource{
+					{Address: "aws_s3_bucket.logs", ProjectNames: []string{"dev"}, MissingMandatoryTags: []string{"cost-center"}},
+				},
+			},
+			{Name: "Environment", PrComment: true},
+		}),
+	}
+
+	b, err := ToJUnit(r, opts)
+	require.NoError(t, err)
+
+	out := string(b)
+	assert.Contains(t, out, `<testsuites name="Infracost" tests="5" failures="2">`)
+	assert.Contains(t, out, `<testsuite name="dev" tests="3" failures="1">`)
+	assert.Contains(t, out, `<testcase name="Mandatory tags: aws_instance.web" classname="infracost.tag-policy">
+      <failure message="All resources must be tagged" type="TagPolicy"><![CDATA[aws_instance.web (main.tf:3)
+- should have mandatory tags: "team"]]></failure>`)
+	assert.Contains(t, out, `<testcase name="Cost center: aws_s3_bucket.logs" classname="infracost.tag-policy">
+      <system-out><![CDATA[Warning: Resources should have a cost center`)
+	assert.Contains(t, out, `<testsuite name="prod" tests="1" failures="1">`)
+	assert.Contains(t, out, `<failure message="The cost estimate could not be generated" type="ProjectError"><![CDATA[could not parse main.tf]]></failure>`)
+
+	// Passing tag policies are only added once, to the suite for the whole run.
+	assert.Contains(t, out, `<testsuite name="Infracost policies" tests="1" failures="0">
+    <testcase name="Environment" classname="infracost.tag-policy"></testcase>`)
+	assert.Equal(t, 1, strings.Count(out, `<testcase name="Environment"`))
+}
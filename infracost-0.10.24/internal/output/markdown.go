@@ -222,8 +222,15 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 			}
 			return placeholders
 		},
+		"discountedCostComponents": func() []discountedCostComponent {
+			return discountedCostComponents(out, opts)
+		},
+		"formatPrice": func(d decimal.Decimal) string {
+			return formatPrice(out.Currency, d)
+		},
 		"stringsJoin":    strings.Join,
 		"truncateMiddle": truncateMiddle,
+		"groupByTitle":   out.groupByTitle,
 	})
 	_, err := tmpl.ParseFS(templatesFS, "templates/"+filename)
 	if err != nil {
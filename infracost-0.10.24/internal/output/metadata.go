@@ -26,6 +26,13 @@ type Metadata struct {
 	VCSPullRequestLabels []string `json:"vcsPullRequestLabels,omitempty"`
 	VCSPipelineRunID     string   `json:"vcsPipelineRunId,omitempty"`
 	VCSPullRequestID     string   `json:"vcsPullRequestId,omitempty"`
+
+	// PriceDate is the date the costs were priced at if it wasn't the current date.
+	PriceDate string `json:"priceDate,omitempty"`
+	// PastPriceDate is the date the past costs were priced at if it wasn't the current date.
+	PastPriceDate string `json:"pastPriceDate,omitempty"`
+	// ExchangeRates are the rates used to convert the costs to the output currency.
+	ExchangeRates []ExchangeRate `json:"exchangeRates,omitempty"`
 }
 
 // NewMetadata returns a Metadata struct filled with information built from the RunContext.
@@ -39,6 +46,14 @@ func NewMetadata(ctx *config.RunContext) Metadata {
 		CommitTimestamp:   ctx.VCSMetadata.Commit.Time.UTC(),
 		CommitMessage:     ctx.VCSMetadata.Commit.Message,
 		VCSRepositoryURL:  ctx.VCSRepositoryURL(),
+		PriceDate:         ctx.Config.PriceDate,
+	}
+
+	if rates := ctx.Config.ExchangeRates; rates != nil && ctx.Config.PricingCurrency() != ctx.Config.Currency {
+		rate, err := rates.ConversionRate(ctx.Config.PricingCurrency(), ctx.Config.Currency)
+		if err == nil {
+			m.ExchangeRates = []ExchangeRate{{From: ctx.Config.PricingCurrency(), To: ctx.Config.Currency, Rate: rate, Date: rates.Date}}
+		}
 	}
 
 	if ctx.VCSMetadata.PullRequest != nil {
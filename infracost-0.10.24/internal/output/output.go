@@ -38,6 +38,9 @@ type Root struct {
 	DiffTotalMonthlyCost *decimal.Decimal `json:"diffTotalMonthlyCost"`
 	TimeGenerated        time.Time        `json:"timeGenerated"`
 	Summary              *Summary         `json:"summary"`
+	GroupBy              string           `json:"groupBy,omitempty"`
+	Groups               []CostGroup      `json:"groups,omitempty"`
+	Projection           []ProjectedMonth `json:"projection,omitempty"`
 	FullSummary          *Summary         `json:"-"`
 	IsCIRun              bool             `json:"-"`
 }
@@ -76,6 +79,7 @@ type Project struct {
 	Breakdown     *Breakdown              `json:"breakdown"`
 	Diff          *Breakdown              `json:"diff"`
 	Summary       *Summary                `json:"summary"`
+	Projection    []ProjectedMonth        `json:"projection,omitempty"`
 	fullSummary   *Summary
 }
 
@@ -135,6 +139,30 @@ func convertCostComponents(outComponents []CostComponent) []*schema.CostComponen
 			MonthlyQuantity: c.MonthlyQuantity,
 		}
 		sc.SetPrice(c.Price)
+		sc.SetPriceHash(c.PriceHash)
+		sc.SetPriceSource(c.PriceSource)
+
+		if c.ListPrice != nil && c.Discount != nil {
+			sc.SetDiscount(&schema.Discount{
+				Name:            c.Discount.Name,
+				ListPrice:       *c.ListPrice,
+				ListHourlyCost:  addDecimals(c.HourlyCost, c.Discount.HourlyCost),
+				ListMonthlyCost: addDecimals(c.MonthlyCost, c.Discount.MonthlyCost),
+			})
+		}
+
+		if len(c.PriceCandidates) > 0 {
+			candidates := make([]*schema.PriceCandidate, len(c.PriceCandidates))
+			for j, pc := range c.PriceCandidates {
+				candidates[j] = &schema.PriceCandidate{
+					Sku:        pc.Sku,
+					Attributes: pc.Attributes,
+					Price:      pc.Price,
+					Unit:       pc.Unit,
+				}
+			}
+			sc.SetPriceCandidates(candidates)
+		}
 
 		components[i] = sc
 	}
@@ -235,8 +263,58 @@ type CostComponent struct {
 	HourlyQuantity  *decimal.Decimal `json:"hourlyQuantity"`
 	MonthlyQuantity *decimal.Decimal `json:"monthlyQuantity"`
 	Price           decimal.Decimal  `json:"price"`
-	HourlyCost      *decimal.Decimal `json:"hourlyCost"`
-	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	// PriceHash and PriceSource identify the price that was used and the
+	// pricing source it was found in. They are only included in the output
+	// of runs with --include-price-hashes.
+	PriceHash   string           `json:"priceHash,omitempty"`
+	PriceSource string           `json:"priceSource,omitempty"`
+	HourlyCost  *decimal.Decimal `json:"hourlyCost"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
+	// ListPrice is the price before any discount was applied. When this is
+	// set the price and costs are the effective values after the discount.
+	ListPrice *decimal.Decimal `json:"listPrice,omitempty"`
+	Discount  *Discount        `json:"discount,omitempty"`
+	// PriceCandidates are the products and prices that matched the filters of
+	// the cost component when there was more than one. The price of the first
+	// candidate is the one that was used. They are only recorded for runs with
+	// --include-price-hashes or --strict-pricing.
+	PriceCandidates []PriceCandidate `json:"priceCandidates,omitempty"`
+	// PriceProduct and UnitMultiplier are recorded along with the price hash
+	// so that the price can be looked up again by infracost reprice. They are
+	// only included in the output of runs with --include-price-hashes and are
+	// not set for cost components with custom prices. UnitMultiplier is not
+	// set if it is 1.
+	PriceProduct   *PriceProduct    `json:"priceProduct,omitempty"`
+	UnitMultiplier *decimal.Decimal `json:"unitMultiplier,omitempty"`
+}
+
+// PriceProduct identifies the product that the price of a cost component was
+// found for.
+type PriceProduct struct {
+	VendorName    string `json:"vendorName"`
+	Service       string `json:"service"`
+	ProductFamily string `json:"productFamily,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Sku           string `json:"sku"`
+}
+
+// PriceCandidate is a product and price that matched the filters of a cost
+// component. The price is per unit of the pricing source, not the unit shown
+// for the cost component.
+type PriceCandidate struct {
+	Sku        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      decimal.Decimal   `json:"price"`
+	Unit       string            `json:"unit"`
+}
+
+// Discount is the amount a cost component was discounted by from its list
+// price by a rule in the discounts file.
+type Discount struct {
+	Name        string           `json:"name"`
+	Price       decimal.Decimal  `json:"price"`
+	HourlyCost  *decimal.Decimal `json:"hourlyCost"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
 }
 
 type ActualCosts struct {
@@ -258,6 +336,46 @@ type Resource struct {
 	SubResources   []Resource             `json:"subresources,omitempty"`
 }
 
+// RemovePriceHashes removes the price hashes, pricing sources and the details
+// recorded to look up the prices again from the cost components of r. They are
+// only included in the output when requested since they would otherwise make
+// the output larger and change it whenever the prices are updated.
+func (r *Root) RemovePriceHashes() {
+	for i := range r.Projects {
+		p := &r.Projects[i]
+		for _, b := range []*Breakdown{p.PastBreakdown, p.Breakdown, p.Diff} {
+			if b == nil {
+				continue
+			}
+
+			for j := range b.Resources {
+				removeResourcePriceHashes(&b.Resources[j])
+			}
+		}
+	}
+}
+
+func removeResourcePriceHashes(r *Resource) {
+	removeCostComponentPriceHashes(r.CostComponents)
+
+	for i := range r.ActualCosts {
+		removeCostComponentPriceHashes(r.ActualCosts[i].CostComponents)
+	}
+
+	for i := range r.SubResources {
+		removeResourcePriceHashes(&r.SubResources[i])
+	}
+}
+
+func removeCostComponentPriceHashes(components []CostComponent) {
+	for i := range components {
+		components[i].PriceHash = ""
+		components[i].PriceSource = ""
+		components[i].PriceProduct = nil
+		components[i].UnitMultiplier = nil
+	}
+}
+
 type Summary struct {
 	TotalResources            *int `json:"totalResources,omitempty"`
 	TotalDetectedResources    *int `json:"totalDetectedResources,omitempty"`
@@ -294,6 +412,9 @@ type Options struct {
 	GuardrailCheck    GuardrailCheck
 	diffMsg           string
 	CurrencyFormat    string
+	// TemplatePath is the path to the user supplied template that is used by
+	// the template output format.
+	TemplatePath string
 }
 
 // PolicyCheck holds information if a given run has any policy checks enabled.
@@ -302,6 +423,10 @@ type PolicyCheck struct {
 	Enabled  bool
 	Failures PolicyCheckFailures
 	Passed   []string
+	// FailureAddresses are the addresses of the resources that caused each
+	// failure, keyed by the failure message. They are only set for policies
+	// that return them.
+	FailureAddresses map[string][]string
 }
 
 // HasFailed returns if the PolicyCheck has any cost policy failures
@@ -622,19 +747,80 @@ func outputResource(r *schema.Resource) Resource {
 func outputCostComponents(costComponents []*schema.CostComponent) []CostComponent {
 	comps := make([]CostComponent, 0, len(costComponents))
 	for _, c := range costComponents {
+		var unitMultiplier *decimal.Decimal
+		priceProduct := outputPriceProduct(c)
+		if priceProduct != nil && !c.UnitMultiplier.Equal(decimal.NewFromInt(1)) {
+			unitMultiplier = decimalPtr(c.UnitMultiplier)
+		}
+
 		comps = append(comps, CostComponent{
 			Name:            c.Name,
 			Unit:            c.Unit,
 			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
 			MonthlyQuantity: c.UnitMultiplierMonthlyQuantity(),
 			Price:           c.UnitMultiplierPrice(),
+			PriceHash:       c.PriceHash(),
+			PriceSource:     c.PriceSource(),
 			HourlyCost:      c.HourlyCost,
 			MonthlyCost:     c.MonthlyCost,
+			ListPrice:       c.UnitMultiplierListPrice(),
+			Discount:        outputDiscount(c),
+			PriceCandidates: outputPriceCandidates(c.PriceCandidates()),
+			PriceProduct:    priceProduct,
+			UnitMultiplier:  unitMultiplier,
 		})
 	}
 	return comps
 }
 
+// outputPriceProduct returns the product that the price of the cost component
+// was found for, or nil if it has a custom price or the sku of the product
+// isn't known.
+func outputPriceProduct(c *schema.CostComponent) *PriceProduct {
+	if c.ProductFilter == nil || c.CustomPrice() != nil || c.PriceSku() == "" {
+		return nil
+	}
+
+	return &PriceProduct{
+		VendorName:    schema.StrValue(c.ProductFilter.VendorName),
+		Service:       schema.StrValue(c.ProductFilter.Service),
+		ProductFamily: schema.StrValue(c.ProductFilter.ProductFamily),
+		Region:        schema.StrValue(c.ProductFilter.Region),
+		Sku:           c.PriceSku(),
+	}
+}
+
+func outputPriceCandidates(candidates []*schema.PriceCandidate) []PriceCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	pcs := make([]PriceCandidate, 0, len(candidates))
+	for _, pc := range candidates {
+		pcs = append(pcs, PriceCandidate{
+			Sku:        pc.Sku,
+			Attributes: pc.Attributes,
+			Price:      pc.Price,
+			Unit:       pc.Unit,
+		})
+	}
+	return pcs
+}
+
+func outputDiscount(c *schema.CostComponent) *Discount {
+	d := c.Discount()
+	if d == nil {
+		return nil
+	}
+
+	return &Discount{
+		Name:        d.Name,
+		Price:       d.ListPrice.Sub(c.Price()).Mul(c.UnitMultiplier),
+		HourlyCost:  subDecimals(d.ListHourlyCost, c.HourlyCost),
+		MonthlyCost: subDecimals(d.ListMonthlyCost, c.MonthlyCost),
+	}
+}
+
 func outputActualCosts(actualCosts []*schema.ActualCosts) []ActualCosts {
 	acs := make([]ActualCosts, 0, len(actualCosts))
 	for _, ac := range actualCosts {
@@ -715,17 +901,7 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 			}
 		}
 
-		summary, err := BuildSummary(project.Resources, SummaryOptions{
-			OnlyFields: []string{
-				"TotalDetectedResources",
-				"TotalSupportedResources",
-				"TotalUnsupportedResources",
-				"TotalUsageBasedResources",
-				"TotalNoPriceResources",
-				"UnsupportedResourceCounts",
-				"NoPriceResourceCounts",
-			},
-		})
+		summary, err := BuildSummary(project.Resources, SummaryOptions{OnlyFields: projectSummaryFields})
 		if err != nil {
 			return Root{}, err
 		}
@@ -744,6 +920,7 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 			Breakdown:     breakdown,
 			Diff:          diff,
 			Summary:       summary,
+			Projection:    outputProjection(project.Projection),
 			fullSummary:   fullSummary,
 		})
 	}
@@ -760,6 +937,7 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 		TimeGenerated:        time.Now().UTC(),
 		Summary:              MergeSummaries(summaries),
 		FullSummary:          MergeSummaries(fullSummaries),
+		Projection:           sumProjections(outProjects),
 	}
 
 	return out, nil
@@ -881,7 +1059,20 @@ func formatCounts(countMap *map[string]int) string {
 }
 
 func hasSupportedTerraformProvider(rType string) bool {
-	return strings.HasPrefix(rType, "aws_") || strings.HasPrefix(rType, "google_") || strings.HasPrefix(rType, "azurerm_")
+	return strings.HasPrefix(rType, "aws_") || strings.HasPrefix(rType, "google_") || strings.HasPrefix(rType, "azurerm_") ||
+		strings.HasPrefix(rType, "AWS::")
+}
+
+// projectSummaryFields are the fields of the summaries of projects that are
+// included in the output.
+var projectSummaryFields = []string{
+	"TotalDetectedResources",
+	"TotalSupportedResources",
+	"TotalUnsupportedResources",
+	"TotalUsageBasedResources",
+	"TotalNoPriceResources",
+	"UnsupportedResourceCounts",
+	"NoPriceResourceCounts",
 }
 
 func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary, error) {
@@ -1083,6 +1274,24 @@ func decimalPtr(d decimal.Decimal) *decimal.Decimal {
 	return &d
 }
 
+// addDecimals returns the sum of a and b, or nil if either is nil.
+func addDecimals(a, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	return decimalPtr(a.Add(*b))
+}
+
+// subDecimals returns a minus b, or nil if either is nil.
+func subDecimals(a, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	return decimalPtr(a.Sub(*b))
+}
+
 func mergeCounts(c1 *map[string]int, c2 *map[string]int) *map[string]int {
 	if c1 == nil && c2 == nil {
 		return nil
@@ -32,3 +32,37 @@ func TestCalculateTotalCosts(t *testing.T) {
 	actual, _ = totalMonthlyCost.Float64()
 	assert.Equal(t, expected, actual)
 }
+
+func TestRemovePriceHashes(t *testing.T) {
+	component := CostComponent{
+		Name:           "Instance usage",
+		PriceHash:      "abc-123",
+		PriceSource:    "api",
+		PriceProduct:   &PriceProduct{VendorName: "aws", Service: "AmazonEC2", Sku: "DQ578CGN99KG6ECF"},
+		UnitMultiplier: decimalPtr(decimal.NewFromInt(1000)),
+	}
+
+	r := Root{
+		Projects: []Project{
+			{
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.web",
+							CostComponents: []CostComponent{component},
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{component}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	r.RemovePriceHashes()
+
+	resource := r.Projects[0].Breakdown.Resources[0]
+	assert.Equal(t, CostComponent{Name: "Instance usage"}, resource.CostComponents[0])
+	assert.Equal(t, CostComponent{Name: "Instance usage"}, resource.SubResources[0].CostComponents[0])
+}
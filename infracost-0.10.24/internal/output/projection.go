@@ -0,0 +1,136 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ProjectedMonth is the projected cost of a month, it's only set when costs
+// are projected with the --projection flag.
+type ProjectedMonth struct {
+	// Month is formatted as YYYY-MM.
+	Month       string           `json:"month"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
+	// CumulativeCost is the total cost from the first month of the projection
+	// to the end of this month.
+	CumulativeCost *decimal.Decimal `json:"cumulativeCost"`
+}
+
+func outputProjection(projection []*schema.ProjectedCost) []ProjectedMonth {
+	if len(projection) == 0 {
+		return nil
+	}
+
+	months := make([]ProjectedMonth, 0, len(projection))
+	for _, p := range projection {
+		months = append(months, ProjectedMonth{
+			Month:       p.Month.Format("2006-01"),
+			MonthlyCost: decimalPtr(p.MonthlyCost),
+		})
+	}
+
+	return withCumulativeCosts(months)
+}
+
+// sumProjections returns the projected costs of all the projects by month.
+// Projects can start their projections in different months, so months that
+// a project doesn't have a projected cost for don't include its cost.
+func sumProjections(projects []Project) []ProjectedMonth {
+	totals := make(map[string]decimal.Decimal)
+
+	for _, p := range projects {
+		for _, m := range p.Projection {
+			if m.MonthlyCost != nil {
+				totals[m.Month] = totals[m.Month].Add(*m.MonthlyCost)
+			}
+		}
+	}
+
+	if len(totals) == 0 {
+		return nil
+	}
+
+	months := make([]ProjectedMonth, 0, len(totals))
+	for month, total := range totals {
+		months = append(months, ProjectedMonth{Month: month, MonthlyCost: decimalPtr(total)})
+	}
+
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Month < months[j].Month
+	})
+
+	return withCumulativeCosts(months)
+}
+
+func withCumulativeCosts(months []ProjectedMonth) []ProjectedMonth {
+	cumulative := decimal.Zero
+	for i := range months {
+		if months[i].MonthlyCost != nil {
+			cumulative = cumulative.Add(*months[i].MonthlyCost)
+		}
+		months[i].CumulativeCost = decimalPtr(cumulative)
+	}
+
+	return months
+}
+
+// projectionTable returns a table of the projected monthly and cumulative
+// costs, with a column for each project if there's more than one.
+func projectionTable(out Root) string {
+	var projects []Project
+	for _, p := range out.Projects {
+		if len(p.Projection) > 0 {
+			projects = append(projects, p)
+		}
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+	t.Style().Format.Footer = text.FormatDefault
+
+	headers := table.Row{"Month"}
+	if len(projects) > 1 {
+		for _, p := range projects {
+			headers = append(headers, truncateMiddle(p.Label(), 30, "..."))
+		}
+	}
+	headers = append(headers, "Monthly cost", "Cumulative cost")
+	t.AppendHeader(headers)
+
+	columns := []table.ColumnConfig{{Number: 1, WidthMin: 10}}
+	for i := 2; i <= len(headers); i++ {
+		columns = append(columns, table.ColumnConfig{Number: i, Align: text.AlignRight, AlignFooter: text.AlignRight})
+	}
+	t.SetColumnConfigs(columns)
+
+	for _, m := range out.Projection {
+		row := table.Row{m.Month}
+		if len(projects) > 1 {
+			for _, p := range projects {
+				row = append(row, formatCost(out.Currency, projectedCost(p, m.Month)))
+			}
+		}
+		row = append(row, formatCost(out.Currency, m.MonthlyCost), formatCost(out.Currency, m.CumulativeCost))
+
+		t.AppendRow(row)
+	}
+
+	return fmt.Sprintf("Projected costs over %d months\n\n%s", len(out.Projection), t.Render())
+}
+
+func projectedCost(p Project, month string) *decimal.Decimal {
+	for _, m := range p.Projection {
+		if m.Month == month {
+			return m.MonthlyCost
+		}
+	}
+
+	return nil
+}
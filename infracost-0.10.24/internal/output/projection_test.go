@@ -0,0 +1,46 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestSumProjections(t *testing.T) {
+	month := func(m time.Month) time.Time {
+		return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	dev := Project{Name: "dev", Projection: outputProjection([]*schema.ProjectedCost{
+		{Month: month(time.January), MonthlyCost: decimal.NewFromInt(10)},
+		{Month: month(time.February), MonthlyCost: decimal.NewFromInt(20)},
+	})}
+	prod := Project{Name: "prod", Projection: outputProjection([]*schema.ProjectedCost{
+		{Month: month(time.February), MonthlyCost: decimal.NewFromInt(100)},
+		{Month: month(time.March), MonthlyCost: decimal.NewFromInt(100)},
+	})}
+
+	require.Len(t, dev.Projection, 2)
+	assert.Equal(t, "2024-02", dev.Projection[1].Month)
+	assert.Equal(t, "30", dev.Projection[1].CumulativeCost.String())
+
+	total := sumProjections([]Project{dev, prod})
+	require.Len(t, total, 3)
+
+	var got []string
+	for _, m := range total {
+		got = append(got, m.Month+" "+m.MonthlyCost.String()+" "+m.CumulativeCost.String())
+	}
+	assert.Equal(t, []string{"2024-01 10 10", "2024-02 120 130", "2024-03 100 230"}, got)
+
+	table := projectionTable(Root{Currency: "USD", Projects: []Project{dev, prod}, Projection: total})
+	assert.True(t, strings.HasPrefix(table, "Projected costs over 3 months"))
+	assert.Contains(t, table, "prod")
+	assert.Contains(t, table, "$230")
+}
@@ -0,0 +1,416 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// DriftReport lists the cost components of an Infracost JSON file whose unit
+// prices changed when it was repriced.
+type DriftReport struct {
+	Currency string       `json:"currency"`
+	Drift    []PriceDrift `json:"drift"`
+	// NotFound are the cost components that no price could be found for. They
+	// keep their previous prices.
+	NotFound []PriceDrift `json:"notFound"`
+	// Skipped is the number of cost components that could not be repriced
+	// because no price hash or product was recorded for them, e.g. because
+	// they have custom prices or the file was generated without
+	// --include-price-hashes.
+	Skipped int `json:"skipped"`
+}
+
+// PriceDrift is the old and new unit price of a cost component. Prices are
+// per the unit shown for the cost component. For discounted cost components
+// these are the list prices.
+type PriceDrift struct {
+	Project       string          `json:"project"`
+	Resource      string          `json:"resource"`
+	CostComponent string          `json:"costComponent"`
+	Unit          string          `json:"unit"`
+	OldPrice      decimal.Decimal `json:"oldPrice"`
+	NewPrice      decimal.Decimal `json:"newPrice"`
+	OldPriceHash  string          `json:"oldPriceHash,omitempty"`
+	NewPriceHash  string          `json:"newPriceHash,omitempty"`
+}
+
+// UnitListPrice returns the price of the cost component before any discount.
+func (c *CostComponent) UnitListPrice() decimal.Decimal {
+	if c.ListPrice != nil {
+		return *c.ListPrice
+	}
+
+	return c.Price
+}
+
+// Repricing holds the cost components of an Infracost JSON that are being
+// repriced. The prices of the cost components of its Resources are looked up
+// again, then Apply updates the Infracost JSON with them.
+type Repricing struct {
+	// Resources hold the cost components used to look up the prices again.
+	// These have the price hash of the previous price set.
+	Resources []*schema.Resource
+	// Skipped is the number of cost components that can't be repriced
+	// because no price hash or product was recorded for them.
+	Skipped int
+
+	root    *Root
+	targets []*repriceTarget
+}
+
+// repriceTarget is a cost component of the Infracost JSON along with the cost
+// component used to look up its price again.
+type repriceTarget struct {
+	project   string
+	resource  string
+	component *CostComponent
+	query     *schema.CostComponent
+}
+
+// NewRepricing returns a Repricing for the cost components of root that have
+// their price hash and product recorded.
+func NewRepricing(root *Root) *Repricing {
+	p := &Repricing{root: root}
+
+	for i := range root.Projects {
+		project := &root.Projects[i]
+
+		for _, b := range []*Breakdown{project.PastBreakdown, project.Breakdown} {
+			if b == nil {
+				continue
+			}
+
+			for j := range b.Resources {
+				p.addResource(project.Name, &b.Resources[j], "", "")
+			}
+		}
+	}
+
+	return p
+}
+
+// Len returns the number of cost components that are being repriced.
+func (p *Repricing) Len() int {
+	return len(p.targets)
+}
+
+func (p *Repricing) addResource(project string, r *Resource, parent, resourceType string) {
+	name := r.Name
+	if parent != "" {
+		name = parent + " → " + r.Name
+	}
+
+	// Sub-resources are matched by the discount rules using the resource type
+	// of their parent.
+	if r.ResourceType != "" {
+		resourceType = r.ResourceType
+	}
+
+	queryResource := &schema.Resource{Name: name, ResourceType: resourceType}
+
+	for i := range r.CostComponents {
+		c := &r.CostComponents[i]
+		if c.PriceHash == "" || c.PriceProduct == nil {
+			p.Skipped++
+			continue
+		}
+
+		q := c.repriceQuery()
+		queryResource.CostComponents = append(queryResource.CostComponents, q)
+		p.targets = append(p.targets, &repriceTarget{project: project, resource: name, component: c, query: q})
+	}
+
+	if len(queryResource.CostComponents) > 0 {
+		p.Resources = append(p.Resources, queryResource)
+	}
+
+	for i := range r.SubResources {
+		p.addResource(project, &r.SubResources[i], name, resourceType)
+	}
+}
+
+// repriceQuery returns the cost component used to look up the price of c
+// again. Its quantities are set so the costs it calculates match the costs of
+// c for the same list price, since the costs of c may include monthly
+// discounts that aren't recorded in the Infracost JSON.
+func (c *CostComponent) repriceQuery() *schema.CostComponent {
+	unitMultiplier := decimal.NewFromInt(1)
+	if c.UnitMultiplier != nil {
+		unitMultiplier = *c.UnitMultiplier
+	}
+
+	listHourlyCost, listMonthlyCost := c.HourlyCost, c.MonthlyCost
+	if c.Discount != nil {
+		listHourlyCost = addDecimals(c.HourlyCost, c.Discount.HourlyCost)
+		listMonthlyCost = addDecimals(c.MonthlyCost, c.Discount.MonthlyCost)
+	}
+
+	quantity := func(cost, q *decimal.Decimal) *decimal.Decimal {
+		listPrice := c.UnitListPrice()
+		if cost != nil && !listPrice.IsZero() {
+			return decimalPtr(cost.Mul(unitMultiplier).Div(listPrice))
+		}
+
+		if q == nil {
+			return nil
+		}
+
+		return decimalPtr(q.Mul(unitMultiplier))
+	}
+
+	q := &schema.CostComponent{
+		Name:            c.Name,
+		Unit:            c.Unit,
+		UnitMultiplier:  unitMultiplier,
+		HourlyQuantity:  quantity(listHourlyCost, c.HourlyQuantity),
+		MonthlyQuantity: quantity(listMonthlyCost, c.MonthlyQuantity),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr(c.PriceProduct.VendorName),
+			Service:       strPtr(c.PriceProduct.Service),
+			ProductFamily: strPtr(c.PriceProduct.ProductFamily),
+			Region:        strPtr(c.PriceProduct.Region),
+			Sku:           strPtr(c.PriceProduct.Sku),
+		},
+	}
+	q.SetPriceHash(c.PriceHash)
+
+	return q
+}
+
+// Apply updates the cost components being repriced with the prices and costs
+// of the cost components used to look up their prices, once these have been
+// looked up again, and recalculates the costs of the Infracost JSON. Cost
+// components that no price was found for keep their previous prices. It
+// returns a report of the prices that changed.
+func (p *Repricing) Apply() *DriftReport {
+	report := &DriftReport{
+		Currency: p.root.Currency,
+		Drift:    []PriceDrift{},
+		NotFound: []PriceDrift{},
+		Skipped:  p.Skipped,
+	}
+
+	for _, t := range p.targets {
+		c, q := t.component, t.query
+
+		drift := PriceDrift{
+			Project:       t.project,
+			Resource:      t.resource,
+			CostComponent: c.Name,
+			Unit:          c.Unit,
+			OldPrice:      c.UnitListPrice(),
+			OldPriceHash:  c.PriceHash,
+		}
+
+		if q.PriceNotFound() != "" {
+			report.NotFound = append(report.NotFound, drift)
+			continue
+		}
+
+		c.Price = q.UnitMultiplierPrice()
+		c.ListPrice = q.UnitMultiplierListPrice()
+		c.Discount = outputDiscount(q)
+		c.HourlyCost = q.HourlyCost
+		c.MonthlyCost = q.MonthlyCost
+		c.PriceHash = q.PriceHash()
+		c.PriceSource = q.PriceSource()
+
+		drift.NewPrice = c.UnitListPrice()
+		drift.NewPriceHash = c.PriceHash
+
+		if !drift.NewPrice.Equal(drift.OldPrice) {
+			report.Drift = append(report.Drift, drift)
+		}
+	}
+
+	p.root.RecalculateCosts()
+
+	return report
+}
+
+// strPtr returns a pointer to s, or nil if s is empty so it isn't used as a
+// filter.
+func strPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// RecalculateCosts recalculates the costs of the resources, breakdowns,
+// diffs and root of r from the costs of their cost components. It is used
+// after the cost components have been repriced.
+func (r *Root) RecalculateCosts() {
+	var totalHourlyCost, totalMonthlyCost,
+		pastTotalHourlyCost, pastTotalMonthlyCost,
+		diffTotalHourlyCost, diffTotalMonthlyCost *decimal.Decimal
+
+	for i := range r.Projects {
+		p := &r.Projects[i]
+
+		if p.Breakdown != nil {
+			recalculateBreakdown(p.Breakdown)
+			totalHourlyCost = sumDecimals(totalHourlyCost, p.Breakdown.TotalHourlyCost)
+			totalMonthlyCost = sumDecimals(totalMonthlyCost, p.Breakdown.TotalMonthlyCost)
+		}
+
+		if p.PastBreakdown != nil {
+			recalculateBreakdown(p.PastBreakdown)
+			pastTotalHourlyCost = sumDecimals(pastTotalHourlyCost, p.PastBreakdown.TotalHourlyCost)
+			pastTotalMonthlyCost = sumDecimals(pastTotalMonthlyCost, p.PastBreakdown.TotalMonthlyCost)
+		}
+
+		if p.Diff != nil {
+			var pastResources, resources []Resource
+			if p.PastBreakdown != nil {
+				pastResources = p.PastBreakdown.Resources
+			}
+			if p.Breakdown != nil {
+				resources = p.Breakdown.Resources
+			}
+
+			p.Diff = outputBreakdown(schema.CalculateDiff(
+				convertOutputResources(pastResources),
+				convertOutputResources(resources),
+			))
+			diffTotalHourlyCost = sumDecimals(diffTotalHourlyCost, p.Diff.TotalHourlyCost)
+			diffTotalMonthlyCost = sumDecimals(diffTotalMonthlyCost, p.Diff.TotalMonthlyCost)
+		}
+	}
+
+	r.TotalHourlyCost = totalHourlyCost
+	r.TotalMonthlyCost = totalMonthlyCost
+	r.PastTotalHourlyCost = pastTotalHourlyCost
+	r.PastTotalMonthlyCost = pastTotalMonthlyCost
+	r.DiffTotalHourlyCost = diffTotalHourlyCost
+	r.DiffTotalMonthlyCost = diffTotalMonthlyCost
+}
+
+func recalculateBreakdown(b *Breakdown) {
+	for i := range b.Resources {
+		recalculateResource(&b.Resources[i])
+	}
+
+	b.TotalHourlyCost, b.TotalMonthlyCost = calculateTotalCosts(b.Resources)
+}
+
+// recalculateResource sums the costs of the cost components and
+// sub-resources of r, the same as schema.Resource.CalculateCosts.
+func recalculateResource(r *Resource) {
+	var hourlyCost, monthlyCost *decimal.Decimal
+	hasCost := false
+
+	for _, c := range r.CostComponents {
+		if c.HourlyCost != nil || c.MonthlyCost != nil {
+			hasCost = true
+		}
+		hourlyCost = sumDecimals(hourlyCost, c.HourlyCost)
+		monthlyCost = sumDecimals(monthlyCost, c.MonthlyCost)
+	}
+
+	for i := range r.SubResources {
+		s := &r.SubResources[i]
+		recalculateResource(s)
+		if s.HourlyCost != nil || s.MonthlyCost != nil {
+			hasCost = true
+		}
+		hourlyCost = sumDecimals(hourlyCost, s.HourlyCost)
+		monthlyCost = sumDecimals(monthlyCost, s.MonthlyCost)
+	}
+
+	if hasCost {
+		r.HourlyCost = zeroIfNil(hourlyCost)
+		r.MonthlyCost = zeroIfNil(monthlyCost)
+	}
+}
+
+// sumDecimals adds the decimals, treating nil as no value rather than zero so
+// the sum is only nil if both are nil.
+func sumDecimals(a, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	return decimalPtr(a.Add(*b))
+}
+
+func zeroIfNil(d *decimal.Decimal) *decimal.Decimal {
+	if d == nil {
+		return decimalPtr(decimal.Zero)
+	}
+
+	return d
+}
+
+// String returns a summary of the drift report for showing in the terminal.
+func (d DriftReport) String() string {
+	var b strings.Builder
+
+	if len(d.Drift) == 0 {
+		b.WriteString("No price drift found, all repriced cost components have the same prices.\n")
+	} else {
+		fmt.Fprintf(&b, "Price drift found for %d cost %s:\n", len(d.Drift), pluralize(len(d.Drift), "component", "components"))
+		project := ""
+		for i, pd := range d.Drift {
+			if i == 0 || pd.Project != project {
+				project = pd.Project
+				fmt.Fprintf(&b, "\nProject: %s\n", project)
+			}
+
+			fmt.Fprintf(&b, "\n  %s → %s\n    %s → %s%s\n",
+				pd.Resource,
+				pd.CostComponent,
+				formatPrice(d.Currency, pd.OldPrice),
+				formatPrice(d.Currency, pd.NewPrice),
+				formatDriftUnit(pd),
+			)
+		}
+	}
+
+	if len(d.NotFound) > 0 {
+		fmt.Fprintf(&b, "\nNo prices found for %d cost %s, their previous prices were kept:\n", len(d.NotFound), pluralize(len(d.NotFound), "component", "components"))
+		for _, pd := range d.NotFound {
+			fmt.Fprintf(&b, "  %s → %s\n", pd.Resource, pd.CostComponent)
+		}
+	}
+
+	if d.Skipped > 0 {
+		fmt.Fprintf(&b, "\n%d cost %s could not be repriced as no price hashes were recorded for them.\n", d.Skipped, pluralize(d.Skipped, "component", "components"))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatDriftUnit(pd PriceDrift) string {
+	s := ""
+	if pd.Unit != "" {
+		s = " per " + pd.Unit
+	}
+
+	if !pd.OldPrice.IsZero() {
+		change := pd.NewPrice.Sub(pd.OldPrice).Div(pd.OldPrice).Mul(decimal.NewFromInt(100))
+		sign := ""
+		if change.IsPositive() {
+			sign = "+"
+		}
+		s += fmt.Sprintf(" (%s%s%%)", sign, change.StringFixed(2))
+	}
+
+	return s
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+
+	return plural
+}
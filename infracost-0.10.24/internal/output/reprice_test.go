@@ -0,0 +1,145 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepricing(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "test",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							CostComponents: []CostComponent{
+								{
+									Name:        "Instance usage",
+									Unit:        "hours",
+									Price:       decimal.RequireFromString("0.09"),
+									PriceHash:   "abc",
+									MonthlyCost: decimalPtr(decimal.RequireFromString("90")),
+									ListPrice:   decimalPtr(decimal.RequireFromString("0.1")),
+									Discount: &Discount{
+										Name:        "EDP",
+										Price:       decimal.RequireFromString("0.01"),
+										MonthlyCost: decimalPtr(decimal.RequireFromString("10")),
+									},
+									PriceProduct: &PriceProduct{VendorName: "aws", Service: "AmazonEC2", Region: "us-east-1", Sku: "DQ578CGN99KG6ECF"},
+								},
+								{Name: "Support", Price: decimal.NewFromInt(10), MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	p := NewRepricing(&r)
+	require.Equal(t, 1, p.Len())
+	assert.Equal(t, 1, p.Skipped)
+	require.Len(t, p.Resources, 1)
+	assert.Equal(t, "aws_instance", p.Resources[0].ResourceType)
+
+	q := p.Resources[0].CostComponents[0]
+	assert.Equal(t, "abc", q.PriceHash())
+	assert.Equal(t, "DQ578CGN99KG6ECF", *q.ProductFilter.Sku)
+	assert.Nil(t, q.ProductFilter.ProductFamily)
+	assert.Equal(t, "1000", q.MonthlyQuantity.String())
+
+	// Simulate the list price changing and a discount rule that sets a unit
+	// price being applied to it.
+	q.SetPrice(decimal.RequireFromString("0.2"))
+	q.SetPriceHash("abc")
+	q.ApplyDiscount("Negotiated", decimal.RequireFromString("0.15"))
+	p.Resources[0].CalculateCosts()
+
+	report := p.Apply()
+	require.Len(t, report.Drift, 1)
+	assert.Equal(t, "0.1", report.Drift[0].OldPrice.String())
+	assert.Equal(t, "0.2", report.Drift[0].NewPrice.String())
+	assert.Equal(t, 1, report.Skipped)
+
+	c := r.Projects[0].Breakdown.Resources[0].CostComponents[0]
+	assert.Equal(t, "0.2", c.ListPrice.String())
+	assert.Equal(t, "0.15", c.Price.String())
+	assert.Equal(t, "150", c.MonthlyCost.String())
+	assert.Equal(t, "Negotiated", c.Discount.Name)
+	assert.Equal(t, "0.05", c.Discount.Price.String())
+	assert.Equal(t, "50", c.Discount.MonthlyCost.String())
+	assert.Equal(t, "160", r.TotalMonthlyCost.String())
+}
+
+func TestRepricingNotFound(t *testing.T) {
+	r := Root{
+		Projects: []Project{
+			{
+				Name: "test",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name: "aws_instance.web",
+							CostComponents: []CostComponent{
+								{
+									Name:         "Instance usage",
+									Price:        decimal.RequireFromString("0.1"),
+									PriceHash:    "abc",
+									HourlyCost:   decimalPtr(decimal.RequireFromString("0.1")),
+									PriceProduct: &PriceProduct{VendorName: "aws", Service: "AmazonEC2", Sku: "DQ578CGN99KG6ECF"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	p := NewRepricing(&r)
+	p.Resources[0].CostComponents[0].SetPriceNotFound("Price hash not found")
+
+	report := p.Apply()
+	assert.Empty(t, report.Drift)
+	require.Len(t, report.NotFound, 1)
+	assert.Equal(t, "0.1", r.Projects[0].Breakdown.Resources[0].CostComponents[0].Price.String())
+	assert.Equal(t, "No price drift found, all repriced cost components have the same prices.\n\nNo prices found for 1 cost component, their previous prices were kept:\n  aws_instance.web → Instance usage", report.String())
+}
+
+func TestRecalculateCosts(t *testing.T) {
+	r := Root{
+		Projects: []Project{
+			{
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:           "aws_instance.web",
+							CostComponents: []CostComponent{{Name: "Instance usage", MonthlyCost: decimalPtr(decimal.NewFromInt(20))}},
+							SubResources: []Resource{
+								{
+									Name:           "root_block_device",
+									CostComponents: []CostComponent{{Name: "Storage", MonthlyCost: decimalPtr(decimal.NewFromInt(5))}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	r.RecalculateCosts()
+
+	assert.Equal(t, "5", r.Projects[0].Breakdown.Resources[0].SubResources[0].MonthlyCost.String())
+	assert.Equal(t, "25", r.Projects[0].Breakdown.Resources[0].MonthlyCost.String())
+	assert.Equal(t, "25", r.Projects[0].Breakdown.TotalMonthlyCost.String())
+	assert.Equal(t, "25", r.TotalMonthlyCost.String())
+	assert.Nil(t, r.PastTotalMonthlyCost)
+}
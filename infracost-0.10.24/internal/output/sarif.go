@@ -0,0 +1,427 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/version"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	sarifRulePolicy = "infracost-policy"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name,omitempty"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+	HelpURI          string        `json:"helpUri,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifBuilder collects the rules and results of a SARIF run so that each
+// rule is only listed once.
+type sarifBuilder struct {
+	rules     []sarifRule
+	ruleIndex map[string]bool
+	results   []sarifResult
+	locations map[sarifResourceKey]sarifLocation
+	// projects are the names of the projects in order, used to look up the
+	// locations of resources for checks that aren't specific to a project.
+	projects []string
+	// paths are the paths of the projects by name. They're used as the
+	// location of results whose resources have no source file, since code
+	// scanning tools drop results without a physical location.
+	paths map[string]string
+}
+
+// sarifResourceKey identifies a resource by its project and address, since
+// projects often have resources with the same address, e.g. when the same
+// module is used for different environments.
+type sarifResourceKey struct {
+	project string
+	address string
+}
+
+// ToSARIF returns the failing Rego policy checks, tag policy checks and cost
+// optimization policies as SARIF 2.1.0 results, so that code scanning tools
+// can annotate the Terraform code of the resources they apply to.
+func ToSARIF(out Root, opts Options) ([]byte, error) {
+	b := &sarifBuilder{
+		ruleIndex: map[string]bool{},
+		locations: resourceLocations(out),
+		paths:     map[string]string{},
+	}
+
+	for _, p := range out.Projects {
+		b.projects = append(b.projects, p.Name)
+		if p.Metadata != nil && p.Metadata.Path != "" {
+			b.paths[p.Name] = filepath.ToSlash(p.Metadata.Path)
+		}
+	}
+
+	b.addPolicyChecks(opts.PolicyChecks)
+	b.addTagPolicyChecks(opts.TagPolicyCheck)
+	b.addCostOptimizations(out)
+
+	report := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "Infracost",
+						InformationURI: "https://www.infracost.io",
+						Version:        version.Version,
+						Rules:          b.rules,
+					},
+				},
+				Results: b.results,
+			},
+		},
+	}
+
+	if report.Runs[0].Tool.Driver.Rules == nil {
+		report.Runs[0].Tool.Driver.Rules = []sarifRule{}
+	}
+	if report.Runs[0].Results == nil {
+		report.Runs[0].Results = []sarifResult{}
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func (b *sarifBuilder) addRule(rule sarifRule) {
+	if b.ruleIndex[rule.ID] {
+		return
+	}
+
+	b.ruleIndex[rule.ID] = true
+	b.rules = append(b.rules, rule)
+}
+
+// location returns the location of the resource with the address in the
+// project. Resources that weren't parsed from HCL have no source file, so the
+// project path is used as their physical location instead.
+func (b *sarifBuilder) location(project, address string) sarifLocation {
+	if l, ok := b.locations[sarifResourceKey{project, address}]; ok {
+		return l
+	}
+
+	return sarifLocation{
+		PhysicalLocation: b.projectPhysicalLocation(project),
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: address, Kind: "resource"}},
+	}
+}
+
+// projectPhysicalLocation returns the path of the project as a physical
+// location. If the project has no path then the path of the first project
+// that has one is used, or the root of the repository if none of them do.
+func (b *sarifBuilder) projectPhysicalLocation(project string) *sarifPhysicalLocation {
+	path := b.paths[project]
+	if path == "" {
+		for _, p := range b.projects {
+			if b.paths[p] != "" {
+				path = b.paths[p]
+				break
+			}
+		}
+	}
+
+	if path == "" {
+		path = "."
+	}
+
+	return &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: path}}
+}
+
+// projectLocations returns the distinct locations of the resource with the
+// address in each of the projects.
+func (b *sarifBuilder) projectLocations(projects []string, address string) []sarifLocation {
+	if len(projects) == 0 {
+		return []sarifLocation{b.location("", address)}
+	}
+
+	locations := make([]sarifLocation, 0, 1)
+	seen := map[string]bool{}
+
+	for _, project := range projects {
+		l := b.location(project, address)
+
+		key := l.PhysicalLocation.ArtifactLocation.URI
+		if r := l.PhysicalLocation.Region; r != nil {
+			key = fmt.Sprintf("%s:%d", key, r.StartLine)
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			locations = append(locations, l)
+		}
+	}
+
+	return locations
+}
+
+// runLocations returns the distinct paths of the projects as locations, for
+// checks that apply to the whole run rather than to any resource.
+func (b *sarifBuilder) runLocations() []sarifLocation {
+	locations := make([]sarifLocation, 0, 1)
+	seen := map[string]bool{}
+
+	for _, project := range b.projects {
+		l := b.projectPhysicalLocation(project)
+		if !seen[l.ArtifactLocation.URI] {
+			seen[l.ArtifactLocation.URI] = true
+			locations = append(locations, sarifLocation{PhysicalLocation: l})
+		}
+	}
+
+	if len(locations) == 0 {
+		locations = append(locations, sarifLocation{PhysicalLocation: b.projectPhysicalLocation("")})
+	}
+
+	return locations
+}
+
+func (b *sarifBuilder) addPolicyChecks(checks PolicyCheck) {
+	if len(checks.Failures) == 0 {
+		return
+	}
+
+	b.addRule(sarifRule{
+		ID:               sarifRulePolicy,
+		Name:             "InfracostPolicy",
+		ShortDescription: sarifMessage{Text: "Infracost policy check failed"},
+		HelpURI:          "https://www.infracost.io/docs/features/cost_policies/",
+	})
+
+	for _, f := range checks.Failures {
+		result := sarifResult{
+			RuleID:  sarifRulePolicy,
+			Level:   "error",
+			Message: sarifMessage{Text: f},
+		}
+
+		// Policy checks apply to the whole run so the resources could be in
+		// any of the projects.
+		for _, address := range checks.FailureAddresses[f] {
+			result.Locations = append(result.Locations, b.projectLocations(b.projects, address)...)
+		}
+
+		if len(result.Locations) == 0 {
+			result.Locations = b.runLocations()
+		}
+
+		b.results = append(b.results, result)
+	}
+}
+
+func (b *sarifBuilder) addTagPolicyChecks(tpc TagPolicyCheck) {
+	add := func(tp TagPolicy, level string) {
+		id := "tag-policy/" + tp.TagPolicyID
+		if tp.TagPolicyID == "" {
+			id = "tag-policy/" + tp.Name
+		}
+
+		b.addRule(sarifRule{
+			ID:               id,
+			Name:             tp.Name,
+			ShortDescription: sarifMessage{Text: tp.Name},
+			FullDescription:  &sarifMessage{Text: tp.Message},
+		})
+
+		for _, r := range tp.Resources {
+			var locations []sarifLocation
+			if r.Path != "" {
+				locations = []sarifLocation{{
+					PhysicalLocation: &sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(r.Path)},
+						Region:           sarifRegionForLine(r.Line, 0),
+					},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: r.Address, Kind: "resource"}},
+				}}
+			} else {
+				locations = b.projectLocations(r.ProjectNames, r.Address)
+			}
+
+			b.results = append(b.results, sarifResult{
+				RuleID:    id,
+				Level:     level,
+				Message:   sarifMessage{Text: fmt.Sprintf("%s: %s %s", tp.Message, r.Address, strings.Join(r.Failures(), ", "))},
+				Locations: locations,
+			})
+		}
+	}
+
+	for _, tp := range tpc.FailingTagPolicies {
+		add(tp, "error")
+	}
+
+	for _, tp := range tpc.WarningTagPolicies {
+		add(tp, "warning")
+	}
+}
+
+func (b *sarifBuilder) addCostOptimizations(out Root) {
+	for _, p := range out.Projects {
+		if p.Metadata == nil {
+			continue
+		}
+
+		for _, policy := range p.Metadata.Policies {
+			b.addRule(sarifRule{
+				ID:               policy.ID,
+				Name:             policy.Title,
+				ShortDescription: sarifMessage{Text: policy.Title},
+				FullDescription:  &sarifMessage{Text: policy.Description},
+			})
+
+			b.results = append(b.results, sarifResult{
+				RuleID:     policy.ID,
+				Level:      "note",
+				Message:    sarifMessage{Text: costOptimizationMessage(out.Currency, policy)},
+				Locations:  []sarifLocation{b.location(p.Name, policy.Address)},
+				Properties: map[string]string{"project": p.Label()},
+			})
+		}
+	}
+}
+
+func costOptimizationMessage(currency string, policy schema.Policy) string {
+	msg := fmt.Sprintf("%s: %s", policy.Address, policy.Title)
+	if policy.Suggested != "" {
+		msg += fmt.Sprintf(", consider %s", policy.Suggested)
+	}
+
+	if policy.Cost != nil {
+		msg += fmt.Sprintf(" (could save %s/month)", FormatCost2DP(currency, policy.Cost))
+	}
+
+	return msg
+}
+
+// resourceLocations returns the source locations of the resources of the
+// projects by project and address, using the filename and lines recorded in
+// the resource metadata when the resources were parsed from HCL.
+func resourceLocations(out Root) map[sarifResourceKey]sarifLocation {
+	locations := make(map[sarifResourceKey]sarifLocation)
+
+	for _, p := range out.Projects {
+		var resources []Resource
+		if p.PastBreakdown != nil {
+			resources = append(resources, p.PastBreakdown.Resources...)
+		}
+		if p.Breakdown != nil {
+			resources = append(resources, p.Breakdown.Resources...)
+		}
+
+		for _, r := range resources {
+			filename, _ := r.Metadata["filename"].(string)
+			if filename == "" {
+				continue
+			}
+
+			locations[sarifResourceKey{p.Name, r.Name}] = sarifLocation{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(filename)},
+					Region:           sarifRegionForLine(metadataInt(r.Metadata["startLine"]), metadataInt(r.Metadata["endLine"])),
+				},
+				LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: r.Name, Kind: "resource"}},
+			}
+		}
+	}
+
+	return locations
+}
+
+func sarifRegionForLine(startLine, endLine int) *sarifRegion {
+	if startLine <= 0 {
+		return nil
+	}
+
+	if endLine < startLine {
+		endLine = 0
+	}
+
+	return &sarifRegion{StartLine: startLine, EndLine: endLine}
+}
+
+// metadataInt returns the int value of resource metadata, which is a float64
+// when the metadata has been loaded from JSON.
+func metadataInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+
+	return 0
+}
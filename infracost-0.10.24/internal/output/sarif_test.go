@@ -0,0 +1,179 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToSARIF(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "dev",
+				Metadata: &schema.ProjectMetadata{
+					Path: "envs/dev",
+					Policies: []schema.Policy{
+						{
+							ID:        "aws_instance_gp2_volume",
+							Title:     "Use gp3 volumes",
+							Address:   "aws_instance.web",
+							Suggested: "gp3",
+							Cost:      decimalPtr(decimal.NewFromInt(4)),
+						},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name: "aws_instance.web",
+							// Metadata loaded from JSON has float64 line numbers.
+							Metadata: map[string]interface{}{"filename": "modules/web/main.tf", "startLine": float64(10), "endLine": float64(24)},
+						},
+						{Name: "aws_s3_bucket.logs"},
+					},
+				},
+			},
+		},
+	}
+
+	opts := Options{
+		PolicyChecks: PolicyCheck{
+			Failures:         PolicyCheckFailures{"aws_instance.web is too expensive", "Total cost is too high"},
+			FailureAddresses: map[string][]string{"aws_instance.web is too expensive": {"aws_instance.web"}},
+		},
+		TagPolicyCheck: NewTagPolicyChecks([]TagPolicy{
+			{
+				Name:      "Mandatory tags",
+				Message:   "All resources must be tagged",
+				PrComment: true,
+				BlockPr:   true,
+				Resources: []TagPolicyResource{
+					{Address: "aws_instance.web", Path: "modules/web/main.tf", Line: 10, ProjectNames: []string{"dev"}, MissingMandatoryTags: []string{"team"}},
+					{Address: "aws_s3_bucket.logs", ProjectNames: []string{"dev"}, MissingMandatoryTags: []string{"team"}},
+				},
+			},
+		}),
+	}
+
+	b, err := ToSARIF(r, opts)
+	require.NoError(t, err)
+
+	var report sarifLog
+	require.NoError(t, json.Unmarshal(b, &report))
+	require.Len(t, report.Runs, 1)
+
+	run := report.Runs[0]
+	ruleIDs := make([]string, 0, len(run.Tool.Driver.Rules))
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(t, []string{"infracost-policy", "tag-policy/Mandatory tags", "aws_instance_gp2_volume"}, ruleIDs)
+
+	require.Len(t, run.Results, 5)
+
+	webLocation := sarifLocation{
+		PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: "modules/web/main.tf"},
+			Region:           &sarifRegion{StartLine: 10, EndLine: 24},
+		},
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "aws_instance.web", Kind: "resource"}},
+	}
+
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, []sarifLocation{webLocation}, run.Results[0].Locations)
+	assert.Equal(t, []sarifLocation{{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "envs/dev"}}}}, run.Results[1].Locations)
+
+	assert.Equal(t, "tag-policy/Mandatory tags", run.Results[2].RuleID)
+	assert.Equal(t, &sarifRegion{StartLine: 10}, run.Results[2].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "envs/dev", run.Results[3].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, run.Results[3].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "aws_s3_bucket.logs", run.Results[3].Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	assert.Equal(t, "note", run.Results[4].Level)
+	assert.Equal(t, "aws_instance.web: Use gp3 volumes, consider gp3 (could save $4.00/month)", run.Results[4].Message.Text)
+	assert.Equal(t, []sarifLocation{webLocation}, run.Results[4].Locations)
+	assert.Equal(t, map[string]string{"project": "dev"}, run.Results[4].Properties)
+}
+
+func TestToSARIFSameAddressInProjects(t *testing.T) {
+	project := func(name string) Project {
+		return Project{
+			Name: name,
+			Metadata: &schema.ProjectMetadata{
+				Policies: []schema.Policy{{ID: "aws_instance_gp2_volume", Title: "Use gp3 volumes", Address: "aws_instance.web"}},
+			},
+			Breakdown: &Breakdown{
+				Resources: []Resource{
+					{
+						Name:     "aws_instance.web",
+						Metadata: map[string]interface{}{"filename": "envs/" + name + "/main.tf", "startLine": float64(1), "endLine": float64(5)},
+					},
+				},
+			},
+		}
+	}
+
+	r := Root{Currency: "USD", Projects: []Project{project("dev"), project("prod")}}
+
+	opts := Options{
+		PolicyChecks: PolicyCheck{
+			Failures:         PolicyCheckFailures{"aws_instance.web is too expensive"},
+			FailureAddresses: map[string][]string{"aws_instance.web is too expensive": {"aws_instance.web"}},
+		},
+	}
+
+	b, err := ToSARIF(r, opts)
+	require.NoError(t, err)
+
+	var report sarifLog
+	require.NoError(t, json.Unmarshal(b, &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 3)
+
+	uris := func(locations []sarifLocation) []string {
+		s := make([]string, 0, len(locations))
+		for _, l := range locations {
+			s = append(s, l.PhysicalLocation.ArtifactLocation.URI)
+		}
+		return s
+	}
+
+	assert.Equal(t, []string{"envs/dev/main.tf", "envs/prod/main.tf"}, uris(results[0].Locations))
+	assert.Equal(t, []string{"envs/dev/main.tf"}, uris(results[1].Locations))
+	assert.Equal(t, []string{"envs/prod/main.tf"}, uris(results[2].Locations))
+}
+
+func TestToSARIFWithoutProjectPaths(t *testing.T) {
+	r := Root{Currency: "USD", Projects: []Project{{Name: "dev", Breakdown: &Breakdown{Resources: []Resource{{Name: "aws_instance.web"}}}}}}
+
+	opts := Options{
+		PolicyChecks: PolicyCheck{
+			Failures:         PolicyCheckFailures{"aws_instance.web is too expensive", "Total cost is too high"},
+			FailureAddresses: map[string][]string{"aws_instance.web is too expensive": {"aws_instance.web"}},
+		},
+	}
+
+	b, err := ToSARIF(r, opts)
+	require.NoError(t, err)
+
+	var report sarifLog
+	require.NoError(t, json.Unmarshal(b, &report))
+
+	results := report.Runs[0].Results
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		require.Len(t, result.Locations, 1)
+		require.NotNil(t, result.Locations[0].PhysicalLocation)
+		assert.Equal(t, ".", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	assert.Equal(t, "aws_instance.web", results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -142,6 +143,22 @@ func ToSlackMessage(out Root, opts Options) ([]byte, error) {
 		))
 	}
 
+	if discounted := discountedCostComponents(out, opts); len(discounted) > 0 {
+		lines := []string{"*Discounts applied*"}
+		for _, d := range discounted {
+			lines = append(lines, fmt.Sprintf("`%s` → %s: %s", d.Resource, d.CostComponent.Name, formatDiscount(out.Currency, d.CostComponent)))
+		}
+
+		discountMsg := truncateMiddle(strings.Join(lines, "\n"), 3000, "\n\n...(truncated due to Slack message length)...\n\n")
+		blocks = append(blocks, slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: discountMsg,
+			},
+			[]*slack.TextBlockObject{}, nil,
+		))
+	}
+
 	diffMsg := fmt.Sprintf("*Infracost output*\n```%s```", ui.StripColor(string(diff)))
 	diffMsg = truncateMiddle(diffMsg, 3000, "\n\n...(truncated due to Slack message length)...\n\n")
 
@@ -13,6 +13,10 @@ import (
 )
 
 func ToTable(out Root, opts Options) ([]byte, error) {
+	if out.GroupBy != "" {
+		return groupedTable(out, opts), nil
+	}
+
 	var tableLen int
 
 	s := ""
@@ -85,6 +89,11 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += breakdownSummaryTable(out, opts)
 	}
 
+	if len(out.Projection) > 0 {
+		s += "\n\n"
+		s += projectionTable(out)
+	}
+
 	return []byte(s), nil
 }
 
@@ -279,6 +288,23 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 
 			t.AppendRow(tableRow)
 		}
+
+		if c.Discount != nil {
+			childPrefix := prefix + "│  "
+			if strings.HasSuffix(labelPrefix, "└─") {
+				childPrefix = prefix + "   "
+			}
+
+			discountLabel := fmt.Sprintf("%s %s", ui.FaintString(childPrefix+"└─"), ui.FaintString("Discount"))
+			discount := ui.FaintString(formatDiscount(currency, c))
+
+			row := table.Row{discountLabel}
+			for range fields {
+				row = append(row, discount)
+			}
+
+			t.AppendRow(row, table.RowConfig{AutoMerge: true, AlignAutoMerge: text.AlignLeft})
+		}
 	}
 }
 
@@ -369,3 +395,63 @@ func breakdownSummaryTable(out Root, opts Options) string {
 
 	return t.Render()
 }
+
+// groupedTable returns the costs of the resources aggregated by the group by
+// value instead of the breakdown of each project.
+func groupedTable(out Root, opts Options) []byte {
+	withPast := hasPastBreakdown(out)
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleBold)
+	t.Style().Format.Header = text.FormatDefault
+	t.Style().Format.Footer = text.FormatDefault
+
+	headers := table.Row{out.groupByTitle(), "Resources"}
+	footer := table.Row{"Total", ""}
+	if withPast {
+		headers = append(headers, "Previous monthly cost")
+		footer = append(footer, formatCost(out.Currency, out.PastTotalMonthlyCost))
+	}
+	headers = append(headers, "Monthly cost")
+	footer = append(footer, formatCost(out.Currency, out.TotalMonthlyCost))
+	if withPast {
+		headers = append(headers, "Diff")
+		footer = append(footer, formatCostChange(out.Currency, subDecimals(out.TotalMonthlyCost, out.PastTotalMonthlyCost)))
+	}
+
+	t.AppendHeader(headers)
+
+	columns := []table.ColumnConfig{{Number: 1, WidthMin: 30}}
+	for i := 2; i <= len(headers); i++ {
+		columns = append(columns, table.ColumnConfig{Number: i, Align: text.AlignRight, AlignFooter: text.AlignRight})
+	}
+	t.SetColumnConfigs(columns)
+
+	for _, g := range out.Groups {
+		row := table.Row{truncateMiddle(g.Name, 64, "..."), g.ResourceCount}
+		if withPast {
+			row = append(row, formatCost(out.Currency, g.PastMonthlyCost))
+		}
+		row = append(row, formatCost(out.Currency, g.MonthlyCost))
+		if withPast {
+			row = append(row, formatCostChange(out.Currency, g.DiffMonthlyCost))
+		}
+
+		t.AppendRow(row)
+	}
+
+	t.AppendFooter(footer)
+
+	s := t.Render()
+
+	if len(out.Projection) > 0 {
+		s += "\n\n" + projectionTable(out)
+	}
+
+	summaryMsg := out.summaryMessage(opts.ShowSkipped)
+	if summaryMsg != "" {
+		s += "\n──────────────────────────────────\n" + summaryMsg
+	}
+
+	return []byte(s)
+}
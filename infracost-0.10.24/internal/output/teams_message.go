@@ -0,0 +1,228 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	teamsCardContentType = "application/vnd.microsoft.card.adaptive"
+	teamsCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	teamsCardVersion     = "1.4"
+)
+
+// teamsMessage is the payload of a Microsoft Teams incoming webhook that
+// contains an Adaptive Card.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string            `json:"$schema"`
+	Type    string            `json:"type"`
+	Version string            `json:"version"`
+	Body    []teamsElement    `json:"body"`
+	Actions []teamsAction     `json:"actions,omitempty"`
+	MSTeams map[string]string `json:"msteams,omitempty"`
+}
+
+// teamsElement is an Adaptive Card element. Only the fields that are used by
+// the element's type are set.
+type teamsElement struct {
+	Type                string         `json:"type"`
+	Text                string         `json:"text,omitempty"`
+	Size                string         `json:"size,omitempty"`
+	Weight              string         `json:"weight,omitempty"`
+	Color               string         `json:"color,omitempty"`
+	IsSubtle            bool           `json:"isSubtle,omitempty"`
+	Wrap                bool           `json:"wrap,omitempty"`
+	Separator           bool           `json:"separator,omitempty"`
+	Spacing             string         `json:"spacing,omitempty"`
+	HorizontalAlignment string         `json:"horizontalAlignment,omitempty"`
+	Width               string         `json:"width,omitempty"`
+	Columns             []teamsElement `json:"columns,omitempty"`
+	Items               []teamsElement `json:"items,omitempty"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func teamsTextBlock(text string) teamsElement {
+	return teamsElement{Type: "TextBlock", Text: text, Wrap: true}
+}
+
+// teamsRow returns a row of the project table, the first column is stretched
+// to fill the width of the card and the others are aligned right.
+func teamsRow(cells []teamsElement) teamsElement {
+	columns := make([]teamsElement, 0, len(cells))
+	for i, cell := range cells {
+		column := teamsElement{Type: "Column", Width: "auto", Items: []teamsElement{cell}}
+		if i == 0 {
+			column.Width = "stretch"
+		} else {
+			column.Items[0].HorizontalAlignment = "Right"
+		}
+
+		columns = append(columns, column)
+	}
+
+	return teamsElement{Type: "ColumnSet", Columns: columns, Spacing: "Small"}
+}
+
+func teamsSummaryRow(name string, currency string, cost, pastCost, diffCost *decimal.Decimal) teamsElement {
+	if cost == nil {
+		cost = decimalPtr(decimal.Zero)
+	}
+
+	if diffCost == nil {
+		// If we don't have a past cost or a diff cost then it means the cost increase is the total cost
+		if pastCost == nil {
+			diffCost = cost
+		} else {
+			diffCost = decimalPtr(decimal.Zero)
+		}
+	}
+
+	previous := "-"
+	if pastCost != nil {
+		previous = formatCost(currency, pastCost)
+	}
+
+	return teamsRow([]teamsElement{
+		teamsTextBlock(name),
+		teamsTextBlock(previous),
+		teamsTextBlock(formatCost(currency, cost)),
+		teamsTextBlock(formatCostChange(currency, diffCost)),
+	})
+}
+
+// teamsPolicyFailures returns the cost policy, tag policy and guardrail
+// failures that should be shown in the card.
+func teamsPolicyFailures(opts Options) []string {
+	failures := append([]string{}, opts.PolicyChecks.Failures...)
+
+	for _, tp := range opts.TagPolicyCheck.FailingTagPolicies {
+		resources := "1 resource"
+		if len(tp.Resources) != 1 {
+			resources = fmt.Sprintf("%d resources", len(tp.Resources))
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s (%s)", tp.Name, tp.Message, resources))
+	}
+
+	for _, e := range opts.GuardrailCheck.GuardrailEvents {
+		if e.BlockPR {
+			failures = append(failures, e.TriggerReason)
+		}
+	}
+
+	return failures
+}
+
+// ToTeamsMessage returns a Microsoft Teams incoming webhook message with an
+// Adaptive Card of the cost estimate. The card has the overall cost change,
+// a table of the cost of each project and any policy failures.
+func ToTeamsMessage(out Root, opts Options) ([]byte, error) {
+	body := []teamsElement{
+		{
+			Type:   "TextBlock",
+			Text:   fmt.Sprintf("💰 Infracost estimate: **%s**", formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, out.TotalMonthlyCost, true)),
+			Size:   "Medium",
+			Weight: "Bolder",
+			Wrap:   true,
+		},
+	}
+
+	header := teamsRow([]teamsElement{
+		teamsTextBlock("Project"),
+		teamsTextBlock("Previous"),
+		teamsTextBlock("New"),
+		teamsTextBlock("Diff"),
+	})
+	for i := range header.Columns {
+		header.Columns[i].Items[0].Weight = "Bolder"
+	}
+	header.Separator = true
+	header.Spacing = "Medium"
+	body = append(body, header)
+
+	skippedProjectCount := 0
+	for _, project := range out.Projects {
+		if project.Diff == nil || len(project.Diff.Resources) == 0 {
+			skippedProjectCount++
+
+			if len(out.Projects) != 1 && !opts.ShowAllProjects {
+				continue
+			}
+		}
+
+		pastCost, cost, diffCost := projectMonthlyCosts(project)
+		body = append(body, teamsSummaryRow(truncateMiddle(project.Label(), 64, "..."), out.Currency, cost, pastCost, diffCost))
+	}
+
+	if len(out.Projects) > 1 {
+		total := teamsSummaryRow("All projects", out.Currency, out.TotalMonthlyCost, out.PastTotalMonthlyCost, out.DiffTotalMonthlyCost)
+		for i := range total.Columns {
+			total.Columns[i].Items[0].Weight = "Bolder"
+		}
+		total.Separator = true
+		body = append(body, total)
+
+		if !opts.ShowAllProjects {
+			if skippedProjectCount == 1 {
+				body = append(body, teamsElement{Type: "TextBlock", Text: "1 project has no cost estimate changes.", IsSubtle: true, Wrap: true})
+			} else if skippedProjectCount > 0 {
+				body = append(body, teamsElement{Type: "TextBlock", Text: fmt.Sprintf("%d projects have no cost estimate changes.", skippedProjectCount), IsSubtle: true, Wrap: true})
+			}
+		}
+	}
+
+	if failures := teamsPolicyFailures(opts); len(failures) > 0 {
+		title := "1 policy failed"
+		if len(failures) > 1 {
+			title = fmt.Sprintf("%d policies failed", len(failures))
+		}
+
+		items := []teamsElement{{Type: "TextBlock", Text: title, Weight: "Bolder", Color: "Attention", Wrap: true}}
+		for _, f := range failures {
+			items = append(items, teamsTextBlock("- "+f))
+		}
+
+		body = append(body, teamsElement{Type: "Container", Items: items, Separator: true, Spacing: "Medium"})
+	} else if opts.PolicyChecks.Enabled {
+		body = append(body, teamsElement{Type: "TextBlock", Text: "All policies passed", Color: "Good", Separator: true, Spacing: "Medium", Wrap: true})
+	}
+
+	card := teamsCard{
+		Schema:  teamsCardSchema,
+		Type:    "AdaptiveCard",
+		Version: teamsCardVersion,
+		Body:    body,
+		MSTeams: map[string]string{"width": "Full"},
+	}
+
+	if url := out.CloudURL; url != "" {
+		card.Actions = append(card.Actions, teamsAction{Type: "Action.OpenUrl", Title: "View in Infracost Cloud", URL: url})
+	} else if url := out.ShareURL; url != "" {
+		card.Actions = append(card.Actions, teamsAction{Type: "Action.OpenUrl", Title: "View report", URL: url})
+	}
+
+	msg := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{ContentType: teamsCardContentType, Content: card},
+		},
+	}
+
+	return json.Marshal(msg)
+}
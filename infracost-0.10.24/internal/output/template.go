@@ -0,0 +1,132 @@
+package output
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// TemplateData is the data that user supplied templates are executed with.
+type TemplateData struct {
+	// Root is the combined Infracost output, e.g. {{ .Root.TotalMonthlyCost }}.
+	Root Root
+	// Options are the output options, including the policy check results,
+	// e.g. {{ .Options.PolicyChecks.HasFailed }}.
+	Options Options
+	// SummaryMessage is the summary of the supported, unsupported and free
+	// resources that is shown below the table output.
+	SummaryMessage string
+}
+
+// TemplateFuncMap returns the functions that are available to user supplied
+// templates, in addition to the sprig functions. These functions are part of
+// the template format so they should not be removed or changed:
+//
+//	formatCost <cost>                      e.g. $1,234 ($0.12 for costs under $1)
+//	formatCost2DP <cost>                   e.g. $1,234.56
+//	formatPrice <price>                    the unit price of a cost component
+//	formatQuantity <quantity>              e.g. 1,234
+//	formatCostChange <diff>                e.g. +$12 or -$12
+//	formatCostChangeBetween <past> <cost>  e.g. +$12 (+10%)
+//	formatPercentChange <past> <cost>      e.g. +10%
+//	projectLabel <project>                 the project name
+//	projectLabelWithMetadata <project>     the project name with its module path and workspace
+//	stripColor <string>                    removes terminal colors from messages
+//	contains <list> <string>               if the list contains the string
+//
+// Costs are formatted using the currency of the output.
+func TemplateFuncMap(out Root) map[string]interface{} {
+	return map[string]interface{}{
+		"formatCost": func(d *decimal.Decimal) string {
+			return formatCost(out.Currency, d)
+		},
+		"formatCost2DP": func(d *decimal.Decimal) string {
+			return FormatCost2DP(out.Currency, d)
+		},
+		"formatPrice": func(d decimal.Decimal) string {
+			return formatPrice(out.Currency, d)
+		},
+		"formatQuantity": formatQuantity,
+		"formatCostChange": func(d *decimal.Decimal) string {
+			return formatCostChange(out.Currency, d)
+		},
+		"formatCostChangeBetween": func(pastCost, cost *decimal.Decimal) string {
+			if cost == nil {
+				cost = decimalPtr(decimal.Zero)
+			}
+			return formatMarkdownCostChange(out.Currency, pastCost, cost, false)
+		},
+		"formatPercentChange": formatPercentChange,
+		"projectLabel": func(p Project) string {
+			return p.Label()
+		},
+		"projectLabelWithMetadata": func(p Project) string {
+			if p.Metadata == nil {
+				return p.Label()
+			}
+			return p.LabelWithMetadata()
+		},
+		"stripColor": ui.StripColor,
+		"contains":   contains,
+	}
+}
+
+// ToTemplate renders the output using the template file at opts.TemplatePath.
+// Templates with a .html, .htm or .gohtml extension are parsed with
+// html/template so that values are escaped, other templates are parsed with
+// text/template.
+func ToTemplate(out Root, opts Options) ([]byte, error) {
+	if opts.TemplatePath == "" {
+		return nil, errors.New("a template path is required for the template output format")
+	}
+
+	content, err := os.ReadFile(opts.TemplatePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading template %s", opts.TemplatePath)
+	}
+
+	data := TemplateData{
+		Root:           out,
+		Options:        opts,
+		SummaryMessage: out.summaryMessage(opts.ShowSkipped),
+	}
+
+	name := filepath.Base(opts.TemplatePath)
+	funcs := TemplateFuncMap(out)
+
+	var buf bytes.Buffer
+
+	switch strings.ToLower(filepath.Ext(opts.TemplatePath)) {
+	case ".html", ".htm", ".gohtml":
+		tmpl, err := htmltemplate.New(name).Funcs(sprig.FuncMap()).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing template %s", opts.TemplatePath)
+		}
+
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error executing template %s", opts.TemplatePath)
+		}
+	default:
+		tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing template %s", opts.TemplatePath)
+		}
+
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error executing template %s", opts.TemplatePath)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
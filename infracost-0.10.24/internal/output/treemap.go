@@ -0,0 +1,147 @@
+package output
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var moduleCallRegex = regexp.MustCompile(`module\.[^.\[]+(?:\[[^\]]*\])?`)
+
+// treemap is the data for the cost treemap in the HTML report.
+type treemap struct {
+	Currency string       `json:"currency"`
+	HasPast  bool         `json:"hasPast"`
+	Root     *treemapNode `json:"root"`
+}
+
+// treemapNode is a node of the treemap. The hierarchy is project → module
+// path → resource → cost component, subresources are nested in their resource.
+type treemapNode struct {
+	Name     string         `json:"name"`
+	Past     float64        `json:"past"`
+	Current  float64        `json:"current"`
+	Children []*treemapNode `json:"children,omitempty"`
+
+	index map[string]*treemapNode
+}
+
+func (n *treemapNode) child(name string) *treemapNode {
+	if c, ok := n.index[name]; ok {
+		return c
+	}
+
+	if n.index == nil {
+		n.index = make(map[string]*treemapNode)
+	}
+
+	c := &treemapNode{Name: name}
+	n.index[name] = c
+	n.Children = append(n.Children, c)
+
+	return c
+}
+
+func (n *treemapNode) add(d *decimal.Decimal, past bool) {
+	if d == nil {
+		return
+	}
+
+	f, _ := d.Float64()
+	if past {
+		n.Past += f
+	} else {
+		n.Current += f
+	}
+}
+
+// sum sets the costs of the nodes with children to the total of their children.
+func (n *treemapNode) sum() {
+	if len(n.Children) == 0 {
+		return
+	}
+
+	n.Past, n.Current = 0, 0
+	for _, c := range n.Children {
+		c.sum()
+		n.Past += c.Past
+		n.Current += c.Current
+	}
+}
+
+func buildTreemap(out Root) treemap {
+	t := treemap{
+		Currency: out.Currency,
+		Root:     &treemapNode{Name: "All projects"},
+	}
+
+	if t.Currency == "" {
+		t.Currency = "USD"
+	}
+
+	for _, p := range out.Projects {
+		base := t.Root.child(p.Label())
+		if p.Metadata != nil && p.Metadata.TerraformModulePath != "" {
+			base = base.child(p.Metadata.TerraformModulePath)
+		}
+
+		if p.PastBreakdown != nil {
+			t.HasPast = true
+			for _, r := range p.PastBreakdown.Resources {
+				addTreemapResource(base, r, true)
+			}
+		}
+
+		if p.Breakdown != nil {
+			for _, r := range p.Breakdown.Resources {
+				addTreemapResource(base, r, false)
+			}
+		}
+	}
+
+	t.Root.sum()
+
+	return t
+}
+
+// addTreemapResource adds the resource to the treemap nested in a node for
+// each module call in its address.
+func addTreemapResource(parent *treemapNode, r Resource, past bool) {
+	modulePrefix := moduleAddressRegex.FindString(r.Name)
+
+	n := parent
+	for _, call := range moduleCallRegex.FindAllString(modulePrefix, -1) {
+		n = n.child(call)
+	}
+
+	addTreemapCosts(n.child(strings.TrimPrefix(r.Name, modulePrefix)), r, past)
+}
+
+func addTreemapCosts(n *treemapNode, r Resource, past bool) {
+	for _, c := range r.CostComponents {
+		n.child(c.Name).add(c.MonthlyCost, past)
+	}
+
+	for _, s := range r.SubResources {
+		addTreemapCosts(n.child(s.Name), s, past)
+	}
+}
+
+// resourceSearchText returns the text that the resource table filter of the
+// HTML report matches against.
+func resourceSearchText(r Resource) string {
+	keys := make([]string, 0, len(r.Tags))
+	for k := range r.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{r.Name, resourceType(r)}
+	for _, k := range keys {
+		parts = append(parts, k+"="+r.Tags[k])
+	}
+
+	return strings.ToLower(strings.Join(parts, " "))
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestBuildTreemap(t *testing.T) {
+	component := func(name string, cost int64) CostComponent {
+		return CostComponent{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	r := Root{
+		Currency: "EUR",
+		Projects: []Project{
+			{
+				Name:     "infra",
+				Metadata: &schema.ProjectMetadata{TerraformModulePath: "envs/prod"},
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "module.app.aws_instance.web", CostComponents: []CostComponent{component("Instance usage", 60)}},
+						{Name: "aws_s3_bucket.old", CostComponents: []CostComponent{component("Storage", 5)}},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "module.app.aws_instance.web", CostComponents: []CostComponent{component("Instance usage", 80)}},
+						{
+							Name: "module.app.module.db[0].aws_db_instance.main",
+							CostComponents: []CostComponent{
+								component("Database instance", 100),
+							},
+							SubResources: []Resource{
+								{Name: "storage", CostComponents: []CostComponent{component("Storage", 20)}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tm := buildTreemap(r)
+	assert.Equal(t, "EUR", tm.Currency)
+	assert.True(t, tm.HasPast)
+	assert.Equal(t, 200.0, tm.Root.Current)
+	assert.Equal(t, 65.0, tm.Root.Past)
+
+	require.Len(t, tm.Root.Children, 1)
+	project := tm.Root.Children[0]
+	assert.Equal(t, "infra", project.Name)
+
+	require.Len(t, project.Children, 1)
+	modulePath := project.Children[0]
+	assert.Equal(t, "envs/prod", modulePath.Name)
+
+	names := func(n *treemapNode) []string {
+		s := make([]string, 0, len(n.Children))
+		for _, c := range n.Children {
+			s = append(s, c.Name)
+		}
+		return s
+	}
+
+	assert.Equal(t, []string{"module.app", "aws_s3_bucket.old"}, names(modulePath))
+
+	app := modulePath.Children[0]
+	assert.Equal(t, []string{"aws_instance.web", "module.db[0]"}, names(app))
+	assert.Equal(t, 200.0, app.Current)
+	assert.Equal(t, 60.0, app.Past)
+
+	db := app.Children[1].Children[0]
+	assert.Equal(t, "aws_db_instance.main", db.Name)
+	assert.Equal(t, []string{"Database instance", "storage"}, names(db))
+	assert.Equal(t, 120.0, db.Current)
+	assert.Equal(t, 20.0, db.Children[1].Children[0].Current)
+
+	deleted := modulePath.Children[1]
+	assert.Equal(t, 5.0, deleted.Past)
+	assert.Equal(t, 0.0, deleted.Current)
+}
+
+func TestResourceSearchText(t *testing.T) {
+	r := Resource{
+		Name: "module.app.aws_instance.Web",
+		Tags: map[string]string{"Team": "web", "env": "prod"},
+	}
+
+	assert.Equal(t, "module.app.aws_instance.web aws_instance team=web env=prod", resourceSearchText(r))
+}
@@ -0,0 +1,304 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	xlsxMaxSheetNameLength = 31
+	costComponentsSheet    = "Cost components"
+)
+
+// xlsxSheet is a worksheet of an xlsx workbook. The header row is shown in
+// bold, it is numbered from 1 like the rows of the sheet.
+type xlsxSheet struct {
+	name      string
+	headerRow int
+	rows      [][]cell
+}
+
+// ToXLSX returns an xlsx workbook with a sheet of the cost components of all
+// the projects, with one row per cost component, followed by a summary sheet
+// for each project.
+func ToXLSX(out Root, opts Options) ([]byte, error) {
+	withPast := hasPastBreakdown(out)
+
+	costComponents := xlsxSheet{name: costComponentsSheet, headerRow: 1}
+	costComponents.rows = append(costComponents.rows, textCells(costComponentHeaders(out.Currency, withPast)))
+	for _, row := range costComponentRows(out) {
+		costComponents.rows = append(costComponents.rows, row.cells(withPast))
+	}
+
+	sheets := []xlsxSheet{costComponents}
+	names := map[string]bool{strings.ToLower(costComponentsSheet): true}
+
+	for _, p := range out.Projects {
+		name := xlsxSheetName(p.Label(), names)
+		sheets = append(sheets, projectSummarySheet(name, out.Currency, p, withPast))
+	}
+
+	return writeXLSX(sheets)
+}
+
+// projectSummarySheet returns a sheet with the costs of each resource of the
+// project and the project total.
+func projectSummarySheet(name string, currency string, p Project, withPast bool) xlsxSheet {
+	sheet := xlsxSheet{name: name}
+
+	label := p.Label()
+	if p.Metadata != nil {
+		label = p.LabelWithMetadata()
+	}
+
+	sheet.rows = append(sheet.rows,
+		[]cell{textCell("Project"), textCell(label)},
+		[]cell{textCell("Currency"), textCell(currency)},
+		[]cell{},
+	)
+
+	headers := []string{
+		"Resource",
+		"Resource type",
+		fmt.Sprintf("Hourly cost (%s)", currency),
+		fmt.Sprintf("Monthly cost (%s)", currency),
+	}
+	if withPast {
+		headers = append(headers,
+			fmt.Sprintf("Past monthly cost (%s)", currency),
+			fmt.Sprintf("Diff monthly cost (%s)", currency),
+		)
+	}
+	sheet.rows = append(sheet.rows, textCells(headers))
+	sheet.headerRow = len(sheet.rows)
+
+	var resources, pastResources []Resource
+	var totalHourlyCost, totalMonthlyCost, pastTotalMonthlyCost *decimal.Decimal
+
+	if p.Breakdown != nil {
+		resources = p.Breakdown.Resources
+		totalHourlyCost, totalMonthlyCost = p.Breakdown.TotalHourlyCost, p.Breakdown.TotalMonthlyCost
+	}
+	if p.PastBreakdown != nil {
+		pastResources = p.PastBreakdown.Resources
+		pastTotalMonthlyCost = p.PastBreakdown.TotalMonthlyCost
+	}
+
+	pastByName := make(map[string]*Resource, len(pastResources))
+	for i := range pastResources {
+		pastByName[pastResources[i].Name] = &pastResources[i]
+	}
+
+	seen := make(map[string]bool, len(resources))
+	for i := range resources {
+		r := &resources[i]
+		seen[r.Name] = true
+		sheet.rows = append(sheet.rows, resourceSummaryCells(r, pastByName[r.Name], withPast))
+	}
+
+	for i := range pastResources {
+		r := &pastResources[i]
+		if !seen[r.Name] {
+			sheet.rows = append(sheet.rows, resourceSummaryCells(nil, r, withPast))
+		}
+	}
+
+	total := []cell{textCell("Total"), {}, numberCell(totalHourlyCost), numberCell(totalMonthlyCost)}
+	if withPast {
+		total = append(total, numberCell(pastTotalMonthlyCost), numberCell(diffDecimals(totalMonthlyCost, pastTotalMonthlyCost)))
+	}
+	sheet.rows = append(sheet.rows, total)
+
+	return sheet
+}
+
+func resourceSummaryCells(r *Resource, past *Resource, withPast bool) []cell {
+	named := r
+	if named == nil {
+		named = past
+	}
+
+	var hourlyCost, monthlyCost, pastMonthlyCost *decimal.Decimal
+	if r != nil {
+		hourlyCost, monthlyCost = r.HourlyCost, r.MonthlyCost
+	}
+	if past != nil {
+		pastMonthlyCost = past.MonthlyCost
+	}
+
+	cells := []cell{
+		textCell(named.Name),
+		textCell(named.ResourceType),
+		numberCell(hourlyCost),
+		numberCell(monthlyCost),
+	}
+	if withPast {
+		cells = append(cells, numberCell(pastMonthlyCost), numberCell(diffDecimals(monthlyCost, pastMonthlyCost)))
+	}
+
+	return cells
+}
+
+func textCells(values []string) []cell {
+	cells := make([]cell, 0, len(values))
+	for _, v := range values {
+		cells = append(cells, textCell(v))
+	}
+
+	return cells
+}
+
+// xlsxSheetName returns a unique sheet name for the label. Sheet names can't
+// contain some characters and are limited to 31 characters, so long labels
+// are truncated in the middle to keep the end of project paths.
+func xlsxSheetName(label string, names map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, label)
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "Project"
+	}
+
+	unique := truncateMiddle(name, xlsxMaxSheetNameLength, "...")
+	for i := 2; names[strings.ToLower(unique)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		unique = truncateMiddle(name, xlsxMaxSheetNameLength-len(suffix), "...") + suffix
+	}
+	names[strings.ToLower(unique)] = true
+
+	return unique
+}
+
+// writeXLSX writes the sheets as a minimal SpreadsheetML workbook. Strings
+// are written inline so the workbook doesn't need a shared strings table.
+func writeXLSX(sheets []xlsxSheet) ([]byte, error) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+
+	var contentTypes, workbook, workbookRels strings.Builder
+
+	contentTypes.WriteString(xml.Header)
+	contentTypes.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	contentTypes.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	contentTypes.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	contentTypes.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	contentTypes.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+
+	workbook.WriteString(xml.Header)
+	workbook.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+
+	workbookRels.WriteString(xml.Header)
+	workbookRels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i, sheet := range sheets {
+		id := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, id)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheet.name), id, id)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, id, id)
+	}
+
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(sheets)+1)
+	workbookRels.WriteString(`</Relationships>`)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font/><font><b/></font></fonts><fills count="1"><fill><patternFill patternType="none"/></fill></fills><borders count="1"><border/></borders><cellStyleXfs count="1"><xf/></cellStyleXfs><cellXfs count="2"><xf/><xf fontId="1" applyFont="1"/></cellXfs></styleSheet>`},
+	}
+
+	for _, f := range files {
+		if err := writeZipFile(z, f.name, strings.NewReader(f.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, sheet := range sheets {
+		if err := writeZipFile(z, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), strings.NewReader(sheetXML(sheet))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeZipFile(z *zip.Writer, name string, r io.Reader) error {
+	w, err := z.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func sheetXML(sheet xlsxSheet) string {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for i, row := range sheet.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+
+		style := ""
+		if i+1 == sheet.headerRow {
+			style = ` s="1"`
+		}
+
+		for j, c := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumnName(j), i+1)
+
+			switch {
+			case c.number != nil:
+				fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, c.number.String())
+			case c.text != "":
+				fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escapeXML(c.text))
+			}
+		}
+
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+// xlsxColumnName returns the column name for the zero-based index, e.g. A, Z,
+// AA.
+func xlsxColumnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+
+	return name
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
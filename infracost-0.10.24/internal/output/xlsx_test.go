@@ -0,0 +1,105 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readXLSXFiles(t *testing.T, b []byte) map[string]string {
+	z, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(z.File))
+	for _, f := range z.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestToXLSX(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "infracost/example/dev",
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.old",
+							MonthlyCost: decimalPtr(decimal.NewFromInt(5)),
+							CostComponents: []CostComponent{
+								{Name: "Instance usage", Unit: "hours", MonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+							},
+						},
+					},
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(5)),
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name:         "aws_instance.web",
+							ResourceType: "aws_instance",
+							Tags:         map[string]string{"team": "infra", "env": "prod"},
+							MonthlyCost:  decimalPtr(decimal.NewFromInt(10)),
+							CostComponents: []CostComponent{
+								{
+									Name:            "Instance usage",
+									Unit:            "hours",
+									MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+									Price:           decimal.RequireFromString("0.0137"),
+									MonthlyCost:     decimalPtr(decimal.NewFromInt(10)),
+								},
+							},
+						},
+					},
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10)),
+				},
+			},
+		},
+	}
+
+	b, err := ToXLSX(r, Options{})
+	require.NoError(t, err)
+
+	files := readXLSXFiles(t, b)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Cost components" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="infracost_example_dev" sheetId="2" r:id="rId2"/>`)
+
+	costComponents := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, costComponents, `<c r="P1" s="1" t="inlineStr"><is><t xml:space="preserve">Diff monthly cost (USD)</t></is></c>`)
+	assert.Contains(t, costComponents, `<c r="D2" t="inlineStr"><is><t xml:space="preserve">env=prod; team=infra</t></is></c>`)
+	assert.Contains(t, costComponents, `<c r="H2"><v>0.0137</v></c>`)
+	assert.Contains(t, costComponents, `<c r="B3" t="inlineStr"><is><t xml:space="preserve">aws_instance.old</t></is></c>`)
+	assert.Contains(t, costComponents, `<c r="P3"><v>-5</v></c>`)
+
+	summary := files["xl/worksheets/sheet2.xml"]
+	assert.Contains(t, summary, `<c r="A4" s="1" t="inlineStr"><is><t xml:space="preserve">Resource</t></is></c>`)
+	assert.Contains(t, summary, `<c r="A7" t="inlineStr"><is><t xml:space="preserve">Total</t></is></c><c r="D7"><v>10</v></c><c r="E7"><v>5</v></c><c r="F7"><v>5</v></c>`)
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	names := map[string]bool{"cost components": true}
+
+	assert.Equal(t, "Cost components (2)", xlsxSheetName("Cost components", names))
+	assert.Equal(t, "infracost_infr...es_dev_main.tf", xlsxSheetName("infracost/infracost/examples/dev/main.tf", names))
+	assert.Equal(t, "infracost_in..._dev_main.tf (2)", xlsxSheetName("infracost/infracost/examples/dev/main.tf", names))
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "AZ", xlsxColumnName(51))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
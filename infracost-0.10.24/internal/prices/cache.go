@@ -0,0 +1,228 @@
+package prices
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const priceCacheFileExt = ".json"
+
+// CachedPricingSource is a PricingSource that caches the results of another
+// source on disk. Entries are content-addressed by a hash of the query, so
+// the same query made by any project or run is only sent to the underlying
+// source once per TTL.
+type CachedPricingSource struct {
+	source   PricingSource
+	dir      string
+	ttl      time.Duration
+	currency string
+	fields   apiclient.PriceQueryFields
+	// endpoint is the endpoint of the source if it has one. Sources with the
+	// same name can have different endpoints, so it's part of the cache key.
+	endpoint string
+}
+
+// endpointSource is a PricingSource that queries an endpoint, e.g. the
+// Pricing API.
+type endpointSource interface {
+	Endpoint() string
+}
+
+// NewCachedPricingSource returns a CachedPricingSource wrapping source, using
+// the price cache directory and TTL from the config.
+func NewCachedPricingSource(ctx *config.RunContext, source PricingSource) *CachedPricingSource {
+	var endpoint string
+	if s, ok := source.(endpointSource); ok {
+		endpoint = s.Endpoint()
+	}
+
+	return &CachedPricingSource{
+		source:   source,
+		dir:      ctx.Config.PriceCachePath(),
+		ttl:      ctx.Config.PriceCacheMaxAge(),
+		currency: ctx.Config.PricingCurrency(),
+		fields:   apiclient.NewPriceQueryFields(ctx.Config),
+		endpoint: endpoint,
+	}
+}
+
+// Name returns the name of the underlying source.
+func (c *CachedPricingSource) Name() string {
+	return c.source.Name()
+}
+
+// QueryPrices returns the cached results for any keys that have been queried
+// within the TTL, and queries the underlying source for the rest.
+func (c *CachedPricingSource) QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	results := make([]apiclient.PriceQueryResult, len(keys))
+
+	var (
+		missIdx    []int
+		missKeys   []apiclient.PriceQueryKey
+		missHashes []string
+	)
+
+	for i, k := range keys {
+		hash := c.hash(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter)
+
+		if res, ok := c.get(hash); ok {
+			results[i] = apiclient.PriceQueryResult{
+				PriceQueryKey: k,
+				Result:        res,
+				Source:        c.source.Name(),
+			}
+			continue
+		}
+
+		missIdx = append(missIdx, i)
+		missKeys = append(missKeys, k)
+		missHashes = append(missHashes, hash)
+	}
+
+	log.Debugf("Found %d of %d price queries in the price cache", len(keys)-len(missKeys), len(keys))
+
+	if len(missKeys) == 0 {
+		return results, nil
+	}
+
+	fetched, err := c.source.QueryPrices(ctx, missKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, res := range fetched {
+		results[missIdx[j]] = res
+		c.set(missHashes[j], res.Result)
+	}
+
+	return results, nil
+}
+
+// hash returns the cache key for the filters. It includes the source name,
+// endpoint, currency and optional fields since these all change the result of
+// the query.
+func (c *CachedPricingSource) hash(productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) string {
+	b, _ := json.Marshal(struct {
+		Source        string                     `json:"source"`
+		Endpoint      string                     `json:"endpoint"`
+		Currency      string                     `json:"currency"`
+		Fields        apiclient.PriceQueryFields `json:"fields"`
+		ProductFilter *schema.ProductFilter      `json:"productFilter"`
+		PriceFilter   *schema.PriceFilter        `json:"priceFilter"`
+	}{c.source.Name(), c.endpoint, c.currency, c.fields, productFilter, priceFilter})
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns the file for the hash. Entries are split into subdirectories
+// by the first two characters of the hash to keep the directories small.
+func (c *CachedPricingSource) path(hash string) string {
+	return filepath.Join(c.dir, hash[:2], hash+priceCacheFileExt)
+}
+
+func (c *CachedPricingSource) get(hash string) (gjson.Result, bool) {
+	p := c.path(hash)
+
+	info, err := os.Stat(p)
+	if err != nil || isPriceCacheEntryExpired(info, c.ttl) {
+		return gjson.Result{}, false
+	}
+
+	b, err := os.ReadFile(p)
+	if err != nil || !gjson.ValidBytes(b) {
+		log.Debugf("Ignoring invalid price cache entry %s", p)
+		return gjson.Result{}, false
+	}
+
+	return gjson.ParseBytes(b), true
+}
+
+// set writes the entry to a temporary file first and then renames it, so
+// concurrent runs never read a partially written entry.
+func (c *CachedPricingSource) set(hash string, res gjson.Result) {
+	p := c.path(hash)
+
+	err := os.MkdirAll(filepath.Dir(p), 0700)
+	if err != nil {
+		log.Debugf("Couldn't create price cache directory: %v", err)
+		return
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), hash+".tmp*")
+	if err != nil {
+		log.Debugf("Couldn't create price cache entry: %v", err)
+		return
+	}
+
+	_, err = f.WriteString(res.Raw)
+	_ = f.Close()
+	if err == nil {
+		err = os.Rename(f.Name(), p)
+	}
+
+	if err != nil {
+		log.Debugf("Couldn't write price cache entry: %v", err)
+		_ = os.Remove(f.Name())
+	}
+}
+
+func isPriceCacheEntryExpired(info fs.FileInfo, ttl time.Duration) bool {
+	return time.Since(info.ModTime()) > ttl
+}
+
+// PrunePriceCache removes all the entries in the price cache directory dir
+// that are older than the ttl, along with any temporary files left behind by
+// interrupted writes. It returns the number of entries removed.
+func PrunePriceCache(dir string, ttl time.Duration) (int, error) {
+	removed := 0
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !isPriceCacheEntryExpired(info, ttl) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(d.Name(), priceCacheFileExt) {
+			removed++
+		}
+
+		return nil
+	})
+
+	return removed, err
+}
@@ -0,0 +1,148 @@
+package prices
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newCachedTestSource(t *testing.T, source PricingSource) *CachedPricingSource {
+	runCtx := config.EmptyRunContext()
+	c := NewCachedPricingSource(runCtx, source)
+	c.dir = t.TempDir()
+	return c
+}
+
+func TestCachedPricingSource(t *testing.T) {
+	public := &stubSource{name: "pricing_api", prices: map[string]string{"a": "1"}}
+	cached := newCachedTestSource(t, public)
+
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Name: "r",
+			CostComponents: []*schema.CostComponent{
+				{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}},
+				{Name: "b", ProductFilter: &schema.ProductFilter{Sku: strPtr("b")}},
+			},
+		}
+	}
+
+	r := newResource()
+	err := GetPrices(config.EmptyRunContext(), cached, r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, public.queried)
+	assert.Equal(t, "1", r.CostComponents[0].Price().String())
+
+	public.queried = nil
+	r = newResource()
+	err = GetPrices(config.EmptyRunContext(), cached, r)
+	require.NoError(t, err)
+	assert.Empty(t, public.queried, "cached queries should not hit the source")
+	assert.Equal(t, "1", r.CostComponents[0].Price().String())
+	assert.Equal(t, "pricing_api", r.CostComponents[0].PriceSource())
+	assert.Equal(t, "pricing_api-hash", r.CostComponents[0].PriceHash())
+
+	cached.currency = "EUR"
+	_, err = cached.QueryPrices(context.Background(), apiclient.PriceQueryKeys(newResource()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, public.queried, "a different currency should not use the cache")
+}
+
+func TestCachedPricingSourceEndpoint(t *testing.T) {
+	runCtx := config.EmptyRunContext()
+	dir := t.TempDir()
+	keys := apiclient.PriceQueryKeys(&schema.Resource{
+		Name:           "r",
+		CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}}},
+	})
+
+	queried := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queried++
+		_, _ = w.Write([]byte(`[{"data":{"products":[{"prices":[{"priceHash":"hash","USD":"1"}]}]}}]`))
+	}))
+	defer server.Close()
+
+	newSource := func(endpoint string) *CachedPricingSource {
+		c := NewCachedPricingSource(runCtx, apiclient.NewNamedPricingAPIClient(runCtx, apiclient.DefaultPricingSourceName, server.URL+endpoint, ""))
+		c.dir = dir
+		return c
+	}
+
+	_, err := newSource("/a").QueryPrices(context.Background(), keys)
+	require.NoError(t, err)
+	_, err = newSource("/a").QueryPrices(context.Background(), keys)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queried)
+
+	// A source with the same name but a different endpoint doesn't use the
+	// cached prices of the other endpoint.
+	_, err = newSource("/b").QueryPrices(context.Background(), keys)
+	require.NoError(t, err)
+	assert.Equal(t, 2, queried)
+}
+
+func TestCachedPricingSourceExpired(t *testing.T) {
+	public := &stubSource{name: "pricing_api", prices: map[string]string{"a": "1"}}
+	cached := newCachedTestSource(t, public)
+
+	keys := apiclient.PriceQueryKeys(&schema.Resource{
+		Name:           "r",
+		CostComponents: []*schema.CostComponent{{Name: "a"}},
+	})
+
+	_, err := cached.QueryPrices(context.Background(), keys)
+	require.NoError(t, err)
+
+	expired := time.Now().Add(-2 * config.DefaultPriceCacheTTL)
+	p := cached.path(cached.hash(nil, nil))
+	require.NoError(t, os.Chtimes(p, expired, expired))
+
+	_, err = cached.QueryPrices(context.Background(), keys)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "a"}, public.queried)
+}
+
+func TestPrunePriceCache(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name string, age time.Duration) {
+		p := filepath.Join(dir, name[:2], name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, os.WriteFile(p, []byte(`{}`), 0600))
+
+		mtime := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(p, mtime, mtime))
+	}
+
+	write("aa1.json", time.Hour)
+	write("aa2.json", 48*time.Hour)
+	write("bb1.json", 72*time.Hour)
+	write("bb2.tmp123", time.Minute)
+	write("bb3.tmp456", 48*time.Hour)
+
+	removed, err := PrunePriceCache(dir, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	assert.FileExists(t, filepath.Join(dir, "aa", "aa1.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "aa", "aa2.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "bb", "bb1.json"))
+	assert.FileExists(t, filepath.Join(dir, "bb", "bb2.tmp123"))
+	assert.NoFileExists(t, filepath.Join(dir, "bb", "bb3.tmp456"))
+
+	removed, err = PrunePriceCache(filepath.Join(dir, "missing"), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
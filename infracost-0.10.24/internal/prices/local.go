@@ -0,0 +1,443 @@
+package prices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/currency"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	// DefaultLocalPricingSourceName is the name used to identify prices returned
+	// from local pricing data when no name has been configured for it.
+	DefaultLocalPricingSourceName = "local"
+
+	// localProductLimit mirrors the maximum number of products the Cloud Pricing
+	// API returns for a single query.
+	localProductLimit = 1000
+
+	// cnyCurrency is the currency of the prices of AWS China regions. The
+	// Cloud Pricing API converts them to USD when they are queried.
+	cnyCurrency = "CNY"
+)
+
+var (
+	localStoresMu sync.Mutex
+	// localStores caches the loaded pricing data by path so that the data is
+	// only read once per run, even when there are multiple projects.
+	localStores = map[string]*localProductStore{}
+
+	regexLiteral = regexp.MustCompile(`/(.+)/(.*)`)
+)
+
+// LocalPricingClient answers price queries in-process from a local snapshot
+// of the Cloud Pricing API products table. It matches product and price
+// filters the same way the Cloud Pricing API does, so runs using it produce
+// the same costs without any network access.
+type LocalPricingClient struct {
+	name  string
+	store *localProductStore
+	rates *currency.ExchangeRates
+}
+
+// NewLocalPricingClient returns a LocalPricingClient using the pricing data
+// found at the Config.PricingDataPath.
+func NewLocalPricingClient(ctx *config.RunContext) (*LocalPricingClient, error) {
+	return NewNamedLocalPricingClient(ctx, DefaultLocalPricingSourceName, ctx.Config.PricingDataPath)
+}
+
+// NewNamedLocalPricingClient returns a LocalPricingClient using the pricing
+// data found at path. The name is recorded against all the prices returned by
+// the client so that it can be distinguished from other pricing sources.
+//
+// The pricing data only has prices in USD, and CNY for AWS China regions, so
+// an error is returned if prices are needed in any other currency. The
+// exchange rates are used to convert the CNY prices to USD.
+func NewNamedLocalPricingClient(ctx *config.RunContext, name, path string) (*LocalPricingClient, error) {
+	if c := ctx.Config.PricingCurrency(); c != currency.BaseCurrency {
+		return nil, fmt.Errorf("Local pricing data only has %s prices, use --exchange-rates to convert them to %s", currency.BaseCurrency, c)
+	}
+
+	store, err := loadLocalProductStore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalPricingClient{name: name, store: store, rates: ctx.Config.ExchangeRates}, nil
+}
+
+// Name returns the name of the client as a pricing source.
+func (c *LocalPricingClient) Name() string {
+	return c.name
+}
+
+// QueryPrices looks up the prices for the cost components of the given keys.
+// The results are in the same format and order as the results returned by the
+// Pricing API.
+func (c *LocalPricingClient) QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	results := make([]apiclient.PriceQueryResult, 0, len(keys))
+
+	for _, k := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		results = append(results, apiclient.PriceQueryResult{
+			PriceQueryKey: k,
+			Result:        c.store.query(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter, c.rates),
+			Source:        c.name,
+		})
+	}
+
+	return results, nil
+}
+
+type localProductStore struct {
+	products []*localProduct
+	// byService indexes the products by vendorName and service since nearly
+	// all queries filter by both.
+	byService map[string][]*localProduct
+
+	regexMu sync.RWMutex
+	regexes map[string]*regexp.Regexp
+}
+
+func loadLocalProductStore(path string) (*localProductStore, error) {
+	localStoresMu.Lock()
+	defer localStoresMu.Unlock()
+
+	if s, ok := localStores[path]; ok {
+		return s, nil
+	}
+
+	log.Debugf("Loading pricing data from %s", path)
+
+	products, err := loadLocalProducts(path)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Loaded %d products from %s", len(products), path)
+
+	s := newLocalProductStore(products)
+	localStores[path] = s
+
+	return s, nil
+}
+
+func newLocalProductStore(products []*localProduct) *localProductStore {
+	s := &localProductStore{
+		products:  products,
+		byService: map[string][]*localProduct{},
+		regexes:   map[string]*regexp.Regexp{},
+	}
+
+	for _, p := range products {
+		k := serviceKey(p.VendorName, p.Service)
+		s.byService[k] = append(s.byService[k], p)
+	}
+
+	return s
+}
+
+func serviceKey(vendorName, service string) string {
+	return vendorName + "/" + service
+}
+
+// query returns the products and prices matching the filters, wrapped in the
+// same response structure as the GraphQL products query. The rates are used
+// to convert CNY prices to USD, if they are nil the CNY prices are left out.
+func (s *localProductStore) query(productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter, rates *currency.ExchangeRates) gjson.Result {
+	candidates := s.products
+	if productFilter != nil && productFilter.VendorName != nil && productFilter.Service != nil && *productFilter.Service != "" {
+		candidates = s.byService[serviceKey(*productFilter.VendorName, *productFilter.Service)]
+	}
+
+	var b strings.Builder
+	b.WriteString(`{"data":{"products":[`)
+
+	n := 0
+	for _, p := range candidates {
+		if !s.matchProduct(p, productFilter) {
+			continue
+		}
+
+		if n > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(`{"sku":`)
+		writeJSONString(&b, p.Sku)
+		b.WriteString(`,"attributes":[`)
+		writeAttributes(&b, p)
+		b.WriteString(`],"prices":[`)
+		s.writePrices(&b, p, priceFilter, rates)
+		b.WriteString("]}")
+
+		n++
+		if n == localProductLimit {
+			break
+		}
+	}
+
+	b.WriteString("]}}")
+
+	return gjson.Parse(b.String())
+}
+
+func (s *localProductStore) matchProduct(p *localProduct, f *schema.ProductFilter) bool {
+	if f == nil {
+		return true
+	}
+
+	if !matchValue(f.VendorName, p.VendorName) ||
+		!matchValue(f.Service, p.Service) ||
+		!matchValue(f.ProductFamily, p.ProductFamily) ||
+		!matchValue(f.Region, p.Region) ||
+		!matchValue(f.Sku, p.Sku) {
+		return false
+	}
+
+	if len(f.AttributeFilters) == 0 {
+		return true
+	}
+
+	attributes := gjson.Parse(p.Attributes)
+	for _, af := range f.AttributeFilters {
+		if !s.matchAttribute(attributes, af) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *localProductStore) matchAttribute(attributes gjson.Result, f *schema.AttributeFilter) bool {
+	v, exists := jsonField(attributes, f.Key)
+
+	if f.ValueRegex != nil {
+		if !exists {
+			return false
+		}
+
+		return s.regex(*f.ValueRegex).MatchString(v)
+	}
+
+	return matchValue(f.Value, v)
+}
+
+// writeAttributes writes the attributes of the product as a comma separated
+// list of key/value objects, the same as the GraphQL products query.
+func writeAttributes(b *strings.Builder, p *localProduct) {
+	n := 0
+	gjson.Parse(p.Attributes).ForEach(func(k, v gjson.Result) bool {
+		if n > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(`{"key":`)
+		writeJSONString(b, k.String())
+		b.WriteString(`,"value":`)
+		writeJSONString(b, v.String())
+		b.WriteString("}")
+		n++
+
+		return true
+	})
+}
+
+func writeJSONString(b *strings.Builder, s string) {
+	v, _ := json.Marshal(s)
+	b.Write(v)
+}
+
+// writePrices writes the prices of the product that match the price filter
+// as a comma separated list of JSON objects.
+func (s *localProductStore) writePrices(b *strings.Builder, p *localProduct, f *schema.PriceFilter, rates *currency.ExchangeRates) {
+	n := 0
+	gjson.Parse(p.Prices).ForEach(func(_, prices gjson.Result) bool {
+		for _, price := range prices.Array() {
+			if !s.matchPrice(price, f) {
+				continue
+			}
+
+			raw := price.Raw
+			if !price.Get(currency.BaseCurrency).Exists() && price.Get(cnyCurrency).Exists() {
+				var ok bool
+				raw, ok = withBaseCurrencyPrice(price, rates)
+				if !ok {
+					continue
+				}
+			}
+
+			if n > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(raw)
+			n++
+		}
+
+		return true
+	})
+}
+
+// withBaseCurrencyPrice returns the JSON of a CNY price with the price
+// converted to USD added, the same as the Cloud Pricing API does. It returns
+// false if the price can't be converted since there is no CNY exchange rate.
+func withBaseCurrencyPrice(price gjson.Result, rates *currency.ExchangeRates) (string, bool) {
+	hash := price.Get("priceHash").String()
+
+	if rates == nil {
+		log.Debugf("Skipping price %s as it is in %s, use --exchange-rates with a %s rate to convert it to %s", hash, cnyCurrency, cnyCurrency, currency.BaseCurrency)
+		return "", false
+	}
+
+	amount, err := decimal.NewFromString(price.Get(cnyCurrency).String())
+	if err != nil {
+		log.Debugf("Skipping price %s as it has an invalid %s price: %s", hash, cnyCurrency, err)
+		return "", false
+	}
+
+	converted, err := rates.Convert(amount, cnyCurrency, currency.BaseCurrency)
+	if err != nil {
+		log.Debugf("Skipping price %s as it can't be converted from %s: %s", hash, cnyCurrency, err)
+		return "", false
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(price.Raw), &m); err != nil {
+		return "", false
+	}
+	m[currency.BaseCurrency] = converted.String()
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", false
+	}
+
+	return string(b), true
+}
+
+func (s *localProductStore) matchPrice(price gjson.Result, f *schema.PriceFilter) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.DescriptionRegex != nil {
+		description, _ := jsonField(price, "description")
+		if !s.regex(*f.DescriptionRegex).MatchString(description) {
+			return false
+		}
+	}
+
+	fields := []struct {
+		filter *string
+		key    string
+	}{
+		{f.PurchaseOption, "purchaseOption"},
+		{f.Unit, "unit"},
+		{f.Description, "description"},
+		{f.StartUsageAmount, "startUsageAmount"},
+		{f.EndUsageAmount, "endUsageAmount"},
+		{f.TermLength, "termLength"},
+		{f.TermPurchaseOption, "termPurchaseOption"},
+		{f.TermOfferingClass, "termOfferingClass"},
+	}
+
+	for _, field := range fields {
+		v, _ := jsonField(price, field.key)
+		if !matchValue(field.filter, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// regex compiles a regex in the /pattern/flags format used by the filters,
+// caching the result since the same regexes are used across many queries.
+func (s *localProductStore) regex(str string) *regexp.Regexp {
+	s.regexMu.RLock()
+	re, ok := s.regexes[str]
+	s.regexMu.RUnlock()
+	if ok {
+		return re
+	}
+
+	re, err := parseFilterRegex(str)
+	if err != nil {
+		log.Warnf("Invalid regex %s in price query, no products will match: %s", str, err)
+		re = regexp.MustCompile(`$^`)
+	}
+
+	s.regexMu.Lock()
+	s.regexes[str] = re
+	s.regexMu.Unlock()
+
+	return re
+}
+
+// parseFilterRegex converts a /pattern/flags string into a Go regex. Strings
+// not in this format result in an empty pattern which matches everything, the
+// same as the Cloud Pricing API.
+func parseFilterRegex(str string) (*regexp.Regexp, error) {
+	var pattern, flags string
+	if m := regexLiteral.FindStringSubmatch(str); m != nil {
+		pattern, flags = m[1], m[2]
+	}
+
+	var goFlags string
+	for _, f := range flags {
+		switch f {
+		case 'i', 'm', 's':
+			goFlags += string(f)
+		}
+	}
+
+	if goFlags != "" {
+		pattern = fmt.Sprintf("(?%s)%s", goFlags, pattern)
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// matchValue checks if the value matches the filter. A nil filter matches any
+// value and an empty filter matches empty or missing values.
+func matchValue(filter *string, v string) bool {
+	if filter == nil {
+		return true
+	}
+
+	return *filter == v
+}
+
+// jsonField returns the string value of the top-level key of a JSON object.
+// The object is iterated rather than using a gjson path since the keys can
+// contain characters with special meaning in a path.
+func jsonField(obj gjson.Result, key string) (string, bool) {
+	var (
+		v      string
+		exists bool
+	)
+
+	obj.ForEach(func(k, val gjson.Result) bool {
+		if k.String() != key {
+			return true
+		}
+
+		exists = val.Type != gjson.Null
+		v = val.String()
+		return false
+	})
+
+	return v, exists
+}
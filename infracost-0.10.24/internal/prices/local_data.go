@@ -0,0 +1,434 @@
+package prices
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// localProduct is a single row of the Cloud Pricing API products table. The
+// attributes and prices columns are kept as raw JSON and only parsed when a
+// query needs them, since a full snapshot contains millions of products.
+type localProduct struct {
+	ProductHash   string
+	Sku           string
+	VendorName    string
+	Region        string
+	Service       string
+	ProductFamily string
+	Attributes    string
+	Prices        string
+}
+
+var localProductColumns = []string{"productHash", "sku", "vendorName", "region", "service", "productFamily", "attributes", "prices"}
+
+// loadLocalProducts loads all the products from the given path. The path can
+// either be a single file or a directory containing files in any of the
+// supported formats:
+//
+//   - .csv or .csv.gz: the data dumps downloaded from or created by the Cloud Pricing API.
+//   - .sql: INSERT INTO products statements, as exported by most SQL clients.
+func loadLocalProducts(path string) ([]*localProduct, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading pricing data")
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = localPricingDataFiles(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("No pricing data files found in %s, expected .csv, .csv.gz or .sql files", path)
+		}
+	}
+
+	var products []*localProduct
+	for _, file := range files {
+		p, err := loadLocalProductsFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error loading pricing data from %s", file)
+		}
+
+		products = append(products, p...)
+	}
+
+	return products, nil
+}
+
+func localPricingDataFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading pricing data directory")
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !isLocalPricingDataFile(e.Name()) {
+			continue
+		}
+
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func isLocalPricingDataFile(name string) bool {
+	return strings.HasSuffix(name, ".csv") || strings.HasSuffix(name, ".csv.gz") || strings.HasSuffix(name, ".sql")
+}
+
+func loadLocalProductsFile(path string) ([]*localProduct, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	if strings.HasSuffix(strings.TrimSuffix(path, ".gz"), ".csv") {
+		return parseProductsCSV(r)
+	}
+
+	return parseProductsSQL(r)
+}
+
+// parseProductsCSV parses the CSV data dumps. These have a header row with
+// the column names so the column order doesn't matter.
+func parseProductsCSV(r io.Reader) ([]*localProduct, error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.ReuseRecord = false
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading CSV header")
+	}
+
+	var products []*localProduct
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]*string, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				v := record[i]
+				row[col] = &v
+			}
+		}
+
+		p, err := newLocalProduct(row)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, p)
+	}
+
+	return products, nil
+}
+
+// parseProductsSQL parses INSERT statements for the products table, e.g.
+//
+//	INSERT INTO products ("productHash",sku,...) VALUES
+//		('25e38b52ab8a22e11562f89bd78c03f5','6BHH8YTJ6V9FQ4E9',...),
+//		(...);
+//
+// Only single-quoted strings, NULL and bare literals are supported as values.
+func parseProductsSQL(r io.Reader) ([]*localProduct, error) {
+	s := &sqlScanner{r: bufio.NewReader(r)}
+
+	var products []*localProduct
+	for {
+		ok, err := s.skipTo("INSERT INTO")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		cols, err := s.readColumns()
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err = s.skipTo("VALUES")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("Expected VALUES after INSERT INTO")
+		}
+
+		for {
+			values, err := s.readTuple()
+			if err != nil {
+				return nil, err
+			}
+
+			if len(values) != len(cols) {
+				return nil, fmt.Errorf("Expected %d values but got %d", len(cols), len(values))
+			}
+
+			row := make(map[string]*string, len(cols))
+			for i, col := range cols {
+				row[col] = values[i]
+			}
+
+			p, err := newLocalProduct(row)
+			if err != nil {
+				return nil, err
+			}
+			products = append(products, p)
+
+			c, err := s.nextNonSpace()
+			if err != nil {
+				return nil, err
+			}
+			if c == ';' {
+				break
+			}
+			if c != ',' {
+				return nil, fmt.Errorf("Unexpected character %q after values", c)
+			}
+		}
+	}
+
+	return products, nil
+}
+
+func newLocalProduct(row map[string]*string) (*localProduct, error) {
+	for _, col := range localProductColumns {
+		if _, ok := row[col]; !ok {
+			return nil, fmt.Errorf("Missing %s column", col)
+		}
+	}
+
+	val := func(col string) string {
+		if v := row[col]; v != nil {
+			return *v
+		}
+		return ""
+	}
+
+	return &localProduct{
+		ProductHash:   val("productHash"),
+		Sku:           val("sku"),
+		VendorName:    val("vendorName"),
+		Region:        val("region"),
+		Service:       val("service"),
+		ProductFamily: val("productFamily"),
+		Attributes:    val("attributes"),
+		Prices:        val("prices"),
+	}, nil
+}
+
+type sqlScanner struct {
+	r *bufio.Reader
+}
+
+// skipTo advances the scanner past the next occurrence of keyword. It returns
+// false if the end of the input is reached first.
+func (s *sqlScanner) skipTo(keyword string) (bool, error) {
+	matched := 0
+	for {
+		c, err := s.r.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if c == '\'' {
+			if _, err := s.readString(); err != nil {
+				return false, err
+			}
+			matched = 0
+			continue
+		}
+
+		if toUpper(c) == keyword[matched] {
+			matched++
+			if matched == len(keyword) {
+				return true, nil
+			}
+			continue
+		}
+
+		matched = 0
+		if toUpper(c) == keyword[0] {
+			matched = 1
+		}
+	}
+}
+
+// readColumns reads the table name and column list of an INSERT statement
+// and returns the unquoted column names.
+func (s *sqlScanner) readColumns() ([]string, error) {
+	if _, err := s.r.ReadString('('); err != nil {
+		return nil, errors.New("Expected column list after INSERT INTO")
+	}
+
+	list, err := s.r.ReadString(')')
+	if err != nil {
+		return nil, errors.New("Unterminated column list")
+	}
+
+	var cols []string
+	for _, col := range strings.Split(strings.TrimSuffix(list, ")"), ",") {
+		cols = append(cols, strings.Trim(strings.TrimSpace(col), `"`))
+	}
+
+	return cols, nil
+}
+
+func (s *sqlScanner) readTuple() ([]*string, error) {
+	c, err := s.nextNonSpace()
+	if err != nil {
+		return nil, err
+	}
+	if c != '(' {
+		return nil, fmt.Errorf("Expected ( but got %q", c)
+	}
+
+	var values []*string
+	for {
+		c, err := s.nextNonSpace()
+		if err != nil {
+			return nil, err
+		}
+
+		if c == '\'' {
+			v, err := s.readString()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, &v)
+		} else {
+			_ = s.r.UnreadByte()
+			v, err := s.readLiteral()
+			if err != nil {
+				return nil, err
+			}
+
+			if strings.EqualFold(v, "NULL") {
+				values = append(values, nil)
+			} else {
+				values = append(values, &v)
+			}
+		}
+
+		c, err = s.nextNonSpace()
+		if err != nil {
+			return nil, err
+		}
+		if c == ')' {
+			return values, nil
+		}
+		if c != ',' {
+			return nil, fmt.Errorf("Unexpected character %q in values", c)
+		}
+	}
+}
+
+// readString reads a single-quoted string, the opening quote must already be
+// consumed. Quotes inside the string are escaped by doubling them.
+func (s *sqlScanner) readString() (string, error) {
+	var b strings.Builder
+	for {
+		part, err := s.r.ReadString('\'')
+		if err != nil {
+			return "", errors.New("Unterminated string")
+		}
+		b.WriteString(part[:len(part)-1])
+
+		next, err := s.r.ReadByte()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if next != '\'' {
+			_ = s.r.UnreadByte()
+			return b.String(), nil
+		}
+
+		b.WriteByte('\'')
+	}
+}
+
+func (s *sqlScanner) readLiteral() (string, error) {
+	var b strings.Builder
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return "", errors.New("Unterminated values")
+		}
+
+		if c == ',' || c == ')' || isSpace(c) {
+			_ = s.r.UnreadByte()
+			return b.String(), nil
+		}
+
+		b.WriteByte(c)
+	}
+}
+
+func (s *sqlScanner) nextNonSpace() (byte, error) {
+	for {
+		c, err := s.r.ReadByte()
+		if err == io.EOF {
+			return 0, errors.New("Unexpected end of SQL")
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if !isSpace(c) {
+			return c, nil
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func toUpper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+	return c
+}
@@ -0,0 +1,223 @@
+package prices
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/currency"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newLocalTestRunContext(path string) *config.RunContext {
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.PricingDataPath = path
+	return runCtx
+}
+
+func TestLocalPricingClient(t *testing.T) {
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+	c, err := NewLocalPricingClient(runCtx)
+	require.NoError(t, err)
+
+	instance := &schema.CostComponent{
+		Name: "Instance usage (Linux/UNIX, on-demand, t3.medium)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr("us-east-1"),
+			Service:       strPtr("AmazonEC2"),
+			ProductFamily: strPtr("Compute Instance"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.medium")},
+				{Key: "tenancy", Value: strPtr("Shared")},
+				{Key: "operatingSystem", ValueRegex: strPtr("/linux/i")},
+				{Key: "licenseModel", Value: strPtr("")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+
+	reserved := &schema.CostComponent{
+		Name: "Instance usage (Linux/UNIX, reserved, t3.medium)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonEC2"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/BoxUsage:t3.medium/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption:     strPtr("reserved"),
+			TermLength:         strPtr("1yr"),
+			TermPurchaseOption: strPtr("No Upfront"),
+		},
+	}
+
+	durationTier2 := &schema.CostComponent{
+		Name: "Duration (over 6B GB-seconds)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr("us-east-1"),
+			Service:    strPtr("AWSLambda"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "group", Value: strPtr("AWS-Lambda-Duration")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr("6000000000"),
+		},
+	}
+
+	requests := &schema.CostComponent{
+		Name: "Requests",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(""),
+			Service:    strPtr("AWSLambda"),
+		},
+		PriceFilter: &schema.PriceFilter{
+			DescriptionRegex: strPtr("/it's free/"),
+		},
+	}
+
+	vm := &schema.CostComponent{
+		Name: "Instance usage (pay as you go, Standard_D2s_v3)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("azure"),
+			Region:     strPtr("eastus"),
+			Service:    strPtr("Virtual Machines"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "armSkuName", Value: strPtr("Standard_D2s_v3")},
+				{Key: "productName", ValueRegex: strPtr("/^Virtual Machines DSv3 Series$/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+
+	missing := &schema.CostComponent{
+		Name: "Missing",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonEC2"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.large")},
+			},
+		},
+	}
+
+	r := &schema.Resource{
+		Name:           "test",
+		CostComponents: []*schema.CostComponent{instance, reserved, durationTier2, requests, missing},
+		SubResources: []*schema.Resource{
+			{Name: "test.vm", CostComponents: []*schema.CostComponent{vm}},
+		},
+	}
+
+	results, err := c.QueryPrices(context.Background(), apiclient.PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	assert.Equal(t, r.SubResources[0], results[5].Resource)
+	assert.Equal(t, vm, results[5].CostComponent)
+
+	for i, expected := range [][]string{
+		{"0.0416000000"},
+		{"0.0260000000"},
+		{"0.0000150000"},
+		{"0.0000000000"},
+		{},
+		{"0.0960000000"},
+	} {
+		var prices []string
+		for _, p := range results[i].Result.Get("data.products").Array() {
+			for _, price := range p.Get("prices").Array() {
+				prices = append(prices, price.Get("USD").String())
+			}
+		}
+
+		assert.ElementsMatch(t, expected, prices, results[i].CostComponent.Name)
+	}
+
+	err = GetPricesConcurrent(runCtx, c, []*schema.Resource{r})
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0416", instance.Price().String())
+	assert.Equal(t, "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-d2c98780d7b6e36641b521f1f8145c6f", instance.PriceHash())
+	assert.Equal(t, DefaultLocalPricingSourceName, instance.PriceSource())
+	assert.True(t, vm.Price().Equal(decimal.RequireFromString("0.096")))
+	assert.True(t, missing.Price().IsZero())
+}
+
+func TestLoadLocalProductsErrors(t *testing.T) {
+	_, err := NewLocalPricingClient(newLocalTestRunContext("testdata/does_not_exist"))
+	assert.Error(t, err)
+
+	_, err = NewLocalPricingClient(newLocalTestRunContext("testdata"))
+	assert.ErrorContains(t, err, "No pricing data files found")
+}
+
+func TestLocalPricingClientCurrency(t *testing.T) {
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+	runCtx.Config.Currency = "EUR"
+
+	_, err := NewLocalPricingClient(runCtx)
+	assert.EqualError(t, err, "Local pricing data only has USD prices, use --exchange-rates to convert them to EUR")
+
+	runCtx.Config.ExchangeRates, err = currency.LoadExchangeRatesFromString("version: 0.1\nrates:\n  EUR: 0.9\n")
+	require.NoError(t, err)
+
+	_, err = NewLocalPricingClient(runCtx)
+	assert.NoError(t, err)
+}
+
+func TestLocalPricingClientCNYPrices(t *testing.T) {
+	instance := &schema.CostComponent{
+		Name: "Instance usage (Linux/UNIX, on-demand, t3.medium)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr("cn-north-1"),
+			Service:    strPtr("AmazonEC2"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.medium")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+	r := &schema.Resource{Name: "test", CostComponents: []*schema.CostComponent{instance}}
+
+	// Without a CNY exchange rate the price can't be converted so it is left out
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+	c, err := NewLocalPricingClient(runCtx)
+	require.NoError(t, err)
+
+	results, err := c.QueryPrices(context.Background(), apiclient.PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Len(t, results[0].Result.Get("data.products.0.prices").Array(), 0)
+
+	runCtx.Config.ExchangeRates, err = currency.LoadExchangeRatesFromString("version: 0.1\nrates:\n  CNY: 7.5\n")
+	require.NoError(t, err)
+	c, err = NewLocalPricingClient(runCtx)
+	require.NoError(t, err)
+
+	results, err = c.QueryPrices(context.Background(), apiclient.PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "0.04", results[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, "0.3000000000", results[0].Result.Get("data.products.0.prices.0.CNY").String())
+}
+
+func strPtr(s string) *string {
+	return &s
+}
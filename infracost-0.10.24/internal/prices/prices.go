@@ -1,34 +1,175 @@
 package prices
 
 import (
+	"encoding/json"
+	"fmt"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/discounts"
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
+	"golang.org/x/sync/errgroup"
 )
 
-func PopulatePrices(ctx *config.RunContext, project *schema.Project) error {
-	resources := project.AllResources()
+const (
+	// maxBatchQueries bounds the number of price queries sent to a pricing
+	// source at once. For the Pricing API a batch is sent in one request.
+	maxBatchQueries = 500
+	// maxBatchBytes bounds the approximate size of the queries in a batch. The
+	// Cloud Pricing API rejects request bodies over 100KB.
+	maxBatchBytes = 80 * 1024
+	// queryOverheadBytes approximates the size of the GraphQL query text that
+	// is sent alongside the filters of each query.
+	queryOverheadBytes = 200
+)
+
+// priceQuery is a unique pair of product and price filters along with the
+// keys of all the cost components that share them.
+type priceQuery struct {
+	keys []apiclient.PriceQueryKey
+	size int
+}
+
+// PopulatePrices gets the prices of all the resources of the projects and
+// applies the rules from the discounts file if one is set. The queries are
+// shared between the projects, so passing all the projects in a run at once
+// means each distinct query is only made once. If strict pricing is enabled a
+// StrictPricingError is returned when any of the prices were missing or
+// ambiguous.
+func PopulatePrices(ctx *config.RunContext, projects ...*schema.Project) error {
+	c, err := NewPricingSource(ctx)
+	if err != nil {
+		return err
+	}
+
+	return PopulatePricesFrom(ctx, c, projects...)
+}
 
-	c := apiclient.NewPricingAPIClient(ctx)
+// PopulatePricesFrom is the same as PopulatePrices but gets the prices from
+// the pricing source c, so a source can be reused between calls.
+func PopulatePricesFrom(ctx *config.RunContext, c PricingSource, projects ...*schema.Project) error {
+	resources := make([]*schema.Resource, 0)
+	for _, project := range projects {
+		resources = append(resources, project.AllResources()...)
+	}
+
+	return PopulateResourcePrices(ctx, c, resources)
+}
 
+// PopulateResourcePrices gets the prices of the resources from the pricing
+// source c and applies the rules from the discounts file if one is set. If
+// strict pricing is enabled a StrictPricingError is returned when any of the
+// prices were missing or ambiguous.
+func PopulateResourcePrices(ctx *config.RunContext, c PricingSource, resources []*schema.Resource) error {
 	err := GetPricesConcurrent(ctx, c, resources)
 	if err != nil {
 		return err
 	}
+
+	err = applyDiscountsFile(ctx, resources)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Config.StrictPricing {
+		return checkStrictPricing(resources)
+	}
+
+	return nil
+}
+
+// applyDiscountsFile applies the rules from the discounts file to the prices
+// of the resources if one is set.
+func applyDiscountsFile(ctx *config.RunContext, resources []*schema.Resource) error {
+	if ctx.Config.DiscountsFile == "" {
+		return nil
+	}
+
+	f, err := discounts.LoadDiscountsFile(ctx.Config.DiscountsFile)
+	if err != nil {
+		return err
+	}
+
+	f.Apply(resources)
 	return nil
 }
 
-// GetPricesConcurrent gets the prices of all resources concurrently.
+// GetPricesConcurrent gets the prices of all resources. Cost components with
+// the same product and price filters are deduplicated across the resources
+// and the unique queries are sent to the pricing source in size-bounded
+// batches, which are run concurrently. If any batch fails the others are
+// cancelled and the error is returned.
+func GetPricesConcurrent(ctx *config.RunContext, c PricingSource, resources []*schema.Resource) error {
+	currency := ctx.Config.PricingCurrency()
+
+	queries, customPriceKeys := collectPriceQueries(resources)
+	for _, k := range customPriceKeys {
+		setCostComponentPrice(ctx, currency, k.Resource, k.CostComponent, gjson.Result{}, "")
+	}
+
+	batches := batchPriceQueries(queries)
+	log.Debugf("Getting pricing details from %s for %d unique queries in %d batches", c.Name(), len(queries), len(batches))
+
+	results := make([][]apiclient.PriceQueryResult, len(batches))
+
+	g, gctx := errgroup.WithContext(ctx.Context())
+	g.SetLimit(numPriceWorkers())
+
+	for i, batch := range batches {
+		i, batch := i, batch
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+
+			keys := make([]apiclient.PriceQueryKey, 0, len(batch))
+			for _, q := range batch {
+				keys = append(keys, q.keys[0])
+			}
+
+			res, err := c.QueryPrices(gctx, keys)
+			if err != nil {
+				return err
+			}
+
+			results[i] = res
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// Fan the results out to every cost component sharing the query. This is
+	// done once all the batches are complete since setting the price can
+	// modify the resource.
+	var priceDateIssues []*PriceDateIssue
+	for i, batch := range batches {
+		for j, q := range batch {
+			res := results[i][j]
+			for _, k := range q.keys {
+				if issue := setCostComponentPrice(ctx, currency, k.Resource, k.CostComponent, res.Result, res.Source); issue != nil {
+					priceDateIssues = append(priceDateIssues, issue)
+				}
+			}
+		}
+	}
+
+	return priceDateError(ctx, priceDateIssues)
+}
+
+// numPriceWorkers returns the number of batches to run concurrently.
 // Concurrency level is calculated using the following formula:
-// max(min(4, numCPU * 4), 16)
-func GetPricesConcurrent(ctx *config.RunContext, c *apiclient.PricingAPIClient, resources []*schema.Resource) error {
-	// Set the number of workers
+// min(max(4, numCPU * 4), 16)
+func numPriceWorkers() int {
 	numWorkers := 4
 	numCPU := runtime.NumCPU()
 	if numCPU*4 > numWorkers {
@@ -37,59 +178,123 @@ func GetPricesConcurrent(ctx *config.RunContext, c *apiclient.PricingAPIClient,
 	if numWorkers > 16 {
 		numWorkers = 16
 	}
-	numJobs := len(resources)
-	jobs := make(chan *schema.Resource, numJobs)
-	resultErrors := make(chan error, numJobs)
 
-	// Fire up the workers
-	for i := 0; i < numWorkers; i++ {
-		go func(jobs <-chan *schema.Resource, resultErrors chan<- error) {
-			for r := range jobs {
-				err := GetPrices(ctx, c, r)
-				resultErrors <- err
-			}
-		}(jobs, resultErrors)
-	}
+	return numWorkers
+}
+
+// collectPriceQueries groups the cost components of the resources by their
+// product and price filters. Cost components with a custom price don't need
+// querying so are returned separately.
+func collectPriceQueries(resources []*schema.Resource) ([]*priceQuery, []apiclient.PriceQueryKey) {
+	queries := make([]*priceQuery, 0)
+	customPriceKeys := make([]apiclient.PriceQueryKey, 0)
+	byFilters := map[string]*priceQuery{}
 
-	// Feed the workers the jobs of getting prices
 	for _, r := range resources {
-		jobs <- r
+		if r.IsSkipped {
+			continue
+		}
+
+		for _, k := range apiclient.PriceQueryKeys(r) {
+			if k.CostComponent.CustomPrice() != nil {
+				customPriceKeys = append(customPriceKeys, k)
+				continue
+			}
+
+			filters := priceQueryFilters(k.CostComponent)
+
+			q, ok := byFilters[filters]
+			if !ok {
+				q = &priceQuery{size: len(filters) + queryOverheadBytes}
+				byFilters[filters] = q
+				queries = append(queries, q)
+			}
+
+			q.keys = append(q.keys, k)
+		}
 	}
 
-	// Get the result of the jobs
-	for i := 0; i < numJobs; i++ {
-		err := <-resultErrors
-		if err != nil {
-			return err
+	return queries, customPriceKeys
+}
+
+// priceQueryFilters returns the serialized product and price filters of the
+// cost component. Cost components with equal filters get the same prices.
+func priceQueryFilters(c *schema.CostComponent) string {
+	b, _ := json.Marshal(struct {
+		ProductFilter *schema.ProductFilter `json:"productFilter"`
+		PriceFilter   *schema.PriceFilter   `json:"priceFilter"`
+	}{c.ProductFilter, c.PriceFilter})
+
+	return string(b)
+}
+
+// batchPriceQueries splits the queries into batches bounded by both
+// maxBatchQueries and maxBatchBytes. A query larger than maxBatchBytes is put
+// in a batch on its own.
+func batchPriceQueries(queries []*priceQuery) [][]*priceQuery {
+	batches := make([][]*priceQuery, 0)
+
+	var batch []*priceQuery
+	size := 0
+
+	for _, q := range queries {
+		if len(batch) > 0 && (len(batch) == maxBatchQueries || size+q.size > maxBatchBytes) {
+			batches = append(batches, batch)
+			batch = nil
+			size = 0
 		}
+
+		batch = append(batch, q)
+		size += q.size
 	}
-	return nil
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
 }
 
-func GetPrices(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.Resource) error {
+// GetPrices looks up the prices for all the cost components of the resource
+// from the pricing source c.
+func GetPrices(ctx *config.RunContext, c PricingSource, r *schema.Resource) error {
 	if r.IsSkipped {
 		return nil
 	}
 
-	results, err := c.RunQueries(r)
+	keys := apiclient.PriceQueryKeys(r)
+	if len(keys) == 0 {
+		log.Debugf("Skipping getting pricing details for %s since there are no queries to run", r.Name)
+		return nil
+	}
+
+	log.Debugf("Getting pricing details from %s for %s", c.Name(), r.Name)
+
+	results, err := c.QueryPrices(ctx.Context(), keys)
 	if err != nil {
 		return err
 	}
 
+	currency := ctx.Config.PricingCurrency()
+
+	var priceDateIssues []*PriceDateIssue
 	for _, r := range results {
-		setCostComponentPrice(ctx, c.Currency, r.Resource, r.CostComponent, r.Result)
+		if issue := setCostComponentPrice(ctx, currency, r.Resource, r.CostComponent, r.Result, r.Source); issue != nil {
+			priceDateIssues = append(priceDateIssues, issue)
+		}
 	}
 
-	return nil
+	return priceDateError(ctx, priceDateIssues)
 }
 
-func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Resource, c *schema.CostComponent, res gjson.Result) {
-	var p decimal.Decimal
-
+// setCostComponentPrice sets the price of the cost component from the result
+// of its price query. A PriceDateIssue is returned if the cost component has
+// prices but none of them were effective on the price date.
+func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Resource, c *schema.CostComponent, res gjson.Result, source string) *PriceDateIssue {
 	if c.CustomPrice() != nil {
 		log.Debugf("Using user-defined custom price %v for %s %s.", *c.CustomPrice(), r.Name, c.Name)
 		c.SetPrice(*c.CustomPrice())
-		return
+		return nil
 	}
 
 	products := res.Get("data.products").Array()
@@ -97,13 +302,14 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 		if c.IgnoreIfMissingPrice {
 			log.Debugf("No products found for %s %s, ignoring since IgnoreIfMissingPrice is set.", r.Name, c.Name)
 			r.RemoveCostComponent(c)
-			return
+			return nil
 		}
 
 		log.Warnf("No products found for %s %s, using 0.00", r.Name, c.Name)
 		setResourceWarningEvent(ctx, r, "No products found")
 		c.SetPrice(decimal.Zero)
-		return
+		c.SetPriceNotFound("No products found")
+		return nil
 	}
 
 	if len(products) > 1 {
@@ -115,24 +321,46 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 	// distinguished by their prices. However if we pick the first product it may not
 	// have the price due to price filter and the lookup fails. Filtering the
 	// products with prices helps to solve that.
-	productsWithPrices := []gjson.Result{}
+	priceDate, hasPriceDate := ctx.Config.EffectivePriceDate()
+
+	productsWithPrices := []productPrices{}
 	for _, product := range products {
-		if len(product.Get("prices").Array()) > 0 {
-			productsWithPrices = append(productsWithPrices, product)
+		prices := product.Get("prices").Array()
+		if hasPriceDate {
+			prices = pricesEffectiveAt(prices, priceDate)
+		}
+
+		if len(prices) > 0 {
+			productsWithPrices = append(productsWithPrices, productPrices{product: product, prices: prices})
 		}
 	}
 
 	if len(productsWithPrices) == 0 {
+		if earliest, ok := earliestEffectiveDate(products); hasPriceDate && ok {
+			log.Warnf("No prices effective on %s found for %s %s, using 0.00", priceDate.Format(config.PriceDateFormat), r.Name, c.Name)
+			c.SetPrice(decimal.Zero)
+			c.SetPriceNotFound("No prices effective on the price date")
+			return &PriceDateIssue{Resource: r.Name, CostComponent: c.Name, EarliestDate: earliest}
+		}
+
 		if c.IgnoreIfMissingPrice {
 			log.Debugf("No prices found for %s %s, ignoring since IgnoreIfMissingPrice is set.", r.Name, c.Name)
 			r.RemoveCostComponent(c)
-			return
+			return nil
 		}
 
 		log.Warnf("No prices found for %s %s, using 0.00", r.Name, c.Name)
 		setResourceWarningEvent(ctx, r, "No prices found")
 		c.SetPrice(decimal.Zero)
-		return
+		c.SetPriceNotFound("No prices found")
+		return nil
+	}
+
+	// A cost component that already has a price hash, e.g. one loaded from an
+	// Infracost JSON file to be repriced, keeps the same price if it is still
+	// returned, even if the lookup now finds other prices too.
+	if c.PriceHash() != "" {
+		productsWithPrices = withPriceHash(productsWithPrices, c.PriceHash())
 	}
 
 	if len(productsWithPrices) > 1 {
@@ -140,23 +368,238 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 		setResourceWarningEvent(ctx, r, "Multiple products found")
 	}
 
-	prices := productsWithPrices[0].Get("prices").Array()
+	prices := productsWithPrices[0].prices
 	if len(prices) > 1 {
 		log.Warnf("Multiple prices found for %s %s, using the first price", r.Name, c.Name)
 		setResourceWarningEvent(ctx, r, "Multiple prices found")
 	}
 
-	var err error
-	p, err = decimal.NewFromString(prices[0].Get(currency).String())
+	if (len(productsWithPrices) > 1 || len(prices) > 1) && ctx.Config.RecordsPriceCandidates() {
+		c.SetPriceCandidates(priceCandidates(ctx, currency, productsWithPrices))
+	}
+
+	p, err := parsePrice(ctx, currency, prices[0])
 	if err != nil {
-		log.Warnf("Error converting price to '%v' (using 0.00)  '%v': %s", currency, prices[0].Get(currency).String(), err.Error())
+		log.Warnf("Error converting price for %s %s (using 0.00): %s", r.Name, c.Name, err)
 		setResourceWarningEvent(ctx, r, "Error converting price")
 		c.SetPrice(decimal.Zero)
-		return
+		c.SetPriceNotFound("Error converting price")
+		return nil
+	}
+
+	if limit := maxPrice(ctx, currency, c); limit != nil && p.GreaterThan(*limit) {
+		log.Debugf("Using max price %s for %s %s since it is lower than the price %s", limit, r.Name, c.Name, p)
+		p = *limit
 	}
 
 	c.SetPrice(p)
 	c.SetPriceHash(prices[0].Get("priceHash").String())
+	c.SetPriceSource(source)
+	c.SetPriceSku(productsWithPrices[0].product.Get("sku").String())
+
+	return nil
+}
+
+// productPrices is a product returned by a price query along with the prices
+// of it that can be used.
+type productPrices struct {
+	product gjson.Result
+	prices  []gjson.Result
+}
+
+// withPriceHash returns only the product and price with the price hash if
+// there is one, otherwise it returns the products unchanged.
+func withPriceHash(products []productPrices, priceHash string) []productPrices {
+	for _, pp := range products {
+		for _, price := range pp.prices {
+			if price.Get("priceHash").String() == priceHash {
+				return []productPrices{{product: pp.product, prices: []gjson.Result{price}}}
+			}
+		}
+	}
+
+	return products
+}
+
+// parsePrice returns the price in the currency it was queried in, converted
+// to the output currency if exchange rates are set.
+func parsePrice(ctx *config.RunContext, currency string, price gjson.Result) (decimal.Decimal, error) {
+	p, err := decimal.NewFromString(price.Get(currency).String())
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s price '%s'", currency, price.Get(currency).String())
+	}
+
+	if ctx.Config.ExchangeRates != nil {
+		return ctx.Config.ExchangeRates.Convert(p, currency, ctx.Config.Currency)
+	}
+
+	return p, nil
+}
+
+// maxPrice returns the max price of the cost component in the output
+// currency, or nil if it has no max price or the max price can't be converted
+// to the output currency.
+func maxPrice(ctx *config.RunContext, currency string, c *schema.CostComponent) *decimal.Decimal {
+	if c.MaxPrice == nil {
+		return nil
+	}
+
+	if ctx.Config.ExchangeRates != nil {
+		limit, err := ctx.Config.ExchangeRates.Convert(*c.MaxPrice, "USD", ctx.Config.Currency)
+		if err != nil {
+			return nil
+		}
+
+		return &limit
+	}
+
+	if currency != "USD" {
+		log.Debugf("Ignoring max price for %s since prices are in %s", c.Name, currency)
+		return nil
+	}
+
+	return c.MaxPrice
+}
+
+// priceCandidates returns a candidate for every price of the products, in the
+// order they are considered, so the first candidate is the one that is used.
+func priceCandidates(ctx *config.RunContext, currency string, products []productPrices) []*schema.PriceCandidate {
+	candidates := make([]*schema.PriceCandidate, 0, len(products))
+
+	for _, pp := range products {
+		attributes := map[string]string{}
+		for _, a := range pp.product.Get("attributes").Array() {
+			attributes[a.Get("key").String()] = a.Get("value").String()
+		}
+
+		for _, price := range pp.prices {
+			p, _ := parsePrice(ctx, currency, price)
+
+			candidates = append(candidates, &schema.PriceCandidate{
+				Sku:        pp.product.Get("sku").String(),
+				Attributes: attributes,
+				Price:      p,
+				Unit:       price.Get("unit").String(),
+			})
+		}
+	}
+
+	return candidates
+}
+
+// pricesEffectiveAt returns the prices that were effective on the given date.
+// Prices with the same price hash and start usage amount are versions of the
+// same price, so only the version with the latest effectiveDateStart on or
+// before the date is kept. Prices without an effectiveDateStart are treated as
+// always effective.
+func pricesEffectiveAt(prices []gjson.Result, date time.Time) []gjson.Result {
+	end := date.AddDate(0, 0, 1)
+
+	type version struct {
+		index int
+		start time.Time
+	}
+	latest := map[string]version{}
+	keys := make([]string, 0, len(prices))
+
+	for i, price := range prices {
+		var start time.Time
+		if s := price.Get("effectiveDateStart").String(); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				log.Debugf("Ignoring price %s with invalid effectiveDateStart %s", price.Get("priceHash").String(), s)
+				continue
+			}
+			start = t
+		}
+
+		if !start.Before(end) {
+			continue
+		}
+
+		key := price.Get("priceHash").String() + "/" + price.Get("startUsageAmount").String()
+		v, ok := latest[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if !ok || start.After(v.start) {
+			latest[key] = version{index: i, start: start}
+		}
+	}
+
+	effective := make([]gjson.Result, 0, len(keys))
+	for _, key := range keys {
+		effective = append(effective, prices[latest[key].index])
+	}
+
+	return effective
+}
+
+// earliestEffectiveDate returns the earliest date that any of the prices of
+// the products are effective from. It returns false if none of the prices
+// have a valid effectiveDateStart.
+func earliestEffectiveDate(products []gjson.Result) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+
+	for _, product := range products {
+		for _, price := range product.Get("prices").Array() {
+			t, err := time.Parse(time.RFC3339, price.Get("effectiveDateStart").String())
+			if err != nil {
+				continue
+			}
+
+			if !found || t.Before(earliest) {
+				earliest = t
+				found = true
+			}
+		}
+	}
+
+	return earliest, found
+}
+
+// PriceDateError is returned when cost components have prices but none of
+// them were effective on the price date, e.g. because the date is before the
+// earliest prices the pricing source has. Using 0.00 for these would make the
+// costs at the price date look lower than they were.
+type PriceDateError struct {
+	Date   time.Time
+	Issues []*PriceDateIssue
+}
+
+// PriceDateIssue is a cost component that has no prices effective on the
+// price date.
+type PriceDateIssue struct {
+	Resource      string
+	CostComponent string
+	// EarliestDate is the earliest date that any price of the cost component
+	// is effective from.
+	EarliestDate time.Time
+}
+
+func (e *PriceDateError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "No prices effective on %s found for %d cost %s:\n", e.Date.Format(config.PriceDateFormat), len(e.Issues), pluralize(len(e.Issues), "component", "components"))
+
+	for _, issue := range e.Issues {
+		fmt.Fprintf(&b, "\n  %s → %s\n    the earliest price is effective from %s\n", issue.Resource, issue.CostComponent, issue.EarliestDate.Format(config.PriceDateFormat))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// priceDateError returns a PriceDateError for the issues, or nil if there
+// are none.
+func priceDateError(ctx *config.RunContext, issues []*PriceDateIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	date, _ := ctx.Config.EffectivePriceDate()
+
+	return &PriceDateError{Date: date, Issues: issues}
 }
 
 func setResourceWarningEvent(ctx *config.RunContext, r *schema.Resource, msg string) {
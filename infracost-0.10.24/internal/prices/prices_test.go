@@ -0,0 +1,289 @@
+package prices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/currency"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newInstanceResource(name, instanceType string) *schema.Resource {
+	return &schema.Resource{
+		Name: name,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name: instanceType,
+				ProductFilter: &schema.ProductFilter{
+					VendorName: strPtr("aws"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "instanceType", Value: strPtr(instanceType)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetPricesConcurrentDeduplicates(t *testing.T) {
+	source := &stubSource{name: "pricing_api", prices: map[string]string{"t3.medium": "0.0416", "t3.large": "0.0832"}}
+
+	resources := make([]*schema.Resource, 0)
+	for i := 0; i < 2000; i++ {
+		resources = append(resources, newInstanceResource(fmt.Sprintf("aws_instance.medium[%d]", i), "t3.medium"))
+	}
+	resources = append(resources, newInstanceResource("aws_instance.large", "t3.large"))
+
+	custom := newInstanceResource("aws_instance.custom", "t3.medium")
+	custom.CostComponents[0].SetCustomPrice(decimalPtr(decimal.NewFromInt(1)))
+	resources = append(resources, custom)
+
+	err := GetPricesConcurrent(config.EmptyRunContext(), source, resources)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, source.calls)
+	assert.ElementsMatch(t, []string{"t3.medium", "t3.large"}, source.queried)
+
+	for _, r := range resources[:2000] {
+		assert.Equal(t, "0.0416", r.CostComponents[0].Price().String())
+		assert.Equal(t, "pricing_api", r.CostComponents[0].PriceSource())
+	}
+
+	assert.Equal(t, "0.0832", resources[2000].CostComponents[0].Price().String())
+	assert.Equal(t, "1", custom.CostComponents[0].Price().String())
+}
+
+func TestBatchPriceQueries(t *testing.T) {
+	resources := make([]*schema.Resource, 0)
+	for i := 0; i < 1200; i++ {
+		resources = append(resources, newInstanceResource(fmt.Sprintf("aws_instance.r[%d]", i), fmt.Sprintf("type-%d", i)))
+	}
+	resources = append(resources, newInstanceResource("aws_instance.huge", strings.Repeat("x", maxBatchBytes)))
+
+	queries, _ := collectPriceQueries(resources)
+	require.Len(t, queries, 1201)
+
+	batches := batchPriceQueries(queries)
+	require.Greater(t, len(batches), 2)
+
+	total := 0
+	for _, batch := range batches {
+		size := 0
+		for _, q := range batch {
+			size += q.size
+		}
+
+		assert.LessOrEqual(t, len(batch), maxBatchQueries)
+		if len(batch) > 1 {
+			assert.LessOrEqual(t, size, maxBatchBytes)
+		}
+
+		total += len(batch)
+	}
+
+	assert.Equal(t, 1201, total)
+	assert.Len(t, batches[len(batches)-1], 1, "a query over the byte limit should be batched on its own")
+}
+
+// failingSource returns an error for the first call and blocks any other
+// calls until their context is cancelled.
+type failingSource struct {
+	mu        sync.Mutex
+	calls     int
+	cancelled int
+}
+
+func (s *failingSource) Name() string { return "failing" }
+
+func (s *failingSource) QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	s.mu.Lock()
+	s.calls++
+	first := s.calls == 1
+	s.mu.Unlock()
+
+	if first {
+		return nil, errors.New("connection refused")
+	}
+
+	select {
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.cancelled++
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Second):
+		return nil, errors.New("not cancelled")
+	}
+}
+
+func TestGetPricesConcurrentCancelsOnError(t *testing.T) {
+	resources := make([]*schema.Resource, 0)
+	for i := 0; i < 2000; i++ {
+		resources = append(resources, newInstanceResource(fmt.Sprintf("aws_instance.r[%d]", i), fmt.Sprintf("type-%d", i)))
+	}
+
+	source := &failingSource{}
+	err := GetPricesConcurrent(config.EmptyRunContext(), source, resources)
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, source.calls-1, source.cancelled)
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func TestPricesEffectiveAt(t *testing.T) {
+	prices := gjson.Parse(`[
+		{"priceHash": "a", "startUsageAmount": "0", "USD": "0.10", "effectiveDateStart": "2023-01-01T00:00:00Z"},
+		{"priceHash": "a", "startUsageAmount": "0", "USD": "0.12", "effectiveDateStart": "2024-01-01T00:00:00Z"},
+		{"priceHash": "a", "startUsageAmount": "100", "USD": "0.08", "effectiveDateStart": "2023-01-01T00:00:00Z"},
+		{"priceHash": "b", "startUsageAmount": "0", "USD": "0.50", "effectiveDateStart": "2024-06-01T00:00:00Z"},
+		{"priceHash": "c", "startUsageAmount": "0", "USD": "1.00"}
+	]`).Array()
+
+	usd := func(prices []gjson.Result) []string {
+		values := make([]string, 0, len(prices))
+		for _, p := range prices {
+			values = append(values, p.Get("USD").String())
+		}
+		return values
+	}
+
+	tests := []struct {
+		date     string
+		expected []string
+	}{
+		{"2022-12-31", []string{"1.00"}},
+		{"2023-06-01", []string{"0.10", "0.08", "1.00"}},
+		{"2024-01-01", []string{"0.12", "0.08", "1.00"}},
+		{"2024-06-01", []string{"0.12", "0.08", "0.50", "1.00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := config.ParsePriceDate(tt.date)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, usd(pricesEffectiveAt(prices, date)))
+		})
+	}
+}
+
+func TestGetPricesAtPriceDate(t *testing.T) {
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Name: "aws_instance.web",
+			CostComponents: []*schema.CostComponent{
+				{
+					Name: "Instance usage (Linux/UNIX, on-demand, t3.medium)",
+					ProductFilter: &schema.ProductFilter{
+						VendorName: strPtr("aws"),
+						Service:    strPtr("AmazonEC2"),
+						AttributeFilters: []*schema.AttributeFilter{
+							{Key: "instanceType", Value: strPtr("t3.medium")},
+						},
+					},
+					PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		priceDate string
+		expected  string
+	}{
+		{"", "0.0416"},
+		{"2023-12-01", "0.0416"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.priceDate, func(t *testing.T) {
+			runCtx := newLocalTestRunContext("testdata/pricing_data")
+			runCtx.Config.PriceDate = tt.priceDate
+
+			c, err := NewLocalPricingClient(runCtx)
+			require.NoError(t, err)
+
+			r := newResource()
+			err = GetPrices(runCtx, c, r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r.CostComponents[0].Price().String())
+		})
+	}
+
+	// Dates before the earliest price return an error rather than using 0.00
+	t.Run("2023-11-30", func(t *testing.T) {
+		runCtx := newLocalTestRunContext("testdata/pricing_data")
+		runCtx.Config.PriceDate = "2023-11-30"
+
+		c, err := NewLocalPricingClient(runCtx)
+		require.NoError(t, err)
+
+		r := newResource()
+		err = GetPricesConcurrent(runCtx, c, []*schema.Resource{r})
+
+		var priceDateErr *PriceDateError
+		require.ErrorAs(t, err, &priceDateErr)
+		assert.Equal(t, `No prices effective on 2023-11-30 found for 1 cost component:
+
+  aws_instance.web → Instance usage (Linux/UNIX, on-demand, t3.medium)
+    the earliest price is effective from 2023-12-01`, err.Error())
+		assert.Equal(t, "0", r.CostComponents[0].Price().String())
+	})
+}
+
+func TestGetPricesWithExchangeRates(t *testing.T) {
+	rates, err := currency.LoadExchangeRatesFromString("version: 0.1\nrates:\n  EUR: 0.5\n")
+	require.NoError(t, err)
+
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.Currency = "EUR"
+	runCtx.Config.ExchangeRates = rates
+	assert.Equal(t, "USD", runCtx.Config.PricingCurrency())
+
+	source := &stubSource{name: "pricing_api", prices: map[string]string{"t3.medium": "0.0416"}}
+
+	r := newInstanceResource("aws_instance.web", "t3.medium")
+	err = GetPrices(runCtx, source, r)
+	require.NoError(t, err)
+	assert.Equal(t, "0.0208", r.CostComponents[0].Price().String())
+}
+
+func TestGetPricesWithMaxPrice(t *testing.T) {
+	source := &stubSource{name: "pricing_api", prices: map[string]string{"t3.medium": "0.0416", "t3.large": "0.0832"}}
+
+	r := newInstanceResource("aws_instance.web", "t3.medium")
+	large := newInstanceResource("aws_instance.web", "t3.large").CostComponents[0]
+	r.CostComponents = append(r.CostComponents, large)
+	r.CostComponents[0].MaxPrice = decimalPtr(decimal.RequireFromString("0.05"))
+	large.MaxPrice = decimalPtr(decimal.RequireFromString("0.05"))
+
+	err := GetPrices(config.EmptyRunContext(), source, r)
+	require.NoError(t, err)
+	assert.Equal(t, "0.0416", r.CostComponents[0].Price().String())
+	assert.Equal(t, "0.05", large.Price().String())
+
+	rates, err := currency.LoadExchangeRatesFromString("version: 0.1\nrates:\n  EUR: 0.5\n")
+	require.NoError(t, err)
+
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.Currency = "EUR"
+	runCtx.Config.ExchangeRates = rates
+
+	err = GetPrices(runCtx, source, r)
+	require.NoError(t, err)
+	assert.Equal(t, "0.0208", r.CostComponents[0].Price().String())
+	assert.Equal(t, "0.025", large.Price().String())
+}
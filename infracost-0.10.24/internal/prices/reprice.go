@@ -0,0 +1,63 @@
+package prices
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Reprice looks up the prices of the cost components of the resources again
+// and applies the rules from the discounts file if one is set, then
+// recalculates the costs of the resources. The cost components must have the
+// price hashes of their previous prices set. If a price hash is no longer
+// returned by the lookup the cost component is marked as having no price
+// found and its price is set to 0.00, since another price returned by the
+// lookup may be for something else.
+func Reprice(ctx *config.RunContext, resources []*schema.Resource) error {
+	priceHashes := make(map[*schema.CostComponent]string)
+	for _, k := range priceQueryKeys(resources) {
+		priceHashes[k.CostComponent] = k.CostComponent.PriceHash()
+	}
+
+	c, err := NewPricingSource(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = GetPricesConcurrent(ctx, c, resources)
+	if err != nil {
+		return err
+	}
+
+	for component, priceHash := range priceHashes {
+		if component.PriceNotFound() == "" && component.PriceHash() != priceHash {
+			component.SetPrice(decimal.Zero)
+			component.SetPriceHash(priceHash)
+			component.SetPriceNotFound("Price hash not found")
+		}
+	}
+
+	err = applyDiscountsFile(ctx, resources)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range resources {
+		r.CalculateCosts()
+	}
+
+	return nil
+}
+
+// priceQueryKeys returns the keys for all the cost components of the resources
+// and their sub-resources.
+func priceQueryKeys(resources []*schema.Resource) []apiclient.PriceQueryKey {
+	keys := make([]apiclient.PriceQueryKey, 0)
+	for _, r := range resources {
+		keys = append(keys, apiclient.PriceQueryKeys(r)...)
+	}
+
+	return keys
+}
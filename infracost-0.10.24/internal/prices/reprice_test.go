@@ -0,0 +1,82 @@
+package prices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newRepriceTestComponent(name, priceHash string) *schema.CostComponent {
+	c := &schema.CostComponent{
+		Name:           name,
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Service:       strPtr("AmazonEC2"),
+			ProductFamily: strPtr("Compute Instance"),
+			Region:        strPtr("us-east-1"),
+			Sku:           strPtr("DQ578CGN99KG6ECF"),
+		},
+	}
+	c.SetPriceHash(priceHash)
+
+	return c
+}
+
+func TestReprice(t *testing.T) {
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+
+	onDemand := newRepriceTestComponent("Instance usage (Linux/UNIX, on-demand, t3.medium)", "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-d2c98780d7b6e36641b521f1f8145c6f")
+	reserved := newRepriceTestComponent("Instance usage (Linux/UNIX, reserved, t3.medium)", "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-8a7d2e6b7d9f1c0e3b4a5c6d7e8f9a0b")
+	removed := newRepriceTestComponent("Instance usage (Linux/UNIX, spot, t3.medium)", "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-removed")
+
+	r := &schema.Resource{
+		Name:           "aws_instance.web",
+		ResourceType:   "aws_instance",
+		CostComponents: []*schema.CostComponent{onDemand, reserved, removed},
+	}
+
+	err := Reprice(runCtx, []*schema.Resource{r})
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0416", onDemand.Price().String())
+	assert.Equal(t, "0.0416", onDemand.HourlyCost.String())
+	assert.Equal(t, "0.026", reserved.Price().String())
+	assert.Equal(t, "Price hash not found", removed.PriceNotFound())
+	assert.Equal(t, "0.0676", r.HourlyCost.String())
+}
+
+func TestRepriceDiscounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "discounts.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+discounts:
+  - name: EDP
+    match:
+      resource_type: aws_instance
+    percent: 10
+`), 0600)
+	require.NoError(t, err)
+
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+	runCtx.Config.DiscountsFile = path
+
+	c := newRepriceTestComponent("Instance usage (Linux/UNIX, on-demand, t3.medium)", "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-d2c98780d7b6e36641b521f1f8145c6f")
+	r := &schema.Resource{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{c}}
+
+	err = Reprice(runCtx, []*schema.Resource{r})
+	require.NoError(t, err)
+
+	require.NotNil(t, c.Discount())
+	assert.Equal(t, "EDP", c.Discount().Name)
+	assert.Equal(t, "0.0416", c.Discount().ListPrice.String())
+	assert.Equal(t, "0.03744", c.Price().String())
+	assert.Equal(t, "0.03744", c.HourlyCost.String())
+}
@@ -0,0 +1,162 @@
+package prices
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+)
+
+// PricingSource is a backend that the prices of cost components can be looked
+// up from, e.g. the Pricing API or local pricing data.
+type PricingSource interface {
+	// Name identifies the source, this is recorded against every price the
+	// source returns.
+	Name() string
+	// QueryPrices looks up the prices for the cost components of the given
+	// keys. A result must be returned for every key, in the same order. It
+	// should return early with the context's error if ctx is cancelled.
+	QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error)
+}
+
+// SourceChain is a PricingSource that looks up prices from a list of sources
+// in order. Any cost components whose prices aren't found in a source are
+// looked up in the next one, so a chain can mix, for example, negotiated rates
+// from a local file with list prices from the Pricing API.
+type SourceChain []PricingSource
+
+// NewPricingSource returns the PricingSource configured for the run. This is
+// a SourceChain if pricing sources are set in the config file. Otherwise, it
+// is the local pricing data if set, falling back to the Pricing API. Pricing
+// API sources are wrapped in a CachedPricingSource unless the price cache is
+// disabled.
+func NewPricingSource(ctx *config.RunContext) (PricingSource, error) {
+	if len(ctx.Config.PricingSources) == 0 {
+		if ctx.Config.UsesLocalPricingData() {
+			return NewLocalPricingClient(ctx)
+		}
+
+		return withPriceCache(ctx, apiclient.NewPricingAPIClient(ctx)), nil
+	}
+
+	chain := make(SourceChain, 0, len(ctx.Config.PricingSources))
+	for _, s := range ctx.Config.PricingSources {
+		name := s.Name
+		if name == "" {
+			name = s.Type
+		}
+
+		switch s.Type {
+		case config.PricingSourceTypeAPI:
+			endpoint := s.Endpoint
+			if endpoint == "" {
+				endpoint = ctx.Config.PricingAPIEndpoint
+			}
+
+			apiKey := s.APIKey
+			if apiKey == "" {
+				apiKey = ctx.Config.APIKey
+			}
+
+			chain = append(chain, withPriceCache(ctx, apiclient.NewNamedPricingAPIClient(ctx, name, endpoint, apiKey)))
+		case config.PricingSourceTypeLocal:
+			local, err := NewNamedLocalPricingClient(ctx, name, s.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			chain = append(chain, local)
+		default:
+			return nil, fmt.Errorf("Invalid pricing source type %s", s.Type)
+		}
+	}
+
+	return chain, nil
+}
+
+func withPriceCache(ctx *config.RunContext, source PricingSource) PricingSource {
+	if ctx.Config.NoPriceCache {
+		return source
+	}
+
+	return NewCachedPricingSource(ctx, source)
+}
+
+// Name returns the names of all the sources in the chain.
+func (s SourceChain) Name() string {
+	names := make([]string, 0, len(s))
+	for _, source := range s {
+		names = append(names, source.Name())
+	}
+
+	return strings.Join(names, ",")
+}
+
+// QueryPrices looks up the prices for the keys from each source in turn until
+// all the prices have been found. If a source returns an error the remaining
+// keys are looked up in the next source, the error is only returned if it was
+// returned by the last source or ctx has been cancelled. Keys whose prices
+// aren't found in any source get the result returned by the last source.
+func (s SourceChain) QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	results := make([]apiclient.PriceQueryResult, len(keys))
+
+	// pending holds the index of the keys that have not been resolved yet.
+	pending := make([]int, len(keys))
+	for i := range keys {
+		pending[i] = i
+	}
+
+	for i, source := range s {
+		if len(pending) == 0 {
+			break
+		}
+
+		isLast := i == len(s)-1
+
+		pendingKeys := make([]apiclient.PriceQueryKey, 0, len(pending))
+		for _, idx := range pending {
+			pendingKeys = append(pendingKeys, keys[idx])
+		}
+
+		sourceResults, err := source.QueryPrices(ctx, pendingKeys)
+		if err != nil {
+			if isLast || ctx.Err() != nil {
+				return nil, err
+			}
+
+			log.Warnf("Error getting prices from pricing source %s, falling back to the next source: %s", source.Name(), err)
+			continue
+		}
+
+		unresolved := make([]int, 0)
+		for j, idx := range pending {
+			res := sourceResults[j]
+			if isLast || hasPrices(res.Result) {
+				results[idx] = res
+				continue
+			}
+
+			unresolved = append(unresolved, idx)
+		}
+
+		pending = unresolved
+	}
+
+	return results, nil
+}
+
+// hasPrices returns true if any of the products in the query result have prices.
+func hasPrices(res gjson.Result) bool {
+	for _, product := range res.Get("data.products").Array() {
+		if len(product.Get("prices").Array()) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
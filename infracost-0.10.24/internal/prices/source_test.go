@@ -0,0 +1,130 @@
+package prices
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// stubSource returns a fixed price for the cost components in prices and no
+// products for any other cost component.
+type stubSource struct {
+	name    string
+	prices  map[string]string
+	err     error
+	queried []string
+	calls   int
+
+	mu sync.Mutex
+}
+
+func (s *stubSource) Name() string { return s.name }
+
+func (s *stubSource) QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	results := make([]apiclient.PriceQueryResult, 0, len(keys))
+	for _, k := range keys {
+		s.queried = append(s.queried, k.CostComponent.Name)
+
+		res := `{"data":{"products":[]}}`
+		if p, ok := s.prices[k.CostComponent.Name]; ok {
+			res = `{"data":{"products":[{"prices":[{"priceHash":"` + s.name + `-hash","USD":"` + p + `"}]}]}}`
+		}
+
+		results = append(results, apiclient.PriceQueryResult{
+			PriceQueryKey: k,
+			Result:        gjson.Parse(res),
+			Source:        s.name,
+		})
+	}
+
+	return results, nil
+}
+
+func TestSourceChain(t *testing.T) {
+	overrides := &stubSource{name: "overrides", prices: map[string]string{"a": "1"}}
+	broken := &stubSource{name: "self_hosted", err: errors.New("connection refused")}
+	public := &stubSource{name: "pricing_api", prices: map[string]string{"a": "3", "b": "2"}}
+
+	a := &schema.CostComponent{Name: "a"}
+	b := &schema.CostComponent{Name: "b"}
+	c := &schema.CostComponent{Name: "c"}
+	r := &schema.Resource{Name: "r", CostComponents: []*schema.CostComponent{a, b, c}}
+
+	chain := SourceChain{overrides, broken, public}
+	assert.Equal(t, "overrides,self_hosted,pricing_api", chain.Name())
+
+	runCtx := config.EmptyRunContext()
+	err := GetPrices(runCtx, chain, r)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c"}, overrides.queried)
+	assert.Equal(t, []string{"b", "c"}, public.queried)
+
+	assert.Equal(t, "1", a.Price().String())
+	assert.Equal(t, "overrides", a.PriceSource())
+	assert.Equal(t, "overrides-hash", a.PriceHash())
+
+	assert.Equal(t, "2", b.Price().String())
+	assert.Equal(t, "pricing_api", b.PriceSource())
+
+	assert.True(t, c.Price().IsZero())
+	assert.Equal(t, "", c.PriceSource())
+}
+
+func TestSourceChainLastSourceError(t *testing.T) {
+	overrides := &stubSource{name: "overrides", prices: map[string]string{"a": "1"}}
+	broken := &stubSource{name: "pricing_api", err: errors.New("connection refused")}
+
+	keys := apiclient.PriceQueryKeys(&schema.Resource{
+		Name:           "r",
+		CostComponents: []*schema.CostComponent{{Name: "a"}, {Name: "b"}},
+	})
+
+	_, err := SourceChain{overrides, broken}.QueryPrices(context.Background(), keys)
+	assert.EqualError(t, err, "connection refused")
+
+	results, err := SourceChain{overrides, broken}.QueryPrices(context.Background(), keys[:1])
+	require.NoError(t, err)
+	assert.Equal(t, "overrides", results[0].Source)
+}
+
+func TestNewPricingSource(t *testing.T) {
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.PricingAPIEndpoint = "https://pricing.api.infracost.io"
+
+	source, err := NewPricingSource(runCtx)
+	require.NoError(t, err)
+	assert.Equal(t, apiclient.DefaultPricingSourceName, source.Name())
+
+	runCtx.Config.PricingDataPath = "testdata/pricing_data"
+	source, err = NewPricingSource(runCtx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLocalPricingSourceName, source.Name())
+
+	runCtx.Config.PricingSources = []*config.PricingSource{
+		{Name: "negotiated", Type: config.PricingSourceTypeLocal, Path: "testdata/pricing_data"},
+		{Name: "self_hosted", Type: config.PricingSourceTypeAPI, Endpoint: "http://localhost:4000"},
+		{Type: config.PricingSourceTypeAPI},
+	}
+	source, err = NewPricingSource(runCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "negotiated,self_hosted,pricing_api", source.Name())
+}
@@ -0,0 +1,111 @@
+package prices
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// StrictPricingError is returned when strict pricing is enabled and the price
+// lookups of some cost components found no prices or more than one price.
+type StrictPricingError struct {
+	Issues []*StrictPricingIssue
+}
+
+// StrictPricingIssue is a cost component whose price lookup found no prices
+// or more than one price.
+type StrictPricingIssue struct {
+	Resource      string
+	CostComponent string
+	// Reason is why no price was found, it is empty if the lookup was
+	// ambiguous.
+	Reason     string
+	Candidates []*schema.PriceCandidate
+}
+
+func (e *StrictPricingError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Strict pricing failed, %d cost %s could not be priced exactly:\n", len(e.Issues), pluralize(len(e.Issues), "component", "components"))
+
+	for _, issue := range e.Issues {
+		fmt.Fprintf(&b, "\n  %s → %s\n", issue.Resource, issue.CostComponent)
+
+		if issue.Reason != "" {
+			fmt.Fprintf(&b, "    %s\n", issue.Reason)
+			continue
+		}
+
+		fmt.Fprintf(&b, "    %d candidate prices found, using the first:\n", len(issue.Candidates))
+		for _, c := range issue.Candidates {
+			fmt.Fprintf(&b, "    - sku %s: %s per %s%s\n", c.Sku, c.Price.String(), c.Unit, formatAttributes(c.Attributes))
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// checkStrictPricing returns a StrictPricingError if any of the cost
+// components of the resources, or their sub-resources, had no prices or more
+// than one price.
+func checkStrictPricing(resources []*schema.Resource) error {
+	issues := make([]*StrictPricingIssue, 0)
+	for _, r := range resources {
+		issues = append(issues, strictPricingIssues(r, r.Name)...)
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return &StrictPricingError{Issues: issues}
+}
+
+func strictPricingIssues(r *schema.Resource, name string) []*StrictPricingIssue {
+	issues := make([]*StrictPricingIssue, 0)
+
+	for _, c := range r.CostComponents {
+		if c.PriceNotFound() != "" {
+			issues = append(issues, &StrictPricingIssue{Resource: name, CostComponent: c.Name, Reason: c.PriceNotFound()})
+		} else if len(c.PriceCandidates()) > 1 {
+			issues = append(issues, &StrictPricingIssue{Resource: name, CostComponent: c.Name, Candidates: c.PriceCandidates()})
+		}
+	}
+
+	for _, s := range r.SubResources {
+		issues = append(issues, strictPricingIssues(s, name+" → "+s.Name)...)
+	}
+
+	return issues
+}
+
+// formatAttributes formats the attributes sorted by key so the report is the
+// same on every run.
+func formatAttributes(attributes map[string]string) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, attributes[k]))
+	}
+
+	return " (" + strings.Join(pairs, ", ") + ")"
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+
+	return plural
+}
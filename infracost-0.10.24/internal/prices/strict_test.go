@@ -0,0 +1,112 @@
+package prices
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newStrictTestProject() (*schema.Project, *schema.CostComponent, *schema.CostComponent) {
+	ambiguous := &schema.CostComponent{
+		Name: "Instance usage (Linux/UNIX, t3.medium)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonEC2"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.medium")},
+			},
+		},
+	}
+
+	missing := &schema.CostComponent{
+		Name: "Instance usage (Linux/UNIX, on-demand, t3.nano)",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonEC2"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.nano")},
+			},
+		},
+	}
+
+	project := &schema.Project{
+		Name: "test",
+		Resources: []*schema.Resource{
+			{
+				Name:           "aws_instance.web",
+				CostComponents: []*schema.CostComponent{ambiguous},
+				SubResources: []*schema.Resource{
+					{Name: "spare", CostComponents: []*schema.CostComponent{missing}},
+				},
+			},
+		},
+	}
+
+	return project, ambiguous, missing
+}
+
+func TestPopulatePricesCandidates(t *testing.T) {
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+
+	project, ambiguous, _ := newStrictTestProject()
+	err := PopulatePrices(runCtx, project)
+	require.NoError(t, err)
+	assert.Empty(t, ambiguous.PriceCandidates())
+
+	runCtx.Config.IncludePriceHashes = true
+
+	project, ambiguous, missing := newStrictTestProject()
+	err = PopulatePrices(runCtx, project)
+	require.NoError(t, err)
+
+	candidates := ambiguous.PriceCandidates()
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "DQ578CGN99KG6ECF", candidates[0].Sku)
+	assert.Equal(t, "t3.medium", candidates[0].Attributes["instanceType"])
+	assert.Equal(t, "Hrs", candidates[0].Unit)
+	assert.Equal(t, ambiguous.Price().String(), candidates[0].Price.String())
+
+	assert.Empty(t, ambiguous.PriceNotFound())
+	assert.Equal(t, "No products found", missing.PriceNotFound())
+}
+
+func TestPopulatePricesStrict(t *testing.T) {
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+	runCtx.Config.StrictPricing = true
+
+	project, _, _ := newStrictTestProject()
+	err := PopulatePrices(runCtx, project)
+
+	var strictErr *StrictPricingError
+	require.True(t, errors.As(err, &strictErr))
+	require.Len(t, strictErr.Issues, 2)
+
+	assert.Equal(t, "aws_instance.web", strictErr.Issues[0].Resource)
+	assert.Len(t, strictErr.Issues[0].Candidates, 2)
+	assert.Equal(t, "aws_instance.web → spare", strictErr.Issues[1].Resource)
+	assert.Equal(t, "No products found", strictErr.Issues[1].Reason)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "Strict pricing failed, 2 cost components could not be priced exactly")
+	assert.Contains(t, msg, "aws_instance.web → Instance usage (Linux/UNIX, t3.medium)")
+	assert.Contains(t, msg, "- sku DQ578CGN99KG6ECF: 0.0416 per Hrs (capacitystatus=Used, instanceType=t3.medium")
+	assert.Contains(t, msg, "aws_instance.web → spare → Instance usage (Linux/UNIX, on-demand, t3.nano)\n    No products found")
+}
+
+func TestPopulatePricesStrictNoIssues(t *testing.T) {
+	runCtx := newLocalTestRunContext("testdata/pricing_data")
+	runCtx.Config.StrictPricing = true
+
+	project := &schema.Project{
+		Name:      "test",
+		Resources: []*schema.Resource{newInstanceResource("aws_instance.web", "t3.medium")},
+	}
+	project.Resources[0].CostComponents[0].PriceFilter = &schema.PriceFilter{PurchaseOption: strPtr("on_demand")}
+
+	err := PopulatePrices(runCtx, project)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,5 @@
+INSERT INTO products ("productHash",sku,"vendorName",region,service,"productFamily","attributes",prices) VALUES
+	 ('0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1','DQ578CGN99KG6ECF','aws','us-east-1','AmazonEC2','Compute Instance','{"tenancy": "Shared", "usagetype": "BoxUsage:t3.medium", "capacitystatus": "Used", "instanceType": "t3.medium", "operatingSystem": "Linux", "preInstalledSw": "NA"}','{"0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-d2c98780d7b6e36641b521f1f8145c6f": [{"USD": "0.0416000000", "unit": "Hrs", "priceHash": "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-d2c98780d7b6e36641b521f1f8145c6f", "description": "$0.0416 per On Demand Linux t3.medium Instance Hour", "endUsageAmount": "Inf", "purchaseOption": "on_demand", "startUsageAmount": "0", "effectiveDateStart": "2023-12-01T00:00:00Z"}], "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-8a7d2e6b7d9f1c0e3b4a5c6d7e8f9a0b": [{"USD": "0.0260000000", "unit": "Hrs", "priceHash": "0f1e7c0c7a6bd4c7a3e0a6e6e4d7c3b1-8a7d2e6b7d9f1c0e3b4a5c6d7e8f9a0b", "termLength": "1yr", "description": "Linux/UNIX (Amazon VPC), t3.medium reserved instance applied", "endUsageAmount": "Inf", "purchaseOption": "reserved", "startUsageAmount": "0", "termOfferingClass": "standard", "termPurchaseOption": "No Upfront", "effectiveDateStart": "2023-12-01T00:00:00Z"}]}'),
+	 ('6d7c5e0a4a4d2e6f8c2b1a0e9d8c7b6a','TG3M4CAGBA3NYQBH','aws','us-east-1','AWSLambda','Serverless','{"group": "AWS-Lambda-Duration", "usagetype": "Lambda-GB-Second", "regionCode": "us-east-1"}','{"6d7c5e0a4a4d2e6f8c2b1a0e9d8c7b6a-a62d05a1a3a4ea9b8f0b5b5b2b1d7a4c": [{"USD": "0.0000166667", "unit": "Lambda-GB-Second", "priceHash": "6d7c5e0a4a4d2e6f8c2b1a0e9d8c7b6a-a62d05a1a3a4ea9b8f0b5b5b2b1d7a4c", "description": "AWS Lambda - Total Compute - US East (N. Virginia)", "endUsageAmount": "6000000000", "purchaseOption": "on_demand", "startUsageAmount": "0", "effectiveDateStart": "2023-06-01T00:00:00Z"}, {"USD": "0.0000150000", "unit": "Lambda-GB-Second", "priceHash": "6d7c5e0a4a4d2e6f8c2b1a0e9d8c7b6a-a62d05a1a3a4ea9b8f0b5b5b2b1d7a4c", "description": "AWS Lambda - Total Compute - US East (N. Virginia)", "endUsageAmount": "15000000000", "purchaseOption": "on_demand", "startUsageAmount": "6000000000", "effectiveDateStart": "2023-06-01T00:00:00Z"}]}');
+INSERT INTO products ("productHash",sku,"vendorName",region,service,"productFamily","attributes",prices) VALUES
+	 ('f258b838070180ba88dd67002b13c99a','ZQVCC2M69R4S79XS','aws',NULL,'AWSLambda','Serverless','{"group": "AWS-Lambda-Requests", "usagetype": "Global-Request", "regionCode": ""}','{"f258b838070180ba88dd67002b13c99a-4a9dfd3965ffcbab75845ead7a27fd47": [{"USD": "0.0000000000", "unit": "Requests", "priceHash": "f258b838070180ba88dd67002b13c99a-4a9dfd3965ffcbab75845ead7a27fd47", "description": "AWS Lambda - Requests Free Tier - 1,000,000 Requests, it''s free", "endUsageAmount": "1000000", "purchaseOption": "on_demand", "startUsageAmount": "0", "effectiveDateStart": "2022-10-01T00:00:00Z"}]}');
@@ -0,0 +1,244 @@
+// Package projection projects the monthly costs of projects over several
+// months, applying the usage growth and resource start dates assumed in the
+// projection section of the usage file.
+package projection
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+// ProjectCosts sets the projected monthly cost of the project for each of the
+// months, starting from the start date of the assumptions or the month of
+// now.
+//
+// Resources without assumptions cost the same every month. Resources with
+// usage growth are rebuilt with their grown usage for each month and
+// repriced from the source in the same way as the project, including the
+// discounts file and strict pricing. The difference from their cost with the
+// usage in the usage file is added to their monthly cost. Resources with a
+// start date have no cost for the months before it.
+func ProjectCosts(ctx *config.RunContext, source prices.PricingSource, project *schema.Project, assumptions *usage.Projection, months int, now time.Time) error {
+	start := firstOfMonth(now)
+	if assumptions != nil && assumptions.StartDate != nil {
+		start = firstOfMonth(*assumptions.StartDate)
+	}
+
+	partials := make(map[string]*schema.PartialResource, len(project.PartialResources))
+	for _, p := range project.PartialResources {
+		partials[p.ResourceData.Address] = p
+	}
+
+	// grown holds the resources rebuilt with their usage grown for a number of
+	// months, by address and then number of months.
+	grown := make(map[string]map[int]*schema.Resource)
+	var toPrice []*schema.Resource
+
+	for _, r := range project.Resources {
+		rp := assumptions.Resource(r.Name)
+		if r.IsSkipped || rp == nil || len(rp.MonthlyGrowth) == 0 {
+			continue
+		}
+
+		partial := partials[r.Name]
+		if partial == nil || partial.CoreResource == nil {
+			log.Warnf("Usage growth can't be projected for %s, its current monthly cost is used for every month", r.Name)
+			continue
+		}
+
+		growth := validGrowth(partial, rp.MonthlyGrowth)
+		if len(growth) == 0 {
+			continue
+		}
+
+		built := make(map[int]*schema.Resource)
+		for i := 0; i < months; i++ {
+			n := growthMonths(start, start.AddDate(0, i, 0), rp)
+			if _, ok := built[n]; ok {
+				continue
+			}
+
+			built[n] = buildGrownResource(partial, growth, n)
+			toPrice = append(toPrice, built[n])
+		}
+
+		if _, ok := built[0]; !ok {
+			built[0] = buildGrownResource(partial, growth, 0)
+			toPrice = append(toPrice, built[0])
+		}
+
+		grown[r.Name] = built
+	}
+
+	if len(toPrice) > 0 {
+		if err := prices.PopulateResourcePrices(ctx, source, toPrice); err != nil {
+			return err
+		}
+
+		for _, r := range toPrice {
+			r.CalculateCosts()
+		}
+	}
+
+	projection := make([]*schema.ProjectedCost, 0, months)
+	for i := 0; i < months; i++ {
+		month := start.AddDate(0, i, 0)
+		total := decimal.Zero
+
+		for _, r := range project.Resources {
+			if r.IsSkipped {
+				continue
+			}
+
+			rp := assumptions.Resource(r.Name)
+			if rp != nil && rp.StartDate != nil && month.Before(firstOfMonth(*rp.StartDate)) {
+				continue
+			}
+
+			cost := monthlyCost(r)
+			if built, ok := grown[r.Name]; ok {
+				cost = cost.Add(monthlyCost(built[growthMonths(start, month, rp)]).Sub(monthlyCost(built[0])))
+			}
+
+			total = total.Add(cost)
+		}
+
+		projection = append(projection, &schema.ProjectedCost{Month: month, MonthlyCost: total})
+	}
+
+	project.Projection = projection
+
+	return nil
+}
+
+// growthMonths returns the number of months the usage of the resource has
+// grown for by the month. Usage grows from the start of the projection, or
+// from the start date of the resource if it's later.
+func growthMonths(start, month time.Time, rp *usage.ResourceProjection) int {
+	from := start
+	if rp != nil && rp.StartDate != nil && rp.StartDate.After(start) {
+		from = firstOfMonth(*rp.StartDate)
+	}
+
+	n := (month.Year()-from.Year())*12 + int(month.Month()-from.Month())
+	if n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthlyCost(r *schema.Resource) decimal.Decimal {
+	if r == nil || r.MonthlyCost == nil {
+		return decimal.Zero
+	}
+
+	return *r.MonthlyCost
+}
+
+// validGrowth returns the growth rates of the usage keys that have a numeric
+// value in the usage file. Keys of nested usage are separated by dots, e.g.
+// standard.storage_gb.
+func validGrowth(partial *schema.PartialResource, growth map[string]decimal.Decimal) map[string]decimal.Decimal {
+	valid := make(map[string]decimal.Decimal, len(growth))
+
+	for key, rate := range growth {
+		if usageValue(partial.ResourceData.UsageData, key).Type != gjson.Number {
+			log.Warnf("Usage growth can't be projected for %s %s as it isn't set to a number in the usage file", partial.ResourceData.Address, key)
+			continue
+		}
+
+		valid[key] = rate
+	}
+
+	return valid
+}
+
+func usageValue(u *schema.UsageData, key string) gjson.Result {
+	if u == nil {
+		return gjson.Result{}
+	}
+
+	attr, path := splitUsageKey(key)
+	v := u.Attributes[attr]
+	if path != "" {
+		v = v.Get(path)
+	}
+
+	return v
+}
+
+func splitUsageKey(key string) (attr, path string) {
+	if i := strings.Index(key, "."); i > 0 {
+		return key[:i], key[i+1:]
+	}
+
+	return key, ""
+}
+
+// buildGrownResource builds the resource with its usage grown by the rates
+// compounded over n months.
+func buildGrownResource(partial *schema.PartialResource, growth map[string]decimal.Decimal, n int) *schema.Resource {
+	original := partial.ResourceData.UsageData
+	u := original.Copy()
+
+	for key, rate := range growth {
+		factor := decimal.NewFromInt(1).Add(rate).Pow(decimal.NewFromInt(int64(n)))
+		value := decimal.NewFromFloat(usageValue(u, key).Float()).Mul(factor)
+
+		attr, path := splitUsageKey(key)
+		if path == "" {
+			u.Attributes[attr] = gjson.Parse(value.String())
+			continue
+		}
+
+		u.Attributes[attr] = setNestedUsage(u.Attributes[attr], strings.Split(path, "."), value)
+	}
+
+	// The usage is read from the resource data when the resource is built,
+	// so it's swapped for the grown usage and then restored.
+	partial.ResourceData.UsageData = u
+	r := schema.BuildResource(partial, nil)
+	partial.ResourceData.UsageData = original
+	partial.CoreResource.PopulateUsage(original)
+
+	return r
+}
+
+func setNestedUsage(v gjson.Result, path []string, value decimal.Decimal) gjson.Result {
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(v.Raw), &m); err != nil {
+		return v
+	}
+
+	current := m
+	for _, p := range path[:len(path)-1] {
+		next, ok := current[p].(map[string]interface{})
+		if !ok {
+			return v
+		}
+		current = next
+	}
+	current[path[len(path)-1]] = json.Number(value.String())
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return v
+	}
+
+	return gjson.ParseBytes(b)
+}
@@ -0,0 +1,203 @@
+package projection
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+// stubSource prices every cost component at $1.
+type stubSource struct{}
+
+func (s stubSource) Name() string { return "stub" }
+
+func (s stubSource) QueryPrices(ctx context.Context, keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	results := make([]apiclient.PriceQueryResult, 0, len(keys))
+	for _, k := range keys {
+		results = append(results, apiclient.PriceQueryResult{
+			PriceQueryKey: k,
+			Result:        gjson.Parse(`{"data":{"products":[{"prices":[{"priceHash":"stub-hash","USD":"1"}]}]}}`),
+			Source:        "stub",
+		})
+	}
+
+	return results, nil
+}
+
+// stubBucket costs $1 per GB of standard storage.
+type stubBucket struct {
+	address   string
+	storageGB float64
+}
+
+func (b *stubBucket) CoreType() string                 { return "stubBucket" }
+func (b *stubBucket) UsageSchema() []*schema.UsageItem { return nil }
+
+func (b *stubBucket) PopulateUsage(u *schema.UsageData) {
+	b.storageGB = u.Get("standard").Get("storage_gb").Float()
+}
+
+func (b *stubBucket) BuildResource() *schema.Resource {
+	vendor := "stub"
+	return &schema.Resource{
+		Name: b.address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Storage",
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: decimalPtr(decimal.NewFromFloat(b.storageGB)),
+				ProductFilter:   &schema.ProductFilter{VendorName: &vendor},
+			},
+		},
+	}
+}
+
+func newProject(t *testing.T, storageGB map[string]string) *schema.Project {
+	t.Helper()
+
+	project := schema.NewProject("test", &schema.ProjectMetadata{})
+	for address, gb := range storageGB {
+		project.PartialResources = append(project.PartialResources, &schema.PartialResource{
+			ResourceData: &schema.ResourceData{
+				Address: address,
+				UsageData: schema.NewUsageData(address, map[string]gjson.Result{
+					"standard": gjson.Parse(`{"storage_gb": ` + gb + `}`),
+				}),
+			},
+			CoreResource: &stubBucket{address: address},
+		})
+	}
+
+	project.BuildResources(schema.UsageMap{})
+	require.NoError(t, prices.GetPricesConcurrent(config.EmptyRunContext(), stubSource{}, project.Resources))
+	schema.CalculateCosts(project)
+
+	return project
+}
+
+func date(s string) *time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return &t
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func projectedCosts(project *schema.Project) []string {
+	costs := make([]string, 0, len(project.Projection))
+	for _, p := range project.Projection {
+		costs = append(costs, p.Month.Format("2006-01")+" "+p.MonthlyCost.StringFixed(2))
+	}
+
+	return costs
+}
+
+func TestProjectCostsWithoutAssumptions(t *testing.T) {
+	project := newProject(t, map[string]string{"bucket.a": "100"})
+
+	err := ProjectCosts(config.EmptyRunContext(), stubSource{}, project, nil, 3, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2024-11 100.00", "2024-12 100.00", "2025-01 100.00"}, projectedCosts(project))
+}
+
+func TestProjectCostsWithGrowthAndStartDates(t *testing.T) {
+	project := newProject(t, map[string]string{"bucket.a": "100", "bucket.b[0]": "10", "bucket.c": "50"})
+
+	assumptions := &usage.Projection{
+		StartDate: date("2024-01-01"),
+		Resources: map[string]*usage.ResourceProjection{
+			"bucket.a": {
+				MonthlyGrowth: map[string]decimal.Decimal{"standard.storage_gb": decimal.NewFromFloat(0.1)},
+			},
+			"bucket.b[*]": {
+				StartDate:     date("2024-03-15"),
+				MonthlyGrowth: map[string]decimal.Decimal{"standard.storage_gb": decimal.NewFromInt(1)},
+			},
+		},
+	}
+
+	err := ProjectCosts(config.EmptyRunContext(), stubSource{}, project, assumptions, 4, time.Now())
+	require.NoError(t, err)
+
+	// bucket.a grows 10% a month from 100, bucket.b[0] starts in March and
+	// doubles every month after that, bucket.c stays at 50.
+	assert.Equal(t, []string{
+		"2024-01 150.00",
+		"2024-02 160.00",
+		"2024-03 181.00",
+		"2024-04 203.10",
+	}, projectedCosts(project))
+
+	// The resources of the project keep the cost from the usage file.
+	for _, r := range project.Resources {
+		if r.Name == "bucket.a" {
+			assert.Equal(t, "100", r.MonthlyCost.String())
+		}
+	}
+}
+
+func TestProjectCostsDiscounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "discounts.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+discounts:
+  - name: Storage
+    match:
+      vendor_name: stub
+    percent: 50
+`), 0600)
+	require.NoError(t, err)
+
+	runCtx := config.EmptyRunContext()
+	runCtx.Config.DiscountsFile = path
+
+	project := newProject(t, map[string]string{"bucket.a": "100"})
+	require.NoError(t, prices.PopulatePricesFrom(runCtx, stubSource{}, project))
+	schema.CalculateCosts(project)
+
+	assumptions := &usage.Projection{
+		Resources: map[string]*usage.ResourceProjection{
+			"bucket.a": {
+				MonthlyGrowth: map[string]decimal.Decimal{"standard.storage_gb": decimal.NewFromFloat(0.1)},
+			},
+		},
+	}
+
+	err = ProjectCosts(runCtx, stubSource{}, project, assumptions, 3, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	// The grown usage gets the same discount as the usage in the usage file.
+	assert.Equal(t, []string{"2024-01 50.00", "2024-02 55.00", "2024-03 60.50"}, projectedCosts(project))
+}
+
+func TestProjectCostsInvalidUsageKey(t *testing.T) {
+	project := newProject(t, map[string]string{"bucket.a": "100"})
+
+	assumptions := &usage.Projection{
+		Resources: map[string]*usage.ResourceProjection{
+			"bucket.a": {
+				MonthlyGrowth: map[string]decimal.Decimal{"standard.missing_gb": decimal.NewFromFloat(0.1)},
+			},
+		},
+	}
+
+	err := ProjectCosts(config.EmptyRunContext(), stubSource{}, project, assumptions, 2, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2024-01 100.00", "2024-02 100.00"}, projectedCosts(project))
+}
@@ -0,0 +1,207 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/autoscaling"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetAutoscalingGroupRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::AutoScaling::AutoScalingGroup",
+		Notes: []string{
+			"Mixed instances policies are not yet supported.",
+			"If a root volume is not specified in the launch configuration then an 8Gi gp2 volume is assumed.",
+		},
+		RFunc: NewAutoscalingGroup,
+		ReferenceAttributes: []string{
+			"LaunchConfigurationName",
+			"LaunchTemplate.LaunchTemplateId",
+			"LaunchTemplate.LaunchTemplateName",
+		},
+	}
+}
+
+func NewAutoscalingGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*autoscaling.AutoScalingGroup)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	a := &aws.AutoscalingGroup{
+		Address: d.Address,
+		Region:  region,
+		Name:    cfr.AutoScalingGroupName,
+	}
+
+	instanceCount := parseInstanceCount(cfr.DesiredCapacity)
+	if instanceCount == 0 {
+		instanceCount = parseInstanceCount(cfr.MinSize)
+	}
+	if instanceCount == 0 {
+		log.Debugf("Using instance count 1 for %s since no DesiredCapacity or non-zero MinSize is set. To override this set the instances attribute for this resource in the Infracost usage file.", a.Address)
+		instanceCount = 1
+	}
+
+	launchTemplateRefs := d.References("LaunchTemplate.LaunchTemplateId")
+	if len(launchTemplateRefs) == 0 {
+		launchTemplateRefs = d.References("LaunchTemplate.LaunchTemplateName")
+	}
+
+	for _, ref := range d.References("LaunchConfigurationName") {
+		if lc, ok := ref.CFResource.(*autoscaling.LaunchConfiguration); ok {
+			a.LaunchConfiguration = newLaunchConfiguration(ref, lc, region, instanceCount)
+			break
+		}
+	}
+
+	if a.LaunchConfiguration == nil {
+		for _, ref := range launchTemplateRefs {
+			if lt, ok := ref.CFResource.(*ec2.LaunchTemplate); ok {
+				a.LaunchTemplate = newLaunchTemplate(ref, lt, region, instanceCount)
+				break
+			}
+		}
+	}
+
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
+
+// parseInstanceCount returns the number of instances from the DesiredCapacity
+// or MinSize of an auto scaling group, which are strings in CloudFormation.
+func parseInstanceCount(s string) int64 {
+	i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return i
+}
+
+func newLaunchConfiguration(d *schema.ResourceData, cfr *autoscaling.LaunchConfiguration, region string, instanceCount int64) *aws.LaunchConfiguration {
+	purchaseOption := "on_demand"
+	var spotMaxPrice *float64
+	if cfr.SpotPrice != "" {
+		purchaseOption = "spot"
+		if f, err := strconv.ParseFloat(cfr.SpotPrice, 64); err == nil {
+			spotMaxPrice = floatPtr(f)
+		}
+	}
+
+	a := &aws.LaunchConfiguration{
+		Address:        d.Address,
+		Region:         region,
+		AMI:            cfr.ImageId,
+		InstanceCount:  intPtr(instanceCount),
+		Tenancy:        cfr.PlacementTenancy,
+		PurchaseOption: purchaseOption,
+		SpotMaxPrice:   spotMaxPrice,
+		InstanceType:   cfr.InstanceType,
+		EBSOptimized:   cfr.EbsOptimized,
+		// Detailed monitoring is enabled unless InstanceMonitoring is false.
+		EnableMonitoring: d.GetBoolOrDefault("InstanceMonitoring", true),
+		RootBlockDevice: &aws.EBSVolume{
+			Address: "root_block_device",
+			Region:  region,
+		},
+	}
+
+	for _, m := range cfr.BlockDeviceMappings {
+		if m.Ebs == nil || m.NoDevice {
+			continue
+		}
+
+		v := a.RootBlockDevice
+		if !rootDeviceNames[m.DeviceName] {
+			v = &aws.EBSVolume{
+				Address: fmt.Sprintf("ebs_block_device[%d]", len(a.EBSBlockDevices)),
+				Region:  region,
+			}
+			a.EBSBlockDevices = append(a.EBSBlockDevices, v)
+		}
+
+		v.Type = m.Ebs.VolumeType
+		v.IOPS = int64(m.Ebs.Iops)
+		if m.Ebs.VolumeSize > 0 {
+			v.Size = intPtr(int64(m.Ebs.VolumeSize))
+		}
+	}
+
+	return a
+}
+
+func newLaunchTemplate(d *schema.ResourceData, cfr *ec2.LaunchTemplate, region string, instanceCount int64) *aws.LaunchTemplate {
+	a := &aws.LaunchTemplate{
+		Address:                          d.Address,
+		Region:                           region,
+		InstanceCount:                    intPtr(instanceCount),
+		OnDemandPercentageAboveBaseCount: 100,
+	}
+
+	data := cfr.LaunchTemplateData
+	if data == nil {
+		return a
+	}
+
+	a.AMI = data.ImageId
+	a.InstanceType = data.InstanceType
+	a.EBSOptimized = data.EbsOptimized
+
+	if data.Placement != nil {
+		a.Tenancy = data.Placement.Tenancy
+	}
+
+	if data.Monitoring != nil {
+		a.EnableMonitoring = data.Monitoring.Enabled
+	}
+
+	if data.CreditSpecification != nil {
+		a.CPUCredits = data.CreditSpecification.CpuCredits
+	}
+
+	if opts := data.InstanceMarketOptions; opts != nil && strings.EqualFold(opts.MarketType, "spot") {
+		a.OnDemandPercentageAboveBaseCount = 0
+		if opts.SpotOptions != nil && opts.SpotOptions.MaxPrice != "" {
+			if f, err := strconv.ParseFloat(opts.SpotOptions.MaxPrice, 64); err == nil {
+				a.SpotMaxPrice = floatPtr(f)
+			}
+		}
+	}
+
+	if len(data.ElasticInferenceAccelerators) > 0 && data.ElasticInferenceAccelerators[0].Type != "" {
+		t := data.ElasticInferenceAccelerators[0].Type
+		a.ElasticInferenceAcceleratorType = &t
+	}
+
+	for i, m := range data.BlockDeviceMappings {
+		if m.Ebs == nil {
+			continue
+		}
+
+		v := &aws.EBSVolume{
+			Address: fmt.Sprintf("block_device_mapping[%d]", i),
+			Region:  region,
+			Type:    m.Ebs.VolumeType,
+			IOPS:    int64(m.Ebs.Iops),
+		}
+		if m.Ebs.VolumeSize > 0 {
+			v.Size = intPtr(int64(m.Ebs.VolumeSize))
+		}
+
+		a.EBSBlockDevices = append(a.EBSBlockDevices, v)
+	}
+
+	return a
+}
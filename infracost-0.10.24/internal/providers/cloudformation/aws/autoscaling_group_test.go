@@ -0,0 +1,16 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/cloudformation/cftest"
+)
+
+func TestAutoscalingGroupGoldenFile(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	cftest.GoldenFileResourceTests(t, "autoscaling_group_test")
+}
@@ -0,0 +1,16 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/cloudformation/cftest"
+)
+
+func TestCloudwatchLogGroupGoldenFile(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	cftest.GoldenFileResourceTests(t, "cloudwatch_log_group_test")
+}
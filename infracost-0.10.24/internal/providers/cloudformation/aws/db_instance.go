@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetDBInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::RDS::DBInstance",
+		RFunc: NewDBInstance,
+	}
+}
+
+// NewDBInstance prices an AWS::RDS::DBInstance. CloudFormation uses the same
+// resource type for the instances of Aurora clusters, these are priced as
+// cluster instances since their storage is billed by the cluster.
+func NewDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*rds.DBInstance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	piEnabled := cfr.EnablePerformanceInsights
+	piLongTerm := piEnabled && cfr.PerformanceInsightsRetentionPeriod > 7
+
+	if cfr.DBClusterIdentifier != "" {
+		a := &aws.RDSClusterInstance{
+			Address:                              d.Address,
+			Region:                               region,
+			InstanceClass:                        cfr.DBInstanceClass,
+			Engine:                               cfr.Engine,
+			PerformanceInsightsEnabled:           piEnabled,
+			PerformanceInsightsLongTermRetention: piLongTerm,
+		}
+		a.PopulateUsage(u)
+
+		return a.BuildResource()
+	}
+
+	iops := float64(cfr.Iops)
+	storageType := cfr.StorageType
+	if storageType == "" {
+		storageType = "gp2"
+		if iops > 0 {
+			storageType = "io1"
+		}
+	}
+
+	a := &aws.DBInstance{
+		Address:                              d.Address,
+		Region:                               region,
+		InstanceClass:                        cfr.DBInstanceClass,
+		Engine:                               cfr.Engine,
+		MultiAZ:                              cfr.MultiAZ,
+		LicenseModel:                         cfr.LicenseModel,
+		BackupRetentionPeriod:                int64(cfr.BackupRetentionPeriod),
+		IOPS:                                 iops,
+		StorageType:                          storageType,
+		PerformanceInsightsEnabled:           piEnabled,
+		PerformanceInsightsLongTermRetention: piLongTerm,
+	}
+
+	if storage, err := strconv.ParseFloat(cfr.AllocatedStorage, 64); err == nil {
+		a.AllocatedStorageGB = floatPtr(storage)
+	}
+
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
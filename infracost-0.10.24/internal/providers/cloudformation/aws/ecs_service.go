@@ -0,0 +1,133 @@
+package aws
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetECSServiceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:                "AWS::ECS::Service",
+		RFunc:               NewECSService,
+		ReferenceAttributes: []string{"Cluster", "TaskDefinition"},
+	}
+}
+
+func NewECSService(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ecs.Service)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	memoryGB := float64(0)
+	vcpu := float64(0)
+	inferenceAcceleratorDeviceType := ""
+
+	for _, ref := range d.References("TaskDefinition") {
+		if taskDefinition, ok := ref.CFResource.(*ecs.TaskDefinition); ok {
+			memoryGB = parseVCPUMemoryString(taskDefinition.Memory)
+			vcpu = parseVCPUMemoryString(taskDefinition.Cpu)
+			if len(taskDefinition.InferenceAccelerators) > 0 {
+				inferenceAcceleratorDeviceType = taskDefinition.InferenceAccelerators[0].DeviceType
+			}
+			break
+		}
+	}
+
+	a := &aws.ECSService{
+		Address:                        d.Address,
+		Region:                         region,
+		LaunchType:                     calcLaunchType(d, cfr),
+		DesiredCount:                   int64(cfr.DesiredCount),
+		MemoryGB:                       memoryGB,
+		VCPU:                           vcpu,
+		InferenceAcceleratorDeviceType: inferenceAcceleratorDeviceType,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
+
+// calcLaunchType determines the launch type for the service using the following precedence:
+//  1. LaunchType
+//  2. CapacityProviderStrategy
+//  3. the DefaultCapacityProviderStrategy of the cluster
+//  4. the CapacityProviders of the cluster
+func calcLaunchType(d *schema.ResourceData, cfr *ecs.Service) string {
+	if cfr.LaunchType != "" {
+		return cfr.LaunchType
+	}
+
+	strategies := make([]ecs.Cluster_CapacityProviderStrategyItem, 0, len(cfr.CapacityProviderStrategy))
+	for _, s := range cfr.CapacityProviderStrategy {
+		strategies = append(strategies, ecs.Cluster_CapacityProviderStrategyItem{
+			Base:             s.Base,
+			CapacityProvider: s.CapacityProvider,
+			Weight:           s.Weight,
+		})
+	}
+
+	launchType := getCapacityProviderLaunchType(strategies)
+	if launchType != "" {
+		return launchType
+	}
+
+	for _, ref := range d.References("Cluster") {
+		cluster, ok := ref.CFResource.(*ecs.Cluster)
+		if !ok {
+			continue
+		}
+
+		if len(cluster.DefaultCapacityProviderStrategy) > 0 {
+			return getCapacityProviderLaunchType(cluster.DefaultCapacityProviderStrategy)
+		}
+
+		for _, capProvider := range cluster.CapacityProviders {
+			if capProvider == "FARGATE" {
+				return "FARGATE"
+			}
+		}
+	}
+
+	return launchType
+}
+
+func getCapacityProviderLaunchType(strategies []ecs.Cluster_CapacityProviderStrategyItem) string {
+	launchType := ""
+	for _, s := range strategies {
+		if s.Base > 0 || s.Weight > 0 {
+			if strings.HasPrefix(strings.ToUpper(s.CapacityProvider), "FARGATE") {
+				// We have at least one fargate provider, use that as the launch type
+				return "FARGATE"
+			}
+			launchType = "EC2"
+		}
+	}
+	return launchType
+}
+
+func parseVCPUMemoryString(rawValue string) float64 {
+	var quantity float64
+
+	noSpaceString := strings.ReplaceAll(rawValue, " ", "")
+
+	reg := regexp.MustCompile(`(?i)vcpu|gb`)
+	if reg.MatchString(noSpaceString) {
+		quantity, _ = strconv.ParseFloat(reg.ReplaceAllString(noSpaceString, ""), 64)
+	} else {
+		quantity, _ = strconv.ParseFloat(noSpaceString, 64)
+		quantity /= 1024.0
+	}
+
+	return quantity
+}
@@ -0,0 +1,16 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/cloudformation/cftest"
+)
+
+func TestECSServiceGoldenFile(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	cftest.GoldenFileResourceTests(t, "ecs_service_test")
+}
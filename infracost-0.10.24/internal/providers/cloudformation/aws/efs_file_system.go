@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/efs"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEFSFileSystemRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EFS::FileSystem",
+		RFunc: NewEFSFileSystem,
+	}
+}
+
+func NewEFSFileSystem(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*efs.FileSystem)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	a := &aws.EFSFileSystem{
+		Address:                     d.Address,
+		Region:                      region,
+		HasLifecyclePolicy:          len(cfr.LifecyclePolicies) > 0,
+		AvailabilityZoneName:        cfr.AvailabilityZoneName,
+		ProvisionedThroughputInMBps: cfr.ProvisionedThroughputInMibps,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
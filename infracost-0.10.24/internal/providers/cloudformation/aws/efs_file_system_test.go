@@ -0,0 +1,16 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/cloudformation/cftest"
+)
+
+func TestEFSFileSystemGoldenFile(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	cftest.GoldenFileResourceTests(t, "efs_file_system_test")
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// eipReferences are the attributes of other resources that allocate the
+// Elastic IP when they reference it.
+var eipReferences = []string{
+	"AWS::EC2::NatGateway.AllocationId",
+	"AWS::EC2::EIPAssociation.AllocationId",
+	"AWS::ElasticLoadBalancingV2::LoadBalancer.SubnetMappings.#.AllocationId",
+}
+
+func GetEIPRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:                "AWS::EC2::EIP",
+		ReferenceAttributes: eipReferences,
+		RFunc:               NewEIP,
+	}
+}
+
+func NewEIP(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.EIP)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	allocated := cfr.InstanceId != "" || len(d.References(eipReferences...)) > 0
+
+	a := &aws.EIP{
+		Address:   d.Address,
+		Region:    region,
+		Allocated: allocated,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
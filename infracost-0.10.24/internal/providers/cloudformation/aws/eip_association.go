@@ -0,0 +1,14 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEIPAssociationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:                "AWS::EC2::EIPAssociation",
+		NoPrice:             true,
+		Notes:               []string{"Free resource."},
+		ReferenceAttributes: []string{"AllocationId"},
+	}
+}
@@ -0,0 +1,25 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetNewEKSClusterItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EKS::Cluster",
+		RFunc: NewEKSCluster,
+	}
+}
+
+func NewEKSCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	a := &aws.EKSCluster{
+		Address: d.Address,
+		Region:  region,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
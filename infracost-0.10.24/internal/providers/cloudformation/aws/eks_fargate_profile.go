@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/eks"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetNewEKSFargateProfileItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EKS::FargateProfile",
+		RFunc: NewEKSFargateProfile,
+	}
+}
+
+func NewEKSFargateProfile(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	_, ok := d.CFResource.(*eks.FargateProfile)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	a := &aws.EKSFargateProfile{
+		Address: d.Address,
+		Region:  region,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
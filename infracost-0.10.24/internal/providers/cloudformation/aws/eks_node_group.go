@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/eks"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const defaultEKSInstanceType = "t3.medium"
+
+func GetNewEKSNodeGroupItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EKS::Nodegroup",
+		RFunc: NewEKSNodeGroup,
+	}
+}
+
+func NewEKSNodeGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*eks.Nodegroup)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	var instanceCount int64
+	if cfr.ScalingConfig != nil {
+		instanceCount = int64(cfr.ScalingConfig.DesiredSize)
+	}
+
+	diskSize := float64(20)
+	if cfr.DiskSize > 0 {
+		diskSize = cfr.DiskSize
+	}
+
+	instanceType := defaultEKSInstanceType
+	if len(cfr.InstanceTypes) > 0 {
+		instanceType = strings.ToLower(cfr.InstanceTypes[0])
+	}
+
+	purchaseOption := "on_demand"
+	if strings.ToLower(cfr.CapacityType) == "spot" {
+		purchaseOption = "spot"
+	}
+
+	a := &aws.EKSNodeGroup{
+		Address:        d.Address,
+		Region:         region,
+		Name:           cfr.NodegroupName,
+		ClusterName:    cfr.ClusterName,
+		InstanceType:   instanceType,
+		PurchaseOption: purchaseOption,
+		DiskSize:       diskSize,
+		InstanceCount:  intPtr(instanceCount),
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
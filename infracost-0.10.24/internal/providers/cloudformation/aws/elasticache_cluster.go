@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticache"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetElastiCacheClusterItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElastiCache::CacheCluster",
+		RFunc: NewElastiCacheCluster,
+	}
+}
+
+func NewElastiCacheCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticache.CacheCluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	a := &aws.ElastiCacheCluster{
+		Address:                d.Address,
+		Region:                 region,
+		NodeType:               cfr.CacheNodeType,
+		Engine:                 cfr.Engine,
+		CacheNodes:             int64(cfr.NumCacheNodes),
+		SnapshotRetentionLimit: int64(cfr.SnapshotRetentionLimit),
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
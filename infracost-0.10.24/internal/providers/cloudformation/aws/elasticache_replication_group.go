@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticache"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetElastiCacheReplicationGroupItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElastiCache::ReplicationGroup",
+		RFunc: NewElastiCacheReplicationGroup,
+	}
+}
+
+func NewElastiCacheReplicationGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticache.ReplicationGroup)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	cacheClusters := int64(cfr.NumCacheClusters)
+	if cacheClusters == 0 {
+		cacheClusters = 1
+	}
+
+	a := &aws.ElastiCacheReplicationGroup{
+		Address:                     d.Address,
+		Region:                      region,
+		NodeType:                    cfr.CacheNodeType,
+		Engine:                      cfr.Engine,
+		CacheClusters:               cacheClusters,
+		ClusterNodeGroups:           int64(cfr.NumNodeGroups),
+		ClusterReplicasPerNodeGroup: int64(cfr.ReplicasPerNodeGroup),
+		SnapshotRetentionLimit:      int64(cfr.SnapshotRetentionLimit),
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
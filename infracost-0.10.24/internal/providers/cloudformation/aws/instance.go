@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// rootDeviceNames are the device names used for the root volume by the
+// common Linux and Windows AMIs.
+var rootDeviceNames = map[string]bool{
+	"/dev/xvda": true,
+	"/dev/sda1": true,
+}
+
+func GetInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::EC2::Instance",
+		Notes: []string{
+			"Costs associated with marketplace AMIs are not supported.",
+			"For non-standard Linux AMIs such as Windows and RHEL, the operating system should be specified in usage file.",
+			"EC2 detailed monitoring assumes the standard 7 metrics and the lowest tier of prices for CloudWatch.",
+			"If a root volume is not specified then an 8Gi gp2 volume is assumed.",
+			"Launch templates are not yet supported.",
+		},
+		RFunc: NewInstance,
+	}
+}
+
+func NewInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.Instance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	cpuCredits := ""
+	if cfr.CreditSpecification != nil {
+		cpuCredits = cfr.CreditSpecification.CPUCredits
+	}
+
+	a := &aws.Instance{
+		Address:          d.Address,
+		Region:           region,
+		Tenancy:          cfr.Tenancy,
+		PurchaseOption:   "on_demand",
+		AMI:              cfr.ImageId,
+		InstanceType:     cfr.InstanceType,
+		EBSOptimized:     cfr.EbsOptimized,
+		EnableMonitoring: cfr.Monitoring,
+		CPUCredits:       cpuCredits,
+		HasHost:          cfr.HostId != "",
+		RootBlockDevice: &aws.EBSVolume{
+			Address: "root_block_device",
+			Region:  region,
+		},
+	}
+
+	for _, m := range cfr.BlockDeviceMappings {
+		if m.Ebs == nil || m.NoDevice != nil {
+			continue
+		}
+
+		v := a.RootBlockDevice
+		if !rootDeviceNames[m.DeviceName] {
+			v = &aws.EBSVolume{
+				Address: fmt.Sprintf("ebs_block_device[%d]", len(a.EBSBlockDevices)),
+				Region:  region,
+			}
+			a.EBSBlockDevices = append(a.EBSBlockDevices, v)
+		}
+
+		v.Type = m.Ebs.VolumeType
+		v.IOPS = int64(m.Ebs.Iops)
+		if m.Ebs.VolumeSize > 0 {
+			v.Size = intPtr(int64(m.Ebs.VolumeSize))
+		}
+	}
+
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLambdaFunctionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Lambda::Function",
+		RFunc: NewLambdaFunction,
+	}
+}
+
+func NewLambdaFunction(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*lambda.Function)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	memorySize := int64(128)
+	if cfr.MemorySize > 0 {
+		memorySize = int64(cfr.MemorySize)
+	}
+
+	// The goformation version we use doesn't support the Architectures or
+	// EphemeralStorage properties yet so we use their defaults.
+
+	a := &aws.LambdaFunction{
+		Address:      d.Address,
+		Region:       region,
+		Name:         cfr.FunctionName,
+		MemorySize:   memorySize,
+		Architecture: "x86_64",
+		StorageSize:  512,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetLBRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::ElasticLoadBalancingV2::LoadBalancer",
+		ReferenceAttributes: []string{
+			"SubnetMappings.#.AllocationId",
+		},
+		RFunc: NewLB,
+	}
+}
+
+func NewLB(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticloadbalancingv2.LoadBalancer)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	loadBalancerType := cfr.Type
+	if loadBalancerType == "" {
+		// CloudFormation creates an Application Load Balancer if no type is given.
+		loadBalancerType = "application"
+	}
+
+	a := &aws.LB{
+		Address:          d.Address,
+		Region:           region,
+		LoadBalancerType: loadBalancerType,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetRDSClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::RDS::DBCluster",
+		RFunc: NewRDSCluster,
+	}
+}
+
+func NewRDSCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*rds.DBCluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	engine := cfr.Engine
+	if engine == "" {
+		engine = "aurora"
+	}
+
+	engineMode := cfr.EngineMode
+	if engineMode == "" {
+		engineMode = "provisioned"
+	}
+
+	backupRetentionPeriod := int64(cfr.BackupRetentionPeriod)
+	if backupRetentionPeriod == 0 {
+		backupRetentionPeriod = 1
+	}
+
+	a := &aws.RDSCluster{
+		Address:               d.Address,
+		Region:                region,
+		Engine:                engine,
+		EngineMode:            engineMode,
+		BackupRetentionPeriod: backupRetentionPeriod,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
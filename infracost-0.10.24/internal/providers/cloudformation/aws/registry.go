@@ -6,13 +6,13 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetAPIGatewayRestAPIRegistryItem(),
 	// GetAPIGatewayStageRegistryItem(),
 	// GetAPIGatewayv2ApiRegistryItem(),
-	// GetAutoscalingGroupRegistryItem(),
+	GetAutoscalingGroupRegistryItem(),
 	// GetACMCertificate(),
 	// GetACMPCACertificateAuthorityRegistryItem(),
 	// GetCloudfrontDistributionRegistryItem(),
 	// GetCloudwatchDashboardRegistryItem(),
 	// GetCloudwatchEventBusItem(),
-	// GetCloudwatchLogGroupItem(),
+	GetCloudwatchLogGroupItem(),
 	// GetCloudwatchMetricAlarmRegistryItem(),
 	// GetCodebuildProjectRegistryItem(),
 	// GetConfigRuleItem(),
@@ -20,7 +20,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetConfigOrganizationCustomRuleItem(),
 	// GetConfigOrganizationManagedRuleItem(),
 	// getDataTransferRegistryItem(),
-	// GetDBInstanceRegistryItem(),
+	GetDBInstanceRegistryItem(),
 	// GetDMSRegistryItem(),
 	// GetDocDBClusterInstanceRegistryItem(),
 	// GetDocDBClusterRegistryItem(),
@@ -30,49 +30,50 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetDynamoDBTableRegistryItem(),
 	// GetEBSSnapshotCopyRegistryItem(),
 	// GetEBSSnapshotRegistryItem(),
-	// GetEBSVolumeRegistryItem(),
+	GetEBSVolumeRegistryItem(),
 	// GetEC2ClientVPNEndpointRegistryItem(),
 	// GetEC2ClientVPNNetworkAssociationRegistryItem(),
 	// GetEC2TrafficMirroSessionRegistryItem(),
 	// GetEC2TransitGatewayPeeringAttachmentRegistryItem(),
 	// GetEC2TransitGatewayVpcAttachmentRegistryItem(),
 	// GetECRRegistryItem(),
-	// GetECSServiceRegistryItem(),
-	// GetEFSFileSystemRegistryItem(),
-	// GetEIPRegistryItem(),
-	// GetElastiCacheClusterItem(),
-	// GetElastiCacheReplicationGroupItem(),
+	GetECSServiceRegistryItem(),
+	GetEFSFileSystemRegistryItem(),
+	GetEIPRegistryItem(),
+	GetEIPAssociationRegistryItem(),
+	GetElastiCacheClusterItem(),
+	GetElastiCacheReplicationGroupItem(),
 	// GetElasticsearchDomainRegistryItem(),
-	// GetELBRegistryItem(),
+	GetELBRegistryItem(),
 	// GetFSXWindowsFSRegistryItem(),
-	// GetInstanceRegistryItem(),
-	// GetLambdaFunctionRegistryItem(),
-	// GetLBRegistryItem(),
+	GetInstanceRegistryItem(),
+	GetLambdaFunctionRegistryItem(),
+	GetLBRegistryItem(),
 	// GetLightsailInstanceRegistryItem(),
 	// GetMSKClusterRegistryItem(),
 	// GetALBRegistryItem(),
 	// GetMQBrokerRegistryItem(),
-	// GetNATGatewayRegistryItem(),
-	// GetRDSClusterRegistryItem(),
+	GetNATGatewayRegistryItem(),
+	GetRDSClusterRegistryItem(),
 	// GetRDSClusterInstanceRegistryItem(),
 	// GetRedshiftClusterRegistryItem(),
 	// GetRoute53HealthCheck(),
 	// GetRoute53ResolverEndpointRegistryItem(),
 	// GetRoute53RecordRegistryItem(),
 	// GetRoute53ZoneRegistryItem(),
-	// GetS3BucketRegistryItem(),
+	GetS3BucketRegistryItem(),
 	// GetS3BucketAnalyticsConfigurationRegistryItem(),
 	// GetS3BucketInventoryRegistryItem(),
-	// GetSecretsManagerSecret(),
+	GetSecretsManagerSecret(),
 	// GetSSMActivationRegistryItem(),
 	// GetSSMParameterRegistryItem(),
-	// GetSNSTopicRegistryItem(),
+	GetSNSTopicRegistryItem(),
 	// GetSNSTopicSubscriptionRegistryItem(),
-	// GetSQSQueueRegistryItem(),
-	// GetNewEKSNodeGroupItem(),
-	// GetNewEKSFargateProfileItem(),
-	// GetNewEKSClusterItem(),
-	// GetNewKMSKeyRegistryItem(),
+	GetSQSQueueRegistryItem(),
+	GetNewEKSNodeGroupItem(),
+	GetNewEKSFargateProfileItem(),
+	GetNewEKSClusterItem(),
+	GetNewKMSKeyRegistryItem(),
 	// GetNewKMSExternalKeyRegistryItem(),
 	// GetVPNConnectionRegistryItem(),
 	// GetVpcEndpointRegistryItem(),
@@ -363,6 +364,71 @@ var FreeResources = []string{
 	"aws_vpn_gateway_attachment",
 	"aws_vpn_gateway_route_propagation",
 
+	// AWS CloudFormation resource types
+	"AWS::AutoScaling::LaunchConfiguration",
+	"AWS::CDK::Metadata",
+	"AWS::CloudFormation::Stack",
+	"AWS::CloudFormation::WaitCondition",
+	"AWS::CloudFormation::WaitConditionHandle",
+	"AWS::EC2::DHCPOptions",
+	"AWS::EC2::EgressOnlyInternetGateway",
+	"AWS::EC2::InternetGateway",
+	"AWS::EC2::KeyPair",
+	"AWS::EC2::LaunchTemplate",
+	"AWS::EC2::NetworkAcl",
+	"AWS::EC2::NetworkAclEntry",
+	"AWS::EC2::NetworkInterface",
+	"AWS::EC2::NetworkInterfaceAttachment",
+	"AWS::EC2::Route",
+	"AWS::EC2::RouteTable",
+	"AWS::EC2::SecurityGroup",
+	"AWS::EC2::SecurityGroupEgress",
+	"AWS::EC2::SecurityGroupIngress",
+	"AWS::EC2::Subnet",
+	"AWS::EC2::SubnetNetworkAclAssociation",
+	"AWS::EC2::SubnetRouteTableAssociation",
+	"AWS::EC2::VolumeAttachment",
+	"AWS::EC2::VPC",
+	"AWS::EC2::VPCDHCPOptionsAssociation",
+	"AWS::EC2::VPCGatewayAttachment",
+	"AWS::ECS::Cluster",
+	"AWS::ECS::TaskDefinition",
+	"AWS::EKS::Addon",
+	"AWS::ElastiCache::ParameterGroup",
+	"AWS::ElastiCache::SubnetGroup",
+	"AWS::ElasticLoadBalancingV2::Listener",
+	"AWS::ElasticLoadBalancingV2::ListenerCertificate",
+	"AWS::ElasticLoadBalancingV2::ListenerRule",
+	"AWS::ElasticLoadBalancingV2::TargetGroup",
+	"AWS::IAM::AccessKey",
+	"AWS::IAM::Group",
+	"AWS::IAM::InstanceProfile",
+	"AWS::IAM::ManagedPolicy",
+	"AWS::IAM::OIDCProvider",
+	"AWS::IAM::Policy",
+	"AWS::IAM::Role",
+	"AWS::IAM::ServiceLinkedRole",
+	"AWS::IAM::User",
+	"AWS::IAM::UserToGroupAddition",
+	"AWS::KMS::Alias",
+	"AWS::Lambda::Alias",
+	"AWS::Lambda::EventInvokeConfig",
+	"AWS::Lambda::EventSourceMapping",
+	"AWS::Lambda::Permission",
+	"AWS::Lambda::Version",
+	"AWS::Logs::MetricFilter",
+	"AWS::Logs::SubscriptionFilter",
+	"AWS::RDS::DBClusterParameterGroup",
+	"AWS::RDS::DBParameterGroup",
+	"AWS::RDS::DBSubnetGroup",
+	"AWS::RDS::OptionGroup",
+	"AWS::S3::BucketPolicy",
+	"AWS::SecretsManager::ResourcePolicy",
+	"AWS::SecretsManager::RotationSchedule",
+	"AWS::SecretsManager::SecretTargetAttachment",
+	"AWS::SNS::TopicPolicy",
+	"AWS::SQS::QueuePolicy",
+
 	// Hashicorp
 	"null_resource",
 	"local_file",
@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/s3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+var s3StorageClassNames = map[string]string{
+	"STANDARD":            "standard",
+	"INTELLIGENT_TIERING": "intelligent_tiering",
+	"STANDARD_IA":         "standard_infrequent_access",
+	"ONEZONE_IA":          "one_zone_infrequent_access",
+	"GLACIER":             "glacier_flexible_retrieval",
+	"DEEP_ARCHIVE":        "glacier_deep_archive",
+}
+
+func GetS3BucketRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::S3::Bucket",
+		Notes: []string{
+			"S3 replication time control data transfer, and batch operations are not supported by CloudFormation.",
+		},
+		RFunc: NewS3Bucket,
+	}
+}
+
+func NewS3Bucket(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*s3.Bucket)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	objTagsEnabled := false
+
+	// Always add the standard storage class
+	lifecycleStorageClasses := []string{"standard"}
+	seen := map[string]bool{"standard": true}
+
+	addStorageClass := func(s string) {
+		storageClass := s3StorageClassNames[s]
+		if storageClass != "" && !seen[storageClass] {
+			seen[storageClass] = true
+			lifecycleStorageClasses = append(lifecycleStorageClasses, storageClass)
+		}
+	}
+
+	if cfr.LifecycleConfiguration != nil {
+		for _, rule := range cfr.LifecycleConfiguration.Rules {
+			if rule.Status != "Enabled" {
+				continue
+			}
+
+			if len(rule.TagFilters) > 0 {
+				objTagsEnabled = true
+			}
+
+			if rule.Transition != nil {
+				addStorageClass(rule.Transition.StorageClass)
+			}
+			for _, t := range rule.Transitions {
+				addStorageClass(t.StorageClass)
+			}
+
+			if rule.NoncurrentVersionTransition != nil {
+				addStorageClass(rule.NoncurrentVersionTransition.StorageClass)
+			}
+			for _, t := range rule.NoncurrentVersionTransitions {
+				addStorageClass(t.StorageClass)
+			}
+		}
+	}
+
+	a := &aws.S3Bucket{
+		Address:                 d.Address,
+		Region:                  region,
+		Name:                    cfr.BucketName,
+		ObjectTagsEnabled:       objTagsEnabled,
+		LifecycleStorageClasses: lifecycleStorageClasses,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/sns"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetSNSTopicRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SNS::Topic",
+		RFunc: NewSNSTopic,
+	}
+}
+
+func NewSNSTopic(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*sns.Topic)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	if cfr.FifoTopic {
+		a := &aws.SNSFIFOTopic{
+			Address:       d.Address,
+			Region:        region,
+			Subscriptions: int64(len(cfr.Subscription)),
+		}
+		a.PopulateUsage(u)
+
+		return a.BuildResource()
+	}
+
+	a := &aws.SNSTopic{
+		Address: d.Address,
+		Region:  region,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
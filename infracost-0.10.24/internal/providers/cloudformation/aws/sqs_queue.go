@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/sqs"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetSQSQueueRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SQS::Queue",
+		RFunc: NewSQSQueue,
+	}
+}
+
+func NewSQSQueue(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*sqs.Queue)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	a := &aws.SQSQueue{
+		Address:   d.Address,
+		Region:    region,
+		FifoQueue: cfr.FifoQueue,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -1,13 +1,53 @@
 package aws
 
 import (
-	"github.com/awslabs/goformation/v4/cloudformation/tags"
+	"github.com/tidwall/gjson"
 )
 
-func mapTags(cfTags []tags.Tag) map[string]string {
-	mapped := make(map[string]string)
-	for _, tag := range cfTags {
-		mapped[tag.Key] = tag.Value
+// tagProperties are the properties that contain the tags of resources which
+// don't use the Tags property.
+var tagProperties = map[string]string{
+	"AWS::EFS::AccessPoint": "AccessPointTags",
+	"AWS::EFS::FileSystem":  "FileSystemTags",
+}
+
+// ParseTags returns the tags from the properties of a resource. Tags are
+// usually a list of Key and Value objects, but some resources, e.g. EKS node
+// groups and SSM parameters, specify them as an object of key-value pairs.
+func ParseTags(resourceType string, v gjson.Result) map[string]string {
+	prop := "Tags"
+	if p, ok := tagProperties[resourceType]; ok {
+		prop = p
+	}
+
+	tags := make(map[string]string)
+
+	t := v.Get(prop)
+	if t.IsObject() {
+		for k, v := range t.Map() {
+			tags[k] = v.String()
+		}
+
+		return tags
+	}
+
+	for _, tag := range t.Array() {
+		// Only the tags propagated to the instances launched by an auto
+		// scaling group apply to the resources that are priced.
+		if resourceType == "AWS::AutoScaling::AutoScalingGroup" && !tag.Get("PropagateAtLaunch").Bool() {
+			continue
+		}
+
+		tags[tag.Get("Key").String()] = tag.Get("Value").String()
 	}
-	return mapped
+
+	return tags
+}
+
+func intPtr(i int64) *int64 {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
 }
@@ -0,0 +1,296 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// cdkManifest is the manifest.json of a CDK cloud assembly, i.e. the cdk.out
+// directory that `cdk synth` writes to.
+type cdkManifest struct {
+	Version   string                 `json:"version"`
+	Artifacts map[string]cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type        string `json:"type"`
+	Environment string `json:"environment"`
+	Properties  struct {
+		TemplateFile  string            `json:"templateFile"`
+		StackName     string            `json:"stackName"`
+		Parameters    map[string]string `json:"parameters"`
+		Tags          map[string]string `json:"tags"`
+		DirectoryName string            `json:"directoryName"`
+	} `json:"properties"`
+}
+
+// cdkStack is a stack artifact of a cloud assembly along with the directory
+// of the assembly that contains it.
+type cdkStack struct {
+	id       string
+	dir      string
+	artifact cdkArtifact
+}
+
+// stackName returns the name the stack is deployed with, which defaults to
+// the artifact ID.
+func (s cdkStack) stackName() string {
+	if s.artifact.Properties.StackName != "" {
+		return s.artifact.Properties.StackName
+	}
+
+	return s.id
+}
+
+// environment returns the account and region of the stack. They are empty
+// for environment-agnostic stacks.
+func (s cdkStack) environment() (string, string) {
+	env := strings.TrimPrefix(s.artifact.Environment, "aws://")
+	parts := strings.SplitN(env, "/", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+
+	account, region := parts[0], parts[1]
+	if account == "unknown-account" {
+		account = ""
+	}
+	if region == "unknown-region" {
+		region = ""
+	}
+
+	return account, region
+}
+
+// CDKAssemblyProvider estimates the costs of the stacks of a CDK cloud
+// assembly. Each stack is a separate project and the resources of its nested
+// stacks are included in it.
+type CDKAssemblyProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewCDKAssemblyProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &CDKAssemblyProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *CDKAssemblyProvider) Type() string {
+	return "cdk_assembly"
+}
+
+func (p *CDKAssemblyProvider) DisplayType() string {
+	return "AWS CDK cloud assembly"
+}
+
+func (p *CDKAssemblyProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *CDKAssemblyProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	stacks, err := readCDKAssembly(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CDK cloud assembly")
+	}
+
+	configValues, err := parameterValues(p.ctx.ProjectConfig)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation parameters file")
+	}
+
+	projects := make([]*schema.Project, 0, len(stacks))
+	for _, stack := range stacks {
+		project := p.newStackProject(stack)
+
+		resources, err := p.loadStackResources(stack, configValues, usage)
+		if err != nil {
+			project.Metadata.AddError(errors.Wrapf(err, "Error reading stack %s", stack.id))
+		}
+
+		project.PartialResources = resources
+		if p.includePastResources {
+			project.PartialPastResources = resources
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+// newStackProject returns the project of a stack. It is named by the stack ID
+// and the account and region of the stack, if they're set.
+func (p *CDKAssemblyProvider) newStackProject(stack cdkStack) *schema.Project {
+	metadata := config.DetectProjectMetadata(filepath.Join(stack.dir, stack.artifact.Properties.TemplateFile))
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+
+	name := stack.id
+	if account, region := stack.environment(); account != "" || region != "" {
+		name = fmt.Sprintf("%s (%s/%s)", name, account, region)
+	}
+	if p.ctx.ProjectConfig.Name != "" {
+		name = fmt.Sprintf("%s/%s", p.ctx.ProjectConfig.Name, name)
+	}
+
+	return schema.NewProject(name, metadata)
+}
+
+func (p *CDKAssemblyProvider) loadStackResources(stack cdkStack, configValues map[string]string, usage schema.UsageMap) ([]*schema.PartialResource, error) {
+	// The account, region and parameters of the stack artifact are used
+	// unless they are overridden by the parameters file or project config.
+	parameters := map[string]string{"AWS::StackName": stack.stackName()}
+	account, region := stack.environment()
+	if account != "" {
+		parameters["AWS::AccountId"] = account
+	}
+	if region != "" {
+		parameters["AWS::Region"] = region
+	}
+	for k, v := range stack.artifact.Properties.Parameters {
+		parameters[k] = v
+	}
+	for k, v := range configValues {
+		parameters[k] = v
+	}
+
+	stackTags := make(map[string]string)
+	for k, v := range stack.artifact.Properties.Tags {
+		stackTags[k] = v
+	}
+	for k, v := range p.ctx.ProjectConfig.CloudFormationStackTags {
+		stackTags[k] = v
+	}
+
+	template, raw, err := parseTemplateFile(filepath.Join(stack.dir, stack.artifact.Properties.TemplateFile), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := NewParser(p.ctx, stackTags, stackRegion(parameters))
+	resources := parser.parseTemplate(template, raw, usage)
+
+	nested, err := parser.parseNestedStacks(stack.dir, raw, parameters, usage, "")
+	if err != nil {
+		return resources, err
+	}
+
+	return append(resources, nested...), nil
+}
+
+// parseNestedStacks returns the resources of the nested stacks of a template.
+// CDK writes the templates of nested stacks to the cloud assembly and sets the
+// aws:asset:path metadata of the AWS::CloudFormation::Stack resource to their
+// file. The addresses of the resources are prefixed with the logical ID of
+// the nested stack resource.
+func (p *Parser) parseNestedStacks(dir string, raw gjson.Result, parentParameters map[string]string, usage schema.UsageMap, addressPrefix string) ([]*schema.PartialResource, error) {
+	var resources []*schema.PartialResource
+	var err error
+
+	raw.Get("Resources").ForEach(func(key, r gjson.Result) bool {
+		if r.Get("Type").String() != "AWS::CloudFormation::Stack" {
+			return true
+		}
+
+		name := key.String()
+		assetPath := r.Get("Metadata").Get("aws:asset:path").String()
+		if assetPath == "" {
+			log.Debugf("Skipping nested stack %s as its template is not in the cloud assembly", addressPrefix+name)
+			return true
+		}
+
+		// Nested stacks are deployed to the same account and region as their
+		// parent stack.
+		parameters := map[string]string{
+			"AWS::StackName": fmt.Sprintf("%s-%s", parentParameters["AWS::StackName"], name),
+		}
+		for _, k := range []string{"AWS::AccountId", "AWS::Region"} {
+			if v, ok := parentParameters[k]; ok {
+				parameters[k] = v
+			}
+		}
+		for k, v := range r.Get("Properties.Parameters").Map() {
+			parameters[k] = parameterString(v.Value())
+		}
+
+		template, nestedRaw, parseErr := parseTemplateFile(filepath.Join(dir, assetPath), parameters)
+		if parseErr != nil {
+			err = errors.Wrapf(parseErr, "Error reading nested stack %s", addressPrefix+name)
+			return false
+		}
+
+		prefix := fmt.Sprintf("%s%s.", addressPrefix, name)
+		resources = append(resources, p.parseResources(template, nestedRaw, usage, prefix)...)
+
+		var nested []*schema.PartialResource
+		nested, err = p.parseNestedStacks(dir, nestedRaw, parameters, usage, prefix)
+		resources = append(resources, nested...)
+
+		return err == nil
+	})
+
+	return resources, err
+}
+
+// readCDKAssembly returns the stacks of a cloud assembly, including those of
+// the nested assemblies of CDK stages. The stacks are sorted by their ID.
+func readCDKAssembly(dir string) ([]cdkStack, error) {
+	manifest, err := readCDKManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(manifest.Artifacts))
+	for id := range manifest.Artifacts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var stacks []cdkStack
+	for _, id := range ids {
+		artifact := manifest.Artifacts[id]
+
+		switch artifact.Type {
+		case "aws:cloudformation:stack":
+			stacks = append(stacks, cdkStack{id: id, dir: dir, artifact: artifact})
+		case "cdk:cloud-assembly":
+			nested, err := readCDKAssembly(filepath.Join(dir, artifact.Properties.DirectoryName))
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error reading nested assembly %s", id)
+			}
+
+			stacks = append(stacks, nested...)
+		}
+	}
+
+	return stacks, nil
+}
+
+func readCDKManifest(dir string) (*cdkManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest cdkManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,71 @@
+package cloudformation
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestCDKAssemblyProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: "testdata/cdk_assembly",
+	}, log.Fields{})
+
+	projects, err := NewCDKAssemblyProvider(ctx, true).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	web := projects[0]
+	assert.Equal(t, "WebStack (123456789012/eu-west-1)", web.Name)
+	assert.Equal(t, "cdk_assembly", web.Metadata.Type)
+	assert.Equal(t, "testdata/cdk_assembly/WebStack.template.json", web.Metadata.Path)
+	assert.Empty(t, web.Metadata.Errors)
+
+	web.BuildResources(schema.UsageMap{})
+	assert.Equal(t, map[string]string{
+		"CDKMetadata":      "",
+		"Instance008A4B15": "Instance usage (Linux/UNIX, on-demand, t3.micro)",
+		"DatabaseNestedStackDatabaseNestedStackResourceA1B2C3D4":                          "",
+		"DatabaseNestedStackDatabaseNestedStackResourceA1B2C3D4.DatabaseInstance2E4B3A1C": "Database instance (on-demand, Single-AZ, db.t3.medium)",
+	}, resourceSummary(web.Resources))
+
+	// The resources, including those of the nested stack, are priced in the
+	// region of the stack environment.
+	for _, r := range web.Resources {
+		assert.Equal(t, "web", r.Tags["Team"], r.Name)
+
+		for _, c := range r.CostComponents {
+			assert.Equal(t, "eu-west-1", *c.ProductFilter.Region, r.Name)
+		}
+	}
+
+	// Stacks of CDK stages are in nested assemblies
+	prod := projects[1]
+	assert.Equal(t, "ProdApiStack5E8A2C1B", prod.Name)
+	assert.Equal(t, "testdata/cdk_assembly/assembly-Prod/ProdApiStack5E8A2C1B.template.json", prod.Metadata.Path)
+	assert.Empty(t, prod.Metadata.Errors)
+
+	require.Len(t, prod.PartialResources, 1)
+	assert.Equal(t, "Queue4A7E3555", prod.PartialResources[0].ResourceData.Address)
+	assert.Equal(t, "Prod-ApiStack-queue", prod.PartialResources[0].ResourceData.Get("QueueName").String())
+}
+
+func TestCDKAssemblyProviderProjectName(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: "testdata/cdk_assembly",
+		Name: "web-app",
+	}, log.Fields{})
+
+	projects, err := NewCDKAssemblyProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	assert.Equal(t, "web-app/WebStack (123456789012/eu-west-1)", projects[0].Name)
+	assert.Equal(t, "web-app/ProdApiStack5E8A2C1B", projects[1].Name)
+	assert.Nil(t, projects[0].PartialPastResources)
+}
@@ -0,0 +1,84 @@
+package cftest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/testutil"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+// GoldenFileResourceTests loads the CloudFormation template from
+// testdata/<testName>/<testName>.yml, along with the usage file from
+// testdata/<testName>/<testName>.usage.yml if it exists, and compares the
+// table output with testdata/<testName>/<testName>.golden.
+func GoldenFileResourceTests(t *testing.T, testName string) {
+	t.Helper()
+
+	runCtx, err := config.NewRunContextFromEnv(context.Background())
+	require.NoError(t, err)
+
+	testutil.ConfigureTestToFailOnLogs(t, runCtx)
+	runCtx.Config.Currency = "USD"
+
+	// Load the usage data, if any.
+	var usageData schema.UsageMap
+	usageFilePath := filepath.Join("testdata", testName, testName+".usage.yml")
+	if _, err := os.Stat(usageFilePath); err == nil || !os.IsNotExist(err) {
+		// usage file exists, load the data
+		usageFile, err := usage.LoadUsageFile(usageFilePath)
+		require.NoError(t, err)
+		usageData = usageFile.ToUsageDataMap()
+	}
+
+	templatePath := filepath.Join("testdata", testName, testName+".yml")
+	provider := cloudformation.NewTemplateProvider(config.NewProjectContext(runCtx, &config.Project{
+		Path: templatePath,
+		Name: t.Name(),
+	}, log.Fields{}), false)
+
+	projects, err := provider.LoadResources(usageData)
+	require.NoError(t, err)
+
+	for _, project := range projects {
+		project.BuildResources(schema.UsageMap{})
+
+		err := prices.PopulatePrices(runCtx, project)
+		require.NoError(t, err)
+
+		schema.CalculateCosts(project)
+	}
+
+	r, err := output.ToOutputFormat(projects)
+	require.NoError(t, err)
+	r.Currency = runCtx.Config.Currency
+
+	opts := output.Options{
+		ShowSkipped: true,
+		NoColor:     true,
+		Fields:      runCtx.Config.Fields,
+	}
+
+	actual, err := output.ToTable(r, opts)
+	require.NoError(t, err)
+
+	// strip the first line of output since it contains the project name
+	endOfFirstLine := bytes.Index(actual, []byte("\n"))
+	if endOfFirstLine > 0 {
+		actual = actual[endOfFirstLine+1:]
+	}
+
+	goldenFilePath := filepath.Join("testdata", testName, testName+".golden")
+	testutil.AssertGoldenFile(t, goldenFilePath, actual)
+}
@@ -0,0 +1,223 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// changeSet is the output of `aws cloudformation describe-change-set`.
+type changeSet struct {
+	StackID    string `json:"StackId"`
+	StackName  string `json:"StackName"`
+	Parameters []struct {
+		ParameterKey   string `json:"ParameterKey"`
+		ParameterValue string `json:"ParameterValue"`
+		ResolvedValue  string `json:"ResolvedValue"`
+	} `json:"Parameters"`
+	Tags []struct {
+		Key   string `json:"Key"`
+		Value string `json:"Value"`
+	} `json:"Tags"`
+	Changes []struct {
+		Type           string `json:"Type"`
+		ResourceChange struct {
+			Action            string `json:"Action"`
+			LogicalResourceID string `json:"LogicalResourceId"`
+			ResourceType      string `json:"ResourceType"`
+			AfterContext      string `json:"AfterContext"`
+		} `json:"ResourceChange"`
+	} `json:"Changes"`
+}
+
+// ChangeSetProvider estimates the costs of a CloudFormation change set. The
+// past resources are parsed from the deployed template, which is set by the
+// compare template, and the current resources are parsed from the deployed
+// template with the changes of the change set applied.
+type ChangeSetProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewChangeSetProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &ChangeSetProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *ChangeSetProvider) Type() string {
+	return "cloudformation_change_set"
+}
+
+func (p *ChangeSetProvider) DisplayType() string {
+	return "CloudFormation change set"
+}
+
+func (p *ChangeSetProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *ChangeSetProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	templatePath := p.ctx.ProjectConfig.CloudFormationCompareTemplate
+	if templatePath == "" {
+		return []*schema.Project{}, errors.New("CloudFormation change sets require the deployed template, use --compare-template to set its path")
+	}
+
+	cs, err := readChangeSetFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation change set file")
+	}
+
+	// The parameter values of the change set are used unless they are
+	// overridden by the parameters file or project config. The account and
+	// region of the stack are taken from its ARN.
+	parameters := make(map[string]string)
+	if cs.StackName != "" {
+		parameters["AWS::StackName"] = cs.StackName
+	}
+	if parts := strings.Split(cs.StackID, ":"); len(parts) >= 6 && strings.HasPrefix(cs.StackID, "arn:") {
+		parameters["AWS::Region"] = parts[3]
+		parameters["AWS::AccountId"] = parts[4]
+	}
+	for _, param := range cs.Parameters {
+		v := param.ParameterValue
+		if param.ResolvedValue != "" {
+			v = param.ResolvedValue
+		}
+		parameters[param.ParameterKey] = v
+	}
+
+	configValues, err := parameterValues(p.ctx.ProjectConfig)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation parameters file")
+	}
+	for k, v := range configValues {
+		parameters[k] = v
+	}
+
+	deployed, err := readTemplateFile(templatePath)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
+	}
+
+	stackName := templateStackName(templatePath)
+
+	pastTemplate, pastRaw, err := evaluateTemplate(deployed, stackName, parameters)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
+	}
+
+	applied, err := cs.apply(deployed)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error applying CloudFormation change set")
+	}
+
+	template, raw, err := evaluateTemplate(applied, stackName, parameters)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error applying CloudFormation change set")
+	}
+
+	project := newProject(p.ctx, p)
+	parser := NewParser(p.ctx, cs.stackTags(p.ctx.ProjectConfig.CloudFormationStackTags), stackRegion(parameters))
+
+	project.PartialResources = parser.parseTemplate(template, raw, usage)
+	if p.includePastResources {
+		project.PartialPastResources = parser.parseTemplate(pastTemplate, pastRaw, usage)
+	}
+
+	return []*schema.Project{project}, nil
+}
+
+func readChangeSetFile(path string) (*changeSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cs changeSet
+	if err := json.Unmarshal(data, &cs); err != nil {
+		return nil, err
+	}
+
+	return &cs, nil
+}
+
+// stackTags returns the tags of the stack from the change set, overridden by
+// the stack tags in the project config.
+func (cs *changeSet) stackTags(configTags map[string]string) map[string]string {
+	tags := make(map[string]string, len(cs.Tags)+len(configTags))
+	for _, tag := range cs.Tags {
+		tags[tag.Key] = tag.Value
+	}
+
+	for k, v := range configTags {
+		tags[k] = v
+	}
+
+	return tags
+}
+
+// apply returns a copy of the template with the resource changes of the
+// change set applied. The properties of added and modified resources are
+// taken from the AfterContext of the change, which is only included when the
+// change set is described with --include-property-values. An error is
+// returned if an added, modified or imported resource has no AfterContext,
+// since the deployed template has no properties for it or has the properties
+// from before the change.
+func (cs *changeSet) apply(template map[string]interface{}) (map[string]interface{}, error) {
+	resources := make(map[string]interface{})
+	for name, r := range objectValue(template["Resources"]) {
+		resources[name] = r
+	}
+
+	for _, change := range cs.Changes {
+		if change.Type != "Resource" {
+			continue
+		}
+
+		rc := change.ResourceChange
+		if rc.Action == "Remove" {
+			delete(resources, rc.LogicalResourceID)
+			continue
+		}
+
+		resource := map[string]interface{}{}
+		for k, v := range objectValue(resources[rc.LogicalResourceID]) {
+			resource[k] = v
+		}
+
+		// The change set has already evaluated whether the resource is
+		// created so its condition no longer applies.
+		delete(resource, "Condition")
+		resource["Type"] = rc.ResourceType
+
+		if rc.AfterContext != "" {
+			var after map[string]interface{}
+			if err := json.Unmarshal([]byte(rc.AfterContext), &after); err != nil {
+				return nil, errors.Wrapf(err, "Could not parse the AfterContext of %s", rc.LogicalResourceID)
+			}
+
+			resource["Properties"] = objectValue(after["Properties"])
+		} else if rc.Action == "Add" || rc.Action == "Modify" || rc.Action == "Import" {
+			return nil, errors.Errorf("Change set has no property values for %s, describe it with --include-property-values to include them", rc.LogicalResourceID)
+		}
+
+		resources[rc.LogicalResourceID] = resource
+	}
+
+	applied := make(map[string]interface{}, len(template))
+	for k, v := range template {
+		applied[k] = v
+	}
+	applied["Resources"] = resources
+
+	return applied, nil
+}
@@ -0,0 +1,141 @@
+package cloudformation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func loadProject(t *testing.T, provider schema.Provider) *schema.Project {
+	projects, err := provider.LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	project.BuildResources(schema.UsageMap{})
+
+	return project
+}
+
+// resourceSummary returns the names of the resources along with the name of
+// their first cost component.
+func resourceSummary(resources []*schema.Resource) map[string]string {
+	summary := make(map[string]string, len(resources))
+	for _, r := range resources {
+		s := ""
+		if len(r.CostComponents) > 0 {
+			s = r.CostComponents[0].Name
+		} else if len(r.SubResources) > 0 {
+			s = r.SubResources[0].Name
+		}
+		summary[r.Name] = s
+	}
+
+	return summary
+}
+
+func TestChangeSetProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:                          "testdata/change_set/change_set.json",
+		CloudFormationCompareTemplate: "testdata/change_set/deployed.yml",
+	}, log.Fields{})
+
+	project := loadProject(t, NewChangeSetProvider(ctx, true))
+
+	assert.Equal(t, map[string]string{
+		"Instance":  "Instance usage (Linux/UNIX, on-demand, t3.micro)",
+		"OldVolume": "Storage (general purpose SSD, gp2)",
+		"Queue":     "Requests",
+	}, resourceSummary(project.PastResources))
+
+	assert.Equal(t, map[string]string{
+		"Bucket":    "Standard",
+		"Instance":  "Instance usage (Linux/UNIX, on-demand, t3.large)",
+		"NewVolume": "Storage (general purpose SSD, gp3)",
+		"Queue":     "Requests",
+	}, resourceSummary(project.Resources))
+
+	// The resources are priced in the region of the stack from its ARN.
+	for _, r := range project.Resources {
+		for _, c := range r.CostComponents {
+			assert.Equal(t, "us-west-2", *c.ProductFilter.Region, r.Name)
+		}
+	}
+}
+
+func TestChangeSetProviderRequiresTemplate(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: "testdata/change_set/change_set.json",
+	}, log.Fields{})
+
+	_, err := NewChangeSetProvider(ctx, true).LoadResources(schema.UsageMap{})
+	assert.EqualError(t, err, "CloudFormation change sets require the deployed template, use --compare-template to set its path")
+}
+
+func TestTemplateProviderCompareTemplate(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:                          "testdata/change_set/template.yml",
+		CloudFormationCompareTemplate: "testdata/change_set/deployed.yml",
+	}, log.Fields{})
+
+	project := loadProject(t, NewTemplateProvider(ctx, true))
+
+	assert.Equal(t, map[string]string{
+		"Instance":  "Instance usage (Linux/UNIX, on-demand, t3.micro)",
+		"OldVolume": "Storage (general purpose SSD, gp2)",
+		"Queue":     "Requests",
+	}, resourceSummary(project.PastResources))
+
+	assert.Equal(t, map[string]string{
+		"Bucket":   "Standard",
+		"Instance": "Instance usage (Linux/UNIX, on-demand, m5.large)",
+		"Queue":    "Requests",
+	}, resourceSummary(project.Resources))
+}
+
+func TestChangeSetProviderRequiresPropertyValues(t *testing.T) {
+	tests := []struct {
+		action            string
+		logicalResourceID string
+		resourceType      string
+	}{
+		{action: "Add", logicalResourceID: "Bucket", resourceType: "AWS::S3::Bucket"},
+		{action: "Modify", logicalResourceID: "Instance", resourceType: "AWS::EC2::Instance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "change_set.json")
+			err := os.WriteFile(path, []byte(fmt.Sprintf(`{
+  "StackName": "web",
+  "Changes": [
+    {
+      "Type": "Resource",
+      "ResourceChange": {
+        "Action": %q,
+        "LogicalResourceId": %q,
+        "ResourceType": %q
+      }
+    }
+  ]
+}`, tt.action, tt.logicalResourceID, tt.resourceType)), 0600)
+			require.NoError(t, err)
+
+			ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+				Path:                          path,
+				CloudFormationCompareTemplate: "testdata/change_set/deployed.yml",
+			}, log.Fields{})
+
+			_, err = NewChangeSetProvider(ctx, true).LoadResources(schema.UsageMap{})
+			assert.EqualError(t, err, fmt.Sprintf("Error applying CloudFormation change set: Change set has no property values for %s, describe it with --include-property-values to include them", tt.logicalResourceID))
+		})
+	}
+}
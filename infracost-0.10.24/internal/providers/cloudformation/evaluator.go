@@ -0,0 +1,589 @@
+package cloudformation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultRegion is the region stacks are assumed to be deployed to when the
+// AWS::Region pseudo parameter isn't set.
+const defaultRegion = "us-east-1"
+
+var subVariableRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// noValue is returned by a Ref to AWS::NoValue. The property or list item
+// that contains it is removed from the template.
+type noValue struct{}
+
+// evaluator resolves the intrinsic functions, parameters, mappings and
+// conditions of a template so the resources have the values they would have
+// when the stack is deployed. Anything that can only be known once the stack
+// is deployed, e.g. most Fn::GetAtt attributes, resolves to nil.
+type evaluator struct {
+	parameters map[string]interface{}
+	mappings   map[string]interface{}
+	conditions map[string]interface{}
+	resources  map[string]interface{}
+
+	conditionResults map[string]bool
+	evaluating       map[string]bool
+}
+
+// newEvaluator returns an evaluator for the template. The parameter values
+// override the defaults of the template parameters and can also be used to
+// set the pseudo parameters, e.g. AWS::Region.
+func newEvaluator(template map[string]interface{}, stackName string, values map[string]string) *evaluator {
+	e := &evaluator{
+		parameters:       pseudoParameters(stackName, values),
+		mappings:         objectValue(template["Mappings"]),
+		conditions:       objectValue(template["Conditions"]),
+		resources:        objectValue(template["Resources"]),
+		conditionResults: make(map[string]bool),
+		evaluating:       make(map[string]bool),
+	}
+
+	for name, v := range objectValue(template["Parameters"]) {
+		param := objectValue(v)
+		paramType, _ := param["Type"].(string)
+
+		value, ok := param["Default"]
+		if s, set := values[name]; set {
+			value, ok = s, true
+		}
+
+		// The values of SSM parameter types are the names of the parameters
+		// in SSM so we can't know their actual values.
+		if !ok || strings.HasPrefix(paramType, "AWS::SSM::") {
+			continue
+		}
+
+		e.parameters[name] = parameterValue(paramType, value)
+	}
+
+	return e
+}
+
+// stackRegion returns the region of the stack from the AWS::Region pseudo
+// parameter value, or the default region if it isn't set.
+func stackRegion(values map[string]string) string {
+	if v, ok := values["AWS::Region"]; ok && v != "" {
+		return v
+	}
+
+	return defaultRegion
+}
+
+func pseudoParameters(stackName string, values map[string]string) map[string]interface{} {
+	region := stackRegion(values)
+
+	accountID := "123456789012"
+	if v, ok := values["AWS::AccountId"]; ok {
+		accountID = v
+	}
+
+	if v, ok := values["AWS::StackName"]; ok {
+		stackName = v
+	}
+
+	partition := "aws"
+	urlSuffix := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		partition = "aws-cn"
+		urlSuffix = "amazonaws.com.cn"
+	} else if strings.HasPrefix(region, "us-gov-") {
+		partition = "aws-us-gov"
+	}
+
+	return map[string]interface{}{
+		"AWS::AccountId":        accountID,
+		"AWS::NotificationARNs": []interface{}{},
+		"AWS::NoValue":          noValue{},
+		"AWS::Partition":        partition,
+		"AWS::Region":           region,
+		"AWS::StackId":          fmt.Sprintf("arn:%s:cloudformation:%s:%s:stack/%s/id", partition, region, accountID, stackName),
+		"AWS::StackName":        stackName,
+		"AWS::URLSuffix":        urlSuffix,
+	}
+}
+
+// parameterValue converts the value of a parameter to the type that a Ref of
+// the parameter resolves to.
+func parameterValue(paramType string, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	switch {
+	case paramType == "Number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case paramType == "CommaDelimitedList" || strings.HasPrefix(paramType, "List<"):
+		items := []interface{}{}
+		for _, item := range strings.Split(s, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		return items
+	}
+
+	return s
+}
+
+// evaluateResources returns the resources of the template with their
+// intrinsic functions resolved. Resources whose condition is false are
+// removed.
+func (e *evaluator) evaluateResources() (map[string]interface{}, error) {
+	resources := make(map[string]interface{}, len(e.resources))
+
+	for name, v := range e.resources {
+		resource := objectValue(v)
+
+		if cond, ok := resource["Condition"].(string); ok {
+			enabled, err := e.condition(cond)
+			if err != nil {
+				return nil, fmt.Errorf("resource %s: %w", name, err)
+			}
+
+			if !enabled {
+				continue
+			}
+		}
+
+		evaluated, err := e.evaluate(resource)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", name, err)
+		}
+
+		resources[name] = evaluated
+	}
+
+	return resources, nil
+}
+
+// condition returns the value of the named condition. Each condition is only
+// evaluated once.
+func (e *evaluator) condition(name string) (bool, error) {
+	if v, ok := e.conditionResults[name]; ok {
+		return v, nil
+	}
+
+	expr, ok := e.conditions[name]
+	if !ok {
+		return false, fmt.Errorf("condition %s not found", name)
+	}
+
+	if e.evaluating[name] {
+		return false, fmt.Errorf("condition %s references itself", name)
+	}
+
+	e.evaluating[name] = true
+	defer delete(e.evaluating, name)
+
+	v, err := e.evaluate(expr)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition %s is not a boolean", name)
+	}
+
+	e.conditionResults[name] = b
+
+	return b, nil
+}
+
+func (e *evaluator) evaluate(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if len(value) == 1 {
+			for key, arg := range value {
+				if isFunction(key, arg) {
+					return e.evaluateFunction(key, arg)
+				}
+			}
+		}
+
+		evaluated := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			ev, err := e.evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := ev.(noValue); !ok {
+				evaluated[key] = ev
+			}
+		}
+
+		return evaluated, nil
+	case []interface{}:
+		evaluated := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			ev, err := e.evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := ev.(noValue); !ok {
+				evaluated = append(evaluated, ev)
+			}
+		}
+
+		return evaluated, nil
+	}
+
+	return v, nil
+}
+
+// isFunction returns whether a single key object is an intrinsic function. A
+// Condition is only a function when it references a condition by name, since
+// e.g. IAM policy statements have their own Condition objects.
+func isFunction(key string, arg interface{}) bool {
+	if key == "Condition" {
+		_, ok := arg.(string)
+		return ok
+	}
+
+	return key == "Ref" || strings.HasPrefix(key, "Fn::")
+}
+
+func (e *evaluator) evaluateFunction(name string, arg interface{}) (interface{}, error) {
+	// Fn::If only evaluates the branch that is selected by its condition,
+	// so the other branch can reference things that don't exist.
+	if name == "Fn::If" {
+		return e.evaluateIf(arg)
+	}
+
+	if name == "Condition" {
+		return e.condition(arg.(string))
+	}
+
+	arg, err := e.evaluate(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "Ref":
+		return e.ref(arg), nil
+	case "Fn::GetAtt":
+		return e.getAtt(arg), nil
+	case "Fn::Sub":
+		return e.sub(arg), nil
+	case "Fn::FindInMap":
+		return e.findInMap(arg), nil
+	case "Fn::Select":
+		return selectItem(arg), nil
+	case "Fn::Join":
+		return join(arg), nil
+	case "Fn::Split":
+		return split(arg), nil
+	case "Fn::GetAZs":
+		return e.getAZs(arg), nil
+	case "Fn::Base64":
+		if s, ok := arg.(string); ok {
+			return base64.StdEncoding.EncodeToString([]byte(s)), nil
+		}
+	case "Fn::Equals":
+		args, ok := arg.([]interface{})
+		if !ok || len(args) != 2 {
+			return nil, fmt.Errorf("Fn::Equals requires 2 arguments")
+		}
+
+		return scalarString(args[0]) == scalarString(args[1]), nil
+	case "Fn::And", "Fn::Or", "Fn::Not":
+		return e.logical(name, arg)
+	}
+
+	// Fn::ImportValue, Fn::Cidr and any other functions can't be resolved
+	// from the template alone.
+	return nil, nil
+}
+
+func (e *evaluator) evaluateIf(arg interface{}) (interface{}, error) {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) != 3 {
+		return nil, fmt.Errorf("Fn::If requires 3 arguments")
+	}
+
+	cond, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("Fn::If requires a condition name")
+	}
+
+	enabled, err := e.condition(cond)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		return e.evaluate(args[1])
+	}
+
+	return e.evaluate(args[2])
+}
+
+// ref resolves a Ref of a parameter or pseudo parameter to its value. Refs of
+// resources resolve to the logical ID of the resource so the parser can link
+// the resources that reference each other.
+func (e *evaluator) ref(arg interface{}) interface{} {
+	name, ok := arg.(string)
+	if !ok {
+		return nil
+	}
+
+	if v, ok := e.parameters[name]; ok {
+		return v
+	}
+
+	if _, ok := e.resources[name]; ok {
+		return name
+	}
+
+	return nil
+}
+
+// getAtt resolves a Fn::GetAtt of a resource ID or ARN to the logical ID of
+// the resource, the same as a Ref. Other attributes aren't known until the
+// stack is deployed so they resolve to nil.
+func (e *evaluator) getAtt(arg interface{}) interface{} {
+	var parts []string
+
+	switch v := arg.(type) {
+	case string:
+		parts = strings.SplitN(v, ".", 2)
+	case []interface{}:
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+	}
+
+	if len(parts) != 2 {
+		return nil
+	}
+
+	if _, ok := e.resources[parts[0]]; !ok {
+		return nil
+	}
+
+	if strings.HasSuffix(parts[1], "Id") || strings.HasSuffix(parts[1], "Arn") {
+		return parts[0]
+	}
+
+	return nil
+}
+
+// sub substitutes the variables of a Fn::Sub string. Variables that can't be
+// resolved are left as they are.
+func (e *evaluator) sub(arg interface{}) interface{} {
+	var s string
+	vars := map[string]interface{}{}
+
+	switch v := arg.(type) {
+	case string:
+		s = v
+	case []interface{}:
+		if len(v) != 2 {
+			return nil
+		}
+
+		s, _ = v[0].(string)
+		vars = objectValue(v[1])
+	default:
+		return nil
+	}
+
+	return subVariableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		name := match[2 : len(match)-1]
+
+		// ${!Literal} is escaped and is written as ${Literal}
+		if strings.HasPrefix(name, "!") {
+			return "${" + name[1:] + "}"
+		}
+
+		var resolved interface{}
+		if v, ok := vars[name]; ok {
+			resolved = v
+		} else if strings.Contains(name, ".") {
+			resolved = e.getAtt(name)
+		} else {
+			resolved = e.ref(name)
+		}
+
+		switch r := resolved.(type) {
+		case string, float64, bool:
+			return scalarString(r)
+		}
+
+		return match
+	})
+}
+
+func (e *evaluator) findInMap(arg interface{}) interface{} {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) < 3 {
+		return nil
+	}
+
+	m := objectValue(e.mappings[scalarString(args[0])])
+	top := objectValue(m[scalarString(args[1])])
+
+	return top[scalarString(args[2])]
+}
+
+func (e *evaluator) getAZs(arg interface{}) interface{} {
+	region, _ := arg.(string)
+	if region == "" {
+		region, _ = e.parameters["AWS::Region"].(string)
+	}
+
+	return []interface{}{region + "a", region + "b", region + "c"}
+}
+
+func selectItem(arg interface{}) interface{} {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) != 2 {
+		return nil
+	}
+
+	i, err := strconv.Atoi(scalarString(args[0]))
+	if err != nil {
+		return nil
+	}
+
+	items, ok := args[1].([]interface{})
+	if !ok || i < 0 || i >= len(items) {
+		return nil
+	}
+
+	return items[i]
+}
+
+func join(arg interface{}) interface{} {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) != 2 {
+		return nil
+	}
+
+	items, ok := args[1].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		switch item.(type) {
+		case string, float64, bool:
+			parts = append(parts, scalarString(item))
+		default:
+			return nil
+		}
+	}
+
+	return strings.Join(parts, scalarString(args[0]))
+}
+
+func split(arg interface{}) interface{} {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) != 2 {
+		return nil
+	}
+
+	s, ok := args[1].(string)
+	if !ok {
+		return nil
+	}
+
+	items := []interface{}{}
+	for _, item := range strings.Split(s, scalarString(args[0])) {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (e *evaluator) logical(name string, arg interface{}) (interface{}, error) {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) == 0 {
+		return nil, fmt.Errorf("%s requires at least 1 argument", name)
+	}
+
+	values := make([]bool, 0, len(args))
+	for _, a := range args {
+		// goformation doesn't convert the short form !Condition to its long
+		// form so it's left as the name of the condition.
+		if cond, ok := a.(string); ok {
+			b, err := e.condition(cond)
+			if err != nil {
+				return nil, err
+			}
+
+			a = b
+		}
+
+		b, ok := a.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s requires boolean arguments", name)
+		}
+
+		values = append(values, b)
+	}
+
+	switch name {
+	case "Fn::Not":
+		return !values[0], nil
+	case "Fn::And":
+		for _, b := range values {
+			if !b {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	default:
+		for _, b := range values {
+			if b {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+func objectValue(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+
+	return map[string]interface{}{}
+}
+
+// scalarString returns the string form of a value. CloudFormation compares
+// and joins values as strings so e.g. 1 and "1" are the same.
+func scalarString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, scalarString(item))
+		}
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprintf("%v", v)
+}
@@ -2,6 +2,7 @@ package cloudformation
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/awslabs/goformation/v4/cloudformation"
@@ -9,18 +10,25 @@ import (
 	"github.com/tidwall/gjson"
 
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/cloudformation/aws"
 	"github.com/infracost/infracost/internal/schema"
 )
 
 type Parser struct {
-	ctx *config.ProjectContext
+	ctx       *config.ProjectContext
+	stackTags map[string]string
+	region    string
 }
 
-func NewParser(ctx *config.ProjectContext) *Parser {
-	return &Parser{ctx}
+// NewParser returns a parser for the templates of a stack. The stack tags are
+// applied to all the resources of the stack unless a resource sets a tag with
+// the same key. The region of the stack is added to the resource data of all
+// the resources as the region value, the same as for Terraform resources.
+func NewParser(ctx *config.ProjectContext, stackTags map[string]string, region string) *Parser {
+	return &Parser{ctx, stackTags, region}
 }
 
-func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+func (p *Parser) createPartialResource(d *schema.ResourceData, u *schema.UsageData) *schema.PartialResource {
 	registryMap := GetResourceRegistryMap()
 
 	if isAwsChina(d) {
@@ -29,65 +37,122 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 
 	if registryItem, ok := (*registryMap)[d.Type]; ok {
 		if registryItem.NoPrice {
-			return &schema.Resource{
-				Name:         d.Address,
-				ResourceType: d.Type,
-				Tags:         d.Tags,
-				IsSkipped:    true,
-				NoPrice:      true,
-				SkipMessage:  "Free resource.",
+			return &schema.PartialResource{
+				ResourceData: d,
+				Resource: &schema.Resource{
+					Name:        d.Address,
+					IsSkipped:   true,
+					NoPrice:     true,
+					SkipMessage: "Free resource.",
+				},
 			}
 		}
 
 		res := registryItem.RFunc(d, u)
 		if res != nil {
-			res.ResourceType = d.Type
-			// TODO: Figure out how to set tags.  For now, have the RFunc set them.
-			// res.Tags = d.Tags
 			if u != nil {
 				res.EstimationSummary = u.CalcEstimationSummary()
 			}
-			return res
+			return &schema.PartialResource{ResourceData: d, Resource: res}
 		}
 	}
 
-	return &schema.Resource{
-		Name:         d.Address,
-		ResourceType: d.Type,
-		Tags:         d.Tags,
-		IsSkipped:    true,
-		SkipMessage:  "This resource is not currently supported",
+	return &schema.PartialResource{
+		ResourceData: d,
+		Resource: &schema.Resource{
+			Name:        d.Address,
+			IsSkipped:   true,
+			SkipMessage: "This resource is not currently supported",
+		},
 	}
 }
 
-func (p *Parser) parseTemplate(t *cloudformation.Template, usage schema.UsageMap) ([]*schema.Resource, []*schema.Resource, error) {
-	baseResources := p.loadUsageFileResources(usage)
+func (p *Parser) parseTemplate(t *cloudformation.Template, raw gjson.Result, usage schema.UsageMap) []*schema.PartialResource {
+	resources := p.loadUsageFileResources(usage)
+	resources = append(resources, p.parseResources(t, raw, usage, "")...)
 
-	var resources []*schema.Resource
-	resources = append(resources, baseResources...)
+	return resources
+}
+
+// parseResources returns the resources of a template. The address prefix is
+// added to the logical IDs of the resources so that the resources of nested
+// stacks have unique addresses.
+func (p *Parser) parseResources(t *cloudformation.Template, raw gjson.Result, usage schema.UsageMap, addressPrefix string) []*schema.PartialResource {
+	// Sort the logical IDs so the resources are always parsed in the same order
+	names := make([]string, 0, len(t.Resources))
+	for name := range t.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resData := make(map[string]*schema.ResourceData, len(names))
+	for _, name := range names {
+		d := t.Resources[name]
+		resourceType := d.AWSCloudFormationType()
+		// Logical IDs are alphanumeric so don't need escaping in the path
+		props := raw.Get(fmt.Sprintf("Resources.%s.Properties", name))
+
+		resourceData := schema.NewCFResourceData(resourceType, "aws", addressPrefix+name, p.parseTags(resourceType, props), d)
+		resourceData.RawValues = schema.AddRawValue(props, "region", p.region)
+		resData[name] = resourceData
+	}
 
-	for name, d := range t.Resources {
-		tags := map[string]string{} // TODO: Where do I get tags?
-		usageData := usage.Get(name)
+	p.parseReferences(resData)
 
-		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, tags, d)
+	resources := make([]*schema.PartialResource, 0, len(names))
+	for _, name := range names {
+		d := resData[name]
 
-		if r := p.createResource(resourceData, usageData); r != nil {
+		if r := p.createPartialResource(d, usage.Get(d.Address)); r != nil {
 			resources = append(resources, r)
 		}
 	}
 
-	return resources, resources, nil
+	return resources
+}
+
+// parseTags returns the stack tags merged with the tags of the resource.
+func (p *Parser) parseTags(resourceType string, props gjson.Result) map[string]string {
+	tags := make(map[string]string, len(p.stackTags))
+	for k, v := range p.stackTags {
+		tags[k] = v
+	}
+
+	for k, v := range aws.ParseTags(resourceType, props) {
+		tags[k] = v
+	}
+
+	return tags
+}
+
+// parseReferences links the resources whose reference attributes contain the
+// logical ID of another resource. Refs and Fn::GetAtts of resources resolve to
+// their logical ID when the template is parsed.
+func (p *Parser) parseReferences(resData map[string]*schema.ResourceData) {
+	registryMap := GetResourceRegistryMap()
+
+	for _, d := range resData {
+		for _, attr := range registryMap.GetReferenceAttributes(d.Type) {
+			for _, refVal := range d.Get(attr).Array() {
+				ref, ok := resData[refVal.String()]
+				if !ok {
+					continue
+				}
+
+				d.AddReference(attr, ref, registryMap.GetReferenceAttributes(ref.Type))
+			}
+		}
+	}
 }
 
-func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.Resource {
-	resources := make([]*schema.Resource, 0)
+func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.PartialResource {
+	resources := make([]*schema.PartialResource, 0)
 
 	for k, v := range u.Data() {
 		for _, t := range GetUsageOnlyResources() {
 			if strings.HasPrefix(k, fmt.Sprintf("%s.", t)) {
 				d := schema.NewResourceData(t, "global", k, map[string]string{}, gjson.Result{})
-				if r := p.createResource(d, v); r != nil {
+				if r := p.createPartialResource(d, v); r != nil {
 					resources = append(resources, r)
 				}
 			}
@@ -98,5 +163,5 @@ func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.Resource {
 }
 
 func isAwsChina(d *schema.ResourceData) bool {
-	return strings.HasPrefix(d.Type, "aws_") && strings.HasPrefix(d.Get("region").String(), "cn-")
+	return strings.HasPrefix(d.Type, "AWS::") && strings.HasPrefix(d.Get("region").String(), "cn-")
 }
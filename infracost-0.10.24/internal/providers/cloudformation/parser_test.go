@@ -0,0 +1,99 @@
+package cloudformation
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestParseTemplateTags(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: "testdata/tags/template.yml",
+		CloudFormationStackTags: map[string]string{
+			"Environment": "stack",
+			"Owner":       "infracost",
+		},
+	}, log.Fields{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	tags := make(map[string]map[string]string)
+	for _, r := range projects[0].PartialResources {
+		tags[r.ResourceData.Address] = r.ResourceData.Tags
+	}
+
+	assert.Equal(t, map[string]map[string]string{
+		"AutoScalingGroup":    {"Environment": "stack", "Owner": "infracost", "Name": "asg-instance"},
+		"FileSystem":          {"Environment": "stack", "Owner": "infracost", "Name": "file-system"},
+		"Instance":            {"Environment": "dev", "Owner": "infracost", "Name": "template-instance"},
+		"LaunchConfiguration": {"Environment": "stack", "Owner": "infracost"},
+		"NodeGroup":           {"Environment": "stack", "Owner": "infracost", "Name": "node-group", "Team": "platform"},
+		"Queue":               {"Environment": "stack", "Owner": "infracost"},
+	}, tags)
+}
+
+func TestParseTemplateAutoScalingGroupTags(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: "testdata/tags/template.yml",
+	}, log.Fields{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	var asg *schema.Resource
+	for _, r := range projects[0].PartialResources {
+		if r.ResourceData.Address == "AutoScalingGroup" {
+			asg = schema.BuildResource(r, nil)
+		}
+	}
+	require.NotNil(t, asg)
+
+	// Only the tags propagated at launch apply to the priced instances.
+	assert.False(t, asg.IsSkipped)
+	assert.Equal(t, map[string]string{"Name": "asg-instance"}, asg.Tags)
+	require.Len(t, asg.SubResources, 1)
+	assert.Equal(t, "LaunchConfiguration", asg.SubResources[0].Name)
+	require.NotEmpty(t, asg.SubResources[0].CostComponents)
+	assert.Equal(t, "Instance usage (Linux/UNIX, on-demand, t3.micro)", asg.SubResources[0].CostComponents[0].Name)
+}
+
+func TestParseTemplateRegion(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		region     string
+	}{
+		{"default", nil, "us-east-1"},
+		{"parameter", map[string]string{"AWS::Region": "eu-west-2"}, "eu-west-2"},
+		{"china", map[string]string{"AWS::Region": "cn-north-1"}, "cn-north-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+				Path:                     "testdata/tags/template.yml",
+				CloudFormationParameters: tt.parameters,
+			}, log.Fields{})
+
+			project := loadProject(t, NewTemplateProvider(ctx, false))
+			require.NotEmpty(t, project.Resources)
+
+			for _, r := range project.Resources {
+				for _, c := range r.CostComponents {
+					assert.Equal(t, tt.region, *c.ProductFilter.Region, r.Name)
+				}
+			}
+
+			_, isAWSChina := ctx.ContextValues()["isAWSChina"]
+			assert.Equal(t, tt.name == "china", isAWSChina)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/intrinsics"
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v2"
+)
+
+// parseTemplateFile reads a JSON or YAML CloudFormation template and
+// evaluates it with the parameter values.
+func parseTemplateFile(path string, parameters map[string]string) (*cloudformation.Template, gjson.Result, error) {
+	template, err := readTemplateFile(path)
+	if err != nil {
+		return nil, gjson.Result{}, err
+	}
+
+	return evaluateTemplate(template, templateStackName(path), parameters)
+}
+
+// readTemplateFile reads a JSON or YAML CloudFormation template without
+// evaluating it.
+func readTemplateFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the YAML to JSON, this also converts the short form intrinsic
+	// functions, e.g. !Ref, to their long form.
+	if !strings.HasSuffix(path, ".json") {
+		data, err = intrinsics.ProcessYAML(data, &intrinsics.ProcessorOptions{NoProcess: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var template map[string]interface{}
+	if err := json.Unmarshal(data, &template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// evaluateTemplate evaluates the intrinsic functions, parameters, mappings
+// and conditions of a template. It returns the goformation template along
+// with the evaluated template JSON, which holds the raw properties of the
+// resources including any that goformation doesn't know about.
+func evaluateTemplate(template map[string]interface{}, stackName string, parameters map[string]string) (*cloudformation.Template, gjson.Result, error) {
+	resources, err := newEvaluator(template, stackName, parameters).evaluateResources()
+	if err != nil {
+		return nil, gjson.Result{}, err
+	}
+
+	evaluated := make(map[string]interface{}, len(template))
+	for k, v := range template {
+		evaluated[k] = v
+	}
+	evaluated["Resources"] = resources
+
+	b, err := json.Marshal(evaluated)
+	if err != nil {
+		return nil, gjson.Result{}, err
+	}
+
+	// goformation fails to parse resource types that it doesn't know about,
+	// e.g. the AWS::CDK::Metadata resource that CDK adds to templates, so
+	// they're added to the template as custom resources instead.
+	known := cloudformation.AllResources()
+	knownResources := make(map[string]interface{}, len(resources))
+	unknown := make(map[string]map[string]interface{})
+	for name, v := range resources {
+		r := objectValue(v)
+		resourceType, _ := r["Type"].(string)
+		if _, ok := known[resourceType]; ok || strings.HasPrefix(resourceType, "Custom::") {
+			knownResources[name] = v
+		} else {
+			unknown[name] = r
+		}
+	}
+	evaluated["Resources"] = knownResources
+
+	tb, err := json.Marshal(evaluated)
+	if err != nil {
+		return nil, gjson.Result{}, err
+	}
+
+	t := &cloudformation.Template{}
+	if err := json.Unmarshal(tb, t); err != nil {
+		return nil, gjson.Result{}, err
+	}
+
+	if t.Resources == nil {
+		t.Resources = cloudformation.Resources{}
+	}
+
+	for name, r := range unknown {
+		resourceType, _ := r["Type"].(string)
+		t.Resources[name] = &cloudformation.CustomResource{
+			Type:       resourceType,
+			Properties: objectValue(r["Properties"]),
+		}
+	}
+
+	return t, gjson.ParseBytes(b), nil
+}
+
+func templateStackName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// loadParameterFile reads the parameter values from a JSON or YAML file. The
+// file can either be in the format used by the AWS CLI, i.e. a list of
+// ParameterKey and ParameterValue objects, or be an object of parameter names
+// to values. Objects with a Parameters key, as used by CodePipeline template
+// configuration files, are also supported.
+func loadParameterFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var content interface{}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+
+	switch v := content.(type) {
+	case []interface{}:
+		for _, item := range v {
+			param, ok := item.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected a ParameterKey and ParameterValue object, got %v", item)
+			}
+
+			key, ok := param["ParameterKey"].(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a ParameterKey and ParameterValue object, got %v", item)
+			}
+
+			values[key] = parameterString(param["ParameterValue"])
+		}
+	case map[interface{}]interface{}:
+		if params, ok := v["Parameters"].(map[interface{}]interface{}); ok {
+			v = params
+		}
+
+		for key, value := range v {
+			values[fmt.Sprintf("%v", key)] = parameterString(value)
+		}
+	case nil:
+	default:
+		return nil, errors.New("expected a list of parameters or an object of parameter values")
+	}
+
+	return values, nil
+}
+
+// parameterString returns a parameter value as a string the same as it would
+// be passed to CloudFormation, lists are comma-delimited.
+func parameterString(v interface{}) string {
+	if l, ok := v.([]interface{}); ok {
+		items := make([]string, 0, len(l))
+		for _, item := range l {
+			items = append(items, fmt.Sprintf("%v", item))
+		}
+
+		return strings.Join(items, ",")
+	}
+
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", v)
+}
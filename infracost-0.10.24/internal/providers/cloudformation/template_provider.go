@@ -1,7 +1,6 @@
 package cloudformation
 
 import (
-	"github.com/awslabs/goformation/v4"
 	"github.com/pkg/errors"
 
 	"github.com/infracost/infracost/internal/config"
@@ -34,33 +33,76 @@ func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
 	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
 }
 
+// LoadResources parses the template. If a compare template is set the past
+// resources are parsed from it, otherwise the past resources are the same as
+// the current ones.
 func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
-	template, err := goformation.Open(p.Path)
+	parameters, err := parameterValues(p.ctx.ProjectConfig)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation parameters file")
+	}
+
+	template, raw, err := parseTemplateFile(p.Path, parameters)
 	if err != nil {
 		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
 	}
 
-	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	project := newProject(p.ctx, p)
+	parser := NewParser(p.ctx, p.ctx.ProjectConfig.CloudFormationStackTags, stackRegion(parameters))
+	resources := parser.parseTemplate(template, raw, usage)
+	pastResources := resources
+
+	if comparePath := p.ctx.ProjectConfig.CloudFormationCompareTemplate; comparePath != "" {
+		pastTemplate, pastRaw, err := parseTemplateFile(comparePath, parameters)
+		if err != nil {
+			return []*schema.Project{project}, errors.Wrap(err, "Error reading CloudFormation compare template file")
+		}
+
+		pastResources = parser.parseTemplate(pastTemplate, pastRaw, usage)
+	}
+
+	project.PartialPastResources = pastResources
+	project.PartialResources = resources
+
+	if !p.includePastResources {
+		project.PartialPastResources = nil
+	}
+
+	return []*schema.Project{project}, nil
+}
+
+func newProject(ctx *config.ProjectContext, p schema.Provider) *schema.Project {
+	metadata := config.DetectProjectMetadata(ctx.ProjectConfig.Path)
 	metadata.Type = p.Type()
 	p.AddMetadata(metadata)
-	name := p.ctx.ProjectConfig.Name
+	name := ctx.ProjectConfig.Name
 	if name == "" {
-		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+		name = metadata.GenerateProjectName(ctx.RunContext.VCSMetadata.Remote, ctx.RunContext.IsCloudEnabled())
 	}
 
-	project := schema.NewProject(name, metadata)
-	parser := NewParser(p.ctx)
-	pastResources, resources, err := parser.parseTemplate(template, usage)
-	if err != nil {
-		return []*schema.Project{project}, errors.Wrap(err, "Error parsing CloudFormation template file")
-	}
+	return schema.NewProject(name, metadata)
+}
 
-	project.PastResources = pastResources
-	project.Resources = resources
+// parameterValues returns the values of the template parameters from the
+// parameters file and the project config. Values in the project config
+// override those in the file.
+func parameterValues(projectCfg *config.Project) (map[string]string, error) {
+	values := make(map[string]string)
 
-	if !p.includePastResources {
-		project.PastResources = nil
+	if projectCfg.CloudFormationParametersFile != "" {
+		fileValues, err := loadParameterFile(projectCfg.CloudFormationParametersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range fileValues {
+			values[k] = v
+		}
 	}
 
-	return []*schema.Project{project}, nil
+	for k, v := range projectCfg.CloudFormationParameters {
+		values[k] = v
+	}
+
+	return values, nil
 }
@@ -0,0 +1,131 @@
+package cloudformation
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resourceNames(t *testing.T, path string, parameters map[string]string) []string {
+	template, _, err := parseTemplateFile(path, parameters)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(template.Resources))
+	for name := range template.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestParseTemplateFileDefaults(t *testing.T) {
+	template, raw, err := parseTemplateFile("testdata/evaluate/template.yml", map[string]string{"InstanceType": "t3.micro"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"BucketPolicy", "DevVolume", "EIP", "Instance"}, resourceNames(t, "testdata/evaluate/template.yml", map[string]string{"InstanceType": "t3.micro"}))
+
+	instance, ok := template.Resources["Instance"].(*ec2.Instance)
+	require.True(t, ok)
+	assert.Equal(t, "ami-12345678", instance.ImageId)
+	assert.Equal(t, "t3.micro", instance.InstanceType)
+	assert.Equal(t, "subnet-2", instance.SubnetId)
+	assert.False(t, instance.Monitoring)
+	require.Len(t, instance.BlockDeviceMappings, 1)
+	assert.Equal(t, 50, instance.BlockDeviceMappings[0].Ebs.VolumeSize)
+	assert.Equal(t, "template-dev-instance", instance.Tags[0].Value)
+	assert.Equal(t, "us-east-1a/aws", instance.Tags[1].Value)
+	assert.Equal(t, "${Literal}-${Missing.Attr}", instance.Tags[2].Value)
+	// The values of SSM parameters aren't known so they're left unresolved
+	assert.Equal(t, "${Image}", instance.Tags[3].Value)
+	assert.False(t, raw.Get("Resources.Instance.Properties.Monitoring").Exists())
+
+	eip, ok := template.Resources["EIP"].(*ec2.EIP)
+	require.True(t, ok)
+	assert.Equal(t, "Instance", eip.InstanceId)
+
+	volume, ok := template.Resources["DevVolume"].(*ec2.Volume)
+	require.True(t, ok)
+	assert.Equal(t, "", volume.AvailabilityZone)
+	assert.Equal(t, 50, volume.Size)
+
+	assert.Equal(t, "EIP", raw.Get("Resources.BucketPolicy.Properties.Bucket").String())
+	assert.Equal(t, "false", raw.Get("Resources.BucketPolicy.Properties.PolicyDocument.Statement.0.Condition.Bool.aws:SecureTransport").String())
+}
+
+func TestParseTemplateFileParameters(t *testing.T) {
+	parameters, err := loadParameterFile("testdata/evaluate/parameters.json")
+	require.NoError(t, err)
+
+	template, _, err := parseTemplateFile("testdata/evaluate/template.yml", parameters)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"BucketPolicy", "Database", "EIP", "EUBucket", "Instance"}, resourceNames(t, "testdata/evaluate/template.yml", parameters))
+
+	instance, ok := template.Resources["Instance"].(*ec2.Instance)
+	require.True(t, ok)
+	assert.Equal(t, "ami-87654321", instance.ImageId)
+	assert.Equal(t, "m5.large", instance.InstanceType)
+	assert.True(t, instance.Monitoring)
+	require.Len(t, instance.BlockDeviceMappings, 2)
+	assert.Equal(t, 80, instance.BlockDeviceMappings[0].Ebs.VolumeSize)
+	assert.Equal(t, 100, instance.BlockDeviceMappings[1].Ebs.VolumeSize)
+	assert.Equal(t, "template-prod-instance", instance.Tags[0].Value)
+	assert.Equal(t, "eu-west-1a/aws", instance.Tags[1].Value)
+
+	db, ok := template.Resources["Database"].(*rds.DBInstance)
+	require.True(t, ok)
+	assert.Equal(t, "db.m5.large", db.DBInstanceClass)
+}
+
+func TestLoadParameterFile(t *testing.T) {
+	parameters, err := loadParameterFile("testdata/evaluate/parameters.yml")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"InstanceType": "t3.micro", "Subnets": "subnet-a,subnet-b"}, parameters)
+
+	template, _, err := parseTemplateFile("testdata/evaluate/template.yml", parameters)
+	require.NoError(t, err)
+
+	instance, ok := template.Resources["Instance"].(*ec2.Instance)
+	require.True(t, ok)
+	assert.Equal(t, "subnet-b", instance.SubnetId)
+}
+
+func TestEvaluatorConditionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   string
+	}{
+		{
+			name:       "missing",
+			conditions: map[string]interface{}{},
+			expected:   "resource Bucket: condition Enabled not found",
+		},
+		{
+			name: "cycle",
+			conditions: map[string]interface{}{
+				"Enabled": map[string]interface{}{"Fn::Not": []interface{}{map[string]interface{}{"Condition": "Enabled"}}},
+			},
+			expected: "resource Bucket: condition Enabled references itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEvaluator(map[string]interface{}{
+				"Conditions": tt.conditions,
+				"Resources": map[string]interface{}{
+					"Bucket": map[string]interface{}{"Type": "AWS::S3::Bucket", "Condition": "Enabled"},
+				},
+			}, "stack", nil)
+
+			_, err := e.evaluateResources()
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -77,7 +77,12 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 
 		return h, nil
 	case "terraform_plan_json":
-		return terraform.NewPlanJSONProvider(ctx, includePastResources), nil
+		p, err := terraform.NewPlanJSONProvider(ctx, includePastResources)
+		if err != nil {
+			return nil, err
+		}
+
+		return p, nil
 	case "terraform_plan_binary":
 		return terraform.NewPlanProvider(ctx, includePastResources), nil
 	case "terraform_cli":
@@ -88,6 +93,10 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 		return terraform.NewStateJSONProvider(ctx, includePastResources), nil
 	case "cloudformation":
 		return cloudformation.NewTemplateProvider(ctx, includePastResources), nil
+	case "cloudformation_change_set":
+		return cloudformation.NewChangeSetProvider(ctx, includePastResources), nil
+	case "cdk_assembly":
+		return cloudformation.NewCDKAssemblyProvider(ctx, includePastResources), nil
 	}
 
 	return nil, fmt.Errorf("could not detect path type for '%s'", path)
@@ -120,6 +129,14 @@ func DetectProjectType(path string, forceCLI bool) string {
 		return "cloudformation"
 	}
 
+	if isCloudFormationChangeSet(path) {
+		return "cloudformation_change_set"
+	}
+
+	if isCDKAssembly(path) {
+		return "cdk_assembly"
+	}
+
 	if isTerraformPlanJSON(path) {
 		return "terraform_plan_json"
 	}
@@ -244,6 +261,46 @@ func isTerragruntNestedDir(path string, maxDepth int) bool {
 	return false
 }
 
+func isCloudFormationChangeSet(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var jsonFormat struct {
+		ChangeSetID string      `json:"ChangeSetId"`
+		Changes     interface{} `json:"Changes"`
+	}
+
+	err = json.Unmarshal(b, &jsonFormat)
+	if err != nil {
+		return false
+	}
+
+	return jsonFormat.ChangeSetID != "" && jsonFormat.Changes != nil
+}
+
+// isCDKAssembly returns true if the path is a CDK cloud assembly directory,
+// i.e. the cdk.out directory that contains the manifest.json of the stacks.
+func isCDKAssembly(path string) bool {
+	b, err := os.ReadFile(filepath.Join(path, "manifest.json"))
+	if err != nil {
+		return false
+	}
+
+	var jsonFormat struct {
+		Version   string      `json:"version"`
+		Artifacts interface{} `json:"artifacts"`
+	}
+
+	err = json.Unmarshal(b, &jsonFormat)
+	if err != nil {
+		return false
+	}
+
+	return jsonFormat.Version != "" && jsonFormat.Artifacts != nil
+}
+
 // goformation lib is not threadsafe, so we run this check synchronously
 // See: https://github.com/awslabs/goformation/issues/363
 var cfMux = &sync.Mutex{}
@@ -2,7 +2,6 @@ package aws
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
@@ -65,7 +64,7 @@ func NewAutoscalingGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Re
 		data := launchTemplateRef[0]
 
 		onDemandPercentageAboveBaseCount := int64(100)
-		if strings.ToLower(data.Get("instance_market_options.0.market_type").String()) == "spot" {
+		if isSpotMarket(data.RawValues) {
 			onDemandPercentageAboveBaseCount = int64(0)
 		}
 
@@ -93,6 +92,7 @@ func newLaunchConfiguration(d *schema.ResourceData, u *schema.UsageData, region
 		InstanceCount:    intPtr(instanceCount),
 		Tenancy:          d.Get("placement_tenancy").String(),
 		PurchaseOption:   purchaseOption,
+		SpotMaxPrice:     spotMaxPrice(d.Get("spot_price")),
 		InstanceType:     d.Get("instance_type").String(),
 		EBSOptimized:     d.Get("ebs_optimized").Bool(),
 		EnableMonitoring: d.GetBoolOrDefault("enable_monitoring", true),
@@ -141,6 +141,7 @@ func newLaunchTemplate(d *schema.ResourceData, region string, instanceCount, onD
 		EBSOptimized:                     d.Get("ebs_optimized").Bool(),
 		EnableMonitoring:                 d.Get("monitoring.0.enabled").Bool(),
 		CPUCredits:                       d.Get("credit_specification.0.cpu_credits").String(),
+		SpotMaxPrice:                     spotMaxPrice(d.Get("instance_market_options.0.spot_options.0.max_price")),
 	}
 
 	if d.Get("elastic_inference_accelerator.0.type").Type != gjson.Null {
@@ -182,7 +183,14 @@ func newMixedInstancesLaunchTemplate(d *schema.ResourceData, u *schema.UsageData
 		onDemandPercentageAboveBaseCount = instanceDistribution.Get("on_demand_percentage_above_base_capacity").Int()
 	}
 
-	return newLaunchTemplate(d, region, instanceCount, onDemandBaseCount, onDemandPercentageAboveBaseCount)
+	lt := newLaunchTemplate(d, region, instanceCount, onDemandBaseCount, onDemandPercentageAboveBaseCount)
+
+	// The spot max price of the policy overrides the launch template's.
+	if spotPrice := spotMaxPrice(instanceDistribution.Get("spot_max_price")); spotPrice != nil {
+		lt.SpotMaxPrice = spotPrice
+	}
+
+	return lt
 }
 
 func getInstanceTypeAndCount(mixedInstancePolicyData gjson.Result, capacity int64) (string, int64) {
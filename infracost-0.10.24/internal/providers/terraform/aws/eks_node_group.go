@@ -54,8 +54,10 @@ func NewEKSNodeGroup(d *schema.ResourceData) schema.CoreResource {
 	if len(launchTemplateRef) > 0 {
 		data := launchTemplateRef[0]
 
+		// Managed node groups request spot instances with their capacity type
+		// rather than with the market options of the launch template.
 		onDemandPercentageAboveBaseCount := int64(100)
-		if strings.ToLower(launchTemplateRef[0].Get("instance_market_options.0.market_type").String()) == "spot" {
+		if isSpotCapacityType(d) || isSpotMarket(data.RawValues) {
 			onDemandPercentageAboveBaseCount = int64(0)
 		}
 
@@ -74,8 +76,15 @@ func NewEKSNodeGroup(d *schema.ResourceData) schema.CoreResource {
 		}
 
 		a.InstanceType = instanceType
-		a.PurchaseOption = strings.ToLower(d.Get("capacity_type").String())
+		a.PurchaseOption = "on_demand"
+		if isSpotCapacityType(d) {
+			a.PurchaseOption = "spot"
+		}
 	}
 
 	return a
 }
+
+func isSpotCapacityType(d *schema.ResourceData) bool {
+	return strings.ToLower(d.Get("capacity_type").String()) == "spot"
+}
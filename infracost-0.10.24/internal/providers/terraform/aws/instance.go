@@ -32,9 +32,10 @@ func NewInstance(d *schema.ResourceData) schema.CoreResource {
 	region := d.Get("region").String()
 
 	purchaseOption := "on_demand"
-	if d.Get("spot_price").String() != "" {
+	if d.Get("spot_price").String() != "" || isSpotMarket(d.RawValues) {
 		purchaseOption = "spot"
 	}
+	spotPrice := spotMaxPrice(d.Get("instance_market_options.0.spot_options.0.max_price"), d.Get("spot_price"))
 
 	var instanceType, ami, cpuCredits, tenancy string
 	var ebsOptimized, monitoring bool
@@ -55,6 +56,14 @@ func NewInstance(d *schema.ResourceData) schema.CoreResource {
 		cpuCredits = ref.Get("credit_specification.0.cpu_credits").String()
 		tenancy = ref.Get("placement.0.tenancy").String()
 
+		// The instance's own market options override the launch template's.
+		if !d.Get("instance_market_options.0.market_type").Exists() && isSpotMarket(ref.RawValues) {
+			purchaseOption = "spot"
+		}
+		if spotPrice == nil {
+			spotPrice = spotMaxPrice(ref.Get("instance_market_options.0.spot_options.0.max_price"))
+		}
+
 		for _, data := range ref.Get("block_device_mappings").Array() {
 			deviceName := data.Get("device_name").String()
 			ebsBlockDevice := &aws.EBSVolume{
@@ -84,6 +93,7 @@ func NewInstance(d *schema.ResourceData) schema.CoreResource {
 		Region:           region,
 		Tenancy:          tenancy,
 		PurchaseOption:   purchaseOption,
+		SpotMaxPrice:     spotPrice,
 		AMI:              ami,
 		InstanceType:     instanceType,
 		EBSOptimized:     ebsOptimized,
@@ -27,6 +27,7 @@ func newSpotInstanceRequest(d *schema.ResourceData, u *schema.UsageData) *schema
 		Address:        d.Address,
 		Region:         region,
 		PurchaseOption: "spot",
+		SpotMaxPrice:   spotMaxPrice(d.Get("spot_price")),
 		InstanceType:   instanceType,
 		AMI:            ami,
 	}
@@ -1,5 +1,12 @@
 package aws
 
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
 func intPtr(i int64) *int64 {
 	return &i
 }
@@ -11,3 +18,27 @@ func strPtr(s string) *string {
 func floatPtr(f float64) *float64 {
 	return &f
 }
+
+// isSpotMarket returns true if the instance_market_options of a resource
+// request spot instances.
+func isSpotMarket(d gjson.Result) bool {
+	return strings.ToLower(d.Get("instance_market_options.0.market_type").String()) == "spot"
+}
+
+// spotMaxPrice returns the first of the values that is set as the max price
+// bid for spot instances. If none are set the max price is the on-demand
+// price so nil is returned.
+func spotMaxPrice(values ...gjson.Result) *float64 {
+	for _, v := range values {
+		if v.String() == "" {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err == nil {
+			return &f
+		}
+	}
+
+	return nil
+}
@@ -66,11 +66,16 @@ func aksClusterNodePool(name, region string, n gjson.Result, nodeCount decimal.D
 		}
 	}
 
+	priority := n.Get("priority").String()
+
+	var instance *schema.CostComponent
 	if strings.EqualFold(os, "windows") {
-		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, "None", monthlyHours))
+		instance = windowsVirtualMachineCostComponent(region, instanceType, "None", priority, monthlyHours)
 	} else {
-		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, monthlyHours))
+		instance = linuxVirtualMachineCostComponent(region, instanceType, priority, monthlyHours)
 	}
+	instance.MaxPrice = virtualMachineMaxPrice(n.Get("spot_max_price"))
+	costComponents = append(costComponents, instance)
 
 	mainResource.CostComponents = costComponents
 	schema.MultiplyQuantities(mainResource, nodeCount)
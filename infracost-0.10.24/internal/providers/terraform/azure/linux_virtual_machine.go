@@ -7,6 +7,7 @@ import (
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
 )
 
 func GetAzureRMLinuxVirtualMachineRegistryItem() *schema.RegistryItem {
@@ -15,7 +16,7 @@ func GetAzureRMLinuxVirtualMachineRegistryItem() *schema.RegistryItem {
 		RFunc: NewAzureRMLinuxVirtualMachine,
 		Notes: []string{
 			"Non-standard images such as RHEL are not supported.",
-			"Low priority, Spot and Reserved instances are not supported.",
+			"Reserved instances are not supported.",
 		},
 	}
 }
@@ -30,7 +31,10 @@ func NewAzureRMLinuxVirtualMachine(d *schema.ResourceData, u *schema.UsageData)
 		monthlyHours = u.GetFloat("monthly_hrs")
 	}
 
-	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType, monthlyHours)}
+	instance := linuxVirtualMachineCostComponent(region, instanceType, d.Get("priority").String(), monthlyHours)
+	instance.MaxPrice = virtualMachineMaxPrice(d.Get("max_bid_price"))
+
+	costComponents := []*schema.CostComponent{instance}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -50,9 +54,9 @@ func NewAzureRMLinuxVirtualMachine(d *schema.ResourceData, u *schema.UsageData)
 	}
 }
 
-func linuxVirtualMachineCostComponent(region string, instanceType string, monthlyHours *float64) *schema.CostComponent {
+func linuxVirtualMachineCostComponent(region string, instanceType string, priority string, monthlyHours *float64) *schema.CostComponent {
 	purchaseOption := "Consumption"
-	purchaseOptionLabel := "pay as you go"
+	purchaseOptionLabel, skuNameRe := virtualMachinePriority(priority)
 
 	productNameRe := "/Virtual Machines .* Series$/"
 	if strings.HasPrefix(strings.ToLower(instanceType), "basic_") {
@@ -78,7 +82,7 @@ func linuxVirtualMachineCostComponent(region string, instanceType string, monthl
 			ProductFamily: strPtr("Compute"),
 			AttributeFilters: []*schema.AttributeFilter{
 				{Key: "meterName", ValueRegex: strPtr("/^(?!.*(Expired|Free)$).*$/i")},
-				{Key: "skuName", ValueRegex: strPtr("/^(?!.*(Low Priority|Spot)$).*$/i")},
+				{Key: "skuName", ValueRegex: strPtr(skuNameRe)},
 				{Key: "armSkuName", ValueRegex: strPtr(fmt.Sprintf("/^%s$/i", instanceType))},
 				{Key: "productName", ValueRegex: strPtr(productNameRe)},
 			},
@@ -89,3 +93,28 @@ func linuxVirtualMachineCostComponent(region string, instanceType string, monthl
 		},
 	}
 }
+
+// virtualMachinePriority returns the label and the skuName filter for the
+// priority of a VM. Spot and low priority VMs have their own SKUs, e.g.
+// "D2s v3 Spot", which are excluded when pricing regular VMs.
+func virtualMachinePriority(priority string) (string, string) {
+	switch strings.ToLower(priority) {
+	case "spot":
+		return "spot", "/ Spot$/i"
+	case "low":
+		return "low priority", "/ Low Priority$/i"
+	default:
+		return "pay as you go", "/^(?!.*(Low Priority|Spot)$).*$/i"
+	}
+}
+
+// virtualMachineMaxPrice returns the max price per hour in USD that a spot VM
+// is allowed to cost. Azure uses -1 to mean the VM is only evicted for
+// capacity reasons, so it is not capped.
+func virtualMachineMaxPrice(maxBidPrice gjson.Result) *decimal.Decimal {
+	if maxBidPrice.Type != gjson.Number || maxBidPrice.Float() <= 0 {
+		return nil
+	}
+
+	return decimalPtr(decimal.NewFromFloat(maxBidPrice.Float()))
+}
@@ -18,7 +18,10 @@ func NewAzureRMLinuxVirtualMachineScaleSet(d *schema.ResourceData, u *schema.Usa
 
 	instanceType := d.Get("sku").String()
 
-	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType, nil)}
+	instance := linuxVirtualMachineCostComponent(region, instanceType, d.Get("priority").String(), nil)
+	instance.MaxPrice = virtualMachineMaxPrice(d.Get("max_bid_price"))
+
+	costComponents := []*schema.CostComponent{instance}
 	subResources := make([]*schema.Resource, 0)
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
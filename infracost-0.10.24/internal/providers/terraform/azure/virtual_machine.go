@@ -39,9 +39,9 @@ func NewAzureRMVirtualMachine(d *schema.ResourceData, u *schema.UsageData) *sche
 
 	if strings.ToLower(os) == "windows" {
 		licenseType := d.Get("license_type").String()
-		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, licenseType, monthlyHours))
+		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, licenseType, "Regular", monthlyHours))
 	} else {
-		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, monthlyHours))
+		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, "Regular", monthlyHours))
 	}
 
 	// TODO: is this always assuming ultrassdreservation cost?
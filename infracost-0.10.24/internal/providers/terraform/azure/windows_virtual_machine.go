@@ -13,7 +13,7 @@ func GetAzureRMWindowsVirtualMachineRegistryItem() *schema.RegistryItem {
 		Name:  "azurerm_windows_virtual_machine",
 		RFunc: NewAzureRMWindowsVirtualMachine,
 		Notes: []string{
-			"Low priority, Spot and Reserved instances are not supported.",
+			"Reserved instances are not supported.",
 		},
 	}
 }
@@ -29,7 +29,10 @@ func NewAzureRMWindowsVirtualMachine(d *schema.ResourceData, u *schema.UsageData
 		monthlyHours = u.GetFloat("monthly_hrs")
 	}
 
-	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(region, instanceType, licenseType, monthlyHours)}
+	instance := windowsVirtualMachineCostComponent(region, instanceType, licenseType, d.Get("priority").String(), monthlyHours)
+	instance.MaxPrice = virtualMachineMaxPrice(d.Get("max_bid_price"))
+
+	costComponents := []*schema.CostComponent{instance}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -49,9 +52,9 @@ func NewAzureRMWindowsVirtualMachine(d *schema.ResourceData, u *schema.UsageData
 	}
 }
 
-func windowsVirtualMachineCostComponent(region string, instanceType string, licenseType string, monthlyHours *float64) *schema.CostComponent {
+func windowsVirtualMachineCostComponent(region string, instanceType string, licenseType string, priority string, monthlyHours *float64) *schema.CostComponent {
 	purchaseOption := "Consumption"
-	purchaseOptionLabel := "pay as you go"
+	purchaseOptionLabel, skuNameRe := virtualMachinePriority(priority)
 
 	productNameRe := "/Virtual Machines .* Series Windows$/"
 	if strings.HasPrefix(instanceType, "Basic_") {
@@ -63,7 +66,11 @@ func windowsVirtualMachineCostComponent(region string, instanceType string, lice
 	// Handle Azure Hybrid Benefit
 	if strings.ToLower(licenseType) == "windows_client" || strings.ToLower(licenseType) == "windows_server" {
 		purchaseOption = "DevTestConsumption"
-		purchaseOptionLabel = "hybrid benefit"
+		if purchaseOptionLabel == "pay as you go" {
+			purchaseOptionLabel = "hybrid benefit"
+		} else {
+			purchaseOptionLabel += ", hybrid benefit"
+		}
 	}
 
 	qty := decimal.NewFromFloat(730)
@@ -82,7 +89,7 @@ func windowsVirtualMachineCostComponent(region string, instanceType string, lice
 			Service:       strPtr("Virtual Machines"),
 			ProductFamily: strPtr("Compute"),
 			AttributeFilters: []*schema.AttributeFilter{
-				{Key: "skuName", ValueRegex: strPtr("/^(?!.*(Low Priority|Spot)$).*$/i")},
+				{Key: "skuName", ValueRegex: strPtr(skuNameRe)},
 				{Key: "armSkuName", ValueRegex: strPtr(fmt.Sprintf("/^%s$/i", instanceType))},
 				{Key: "productName", ValueRegex: strPtr(productNameRe)},
 			},
@@ -19,7 +19,10 @@ func NewAzureRMWindowsVirtualMachineScaleSet(d *schema.ResourceData, u *schema.U
 	instanceType := d.Get("sku").String()
 	licenseType := d.Get("license_type").String()
 
-	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(region, instanceType, licenseType, nil)}
+	instance := windowsVirtualMachineCostComponent(region, instanceType, licenseType, d.Get("priority").String(), nil)
+	instance.MaxPrice = virtualMachineMaxPrice(d.Get("max_bid_price"))
+
+	costComponents := []*schema.CostComponent{instance}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
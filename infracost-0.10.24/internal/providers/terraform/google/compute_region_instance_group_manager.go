@@ -39,9 +39,7 @@ func newComputeRegionInstanceGroupManager(d *schema.ResourceData, u *schema.Usag
 
 		machineType = instanceTemplate.Get("machine_type").String()
 
-		if instanceTemplate.Get("scheduling.0.preemptible").Bool() {
-			purchaseOption = "preemptible"
-		}
+		purchaseOption = getComputePurchaseOption(instanceTemplate.RawValues)
 
 		for _, disk := range instanceTemplate.Get("disk").Array() {
 			diskType := disk.Get("type").String()
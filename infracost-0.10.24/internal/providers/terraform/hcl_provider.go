@@ -159,7 +159,10 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 	}
 	var scanner *scan.TerraformPlanScanner
 	if runCtx.Config.PolicyAPIEndpoint != "" {
-		scanner = scan.NewTerraformPlanScanner(runCtx, ctx.Logger(), prices.GetPrices)
+		scanner, err = scan.NewTerraformPlanScanner(runCtx, ctx.Logger(), prices.GetPrices)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &HCLProvider{
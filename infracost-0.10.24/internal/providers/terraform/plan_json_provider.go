@@ -21,10 +21,14 @@ type PlanJSONProvider struct {
 	logger               *logrus.Entry
 }
 
-func NewPlanJSONProvider(ctx *config.ProjectContext, includePastResources bool) *PlanJSONProvider {
+func NewPlanJSONProvider(ctx *config.ProjectContext, includePastResources bool) (*PlanJSONProvider, error) {
 	var scanner *scan.TerraformPlanScanner
 	if ctx.RunContext.Config.PolicyAPIEndpoint != "" {
-		scanner = scan.NewTerraformPlanScanner(ctx.RunContext, ctx.Logger(), prices.GetPrices)
+		var err error
+		scanner, err = scan.NewTerraformPlanScanner(ctx.RunContext, ctx.Logger(), prices.GetPrices)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &PlanJSONProvider{
@@ -33,7 +37,7 @@ func NewPlanJSONProvider(ctx *config.ProjectContext, includePastResources bool)
 		includePastResources: includePastResources,
 		scanner:              scanner,
 		logger:               ctx.Logger(),
-	}
+	}, nil
 }
 
 func (p *PlanJSONProvider) Type() string {
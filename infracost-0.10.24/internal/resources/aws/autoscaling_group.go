@@ -22,6 +22,7 @@ type AutoscalingGroup struct {
 
 var AutoscalingGroupUsageSchema = append([]*schema.UsageItem{
 	{Key: "instances", DefaultValue: 0, ValueType: schema.Int64},
+	{Key: "spot_percentage", DefaultValue: 0.0, ValueType: schema.Float64},
 }, InstanceUsageSchema...)
 
 func (a *AutoscalingGroup) PopulateUsage(u *schema.UsageData) {
@@ -30,6 +30,8 @@ type Instance struct {
 	HasHost          bool
 
 	// "optional" args, that may be empty depending on the resource config
+	// SpotMaxPrice is the maximum hourly price in USD bid for spot instances.
+	SpotMaxPrice                    *float64
 	ElasticInferenceAcceleratorType *string
 	RootBlockDevice                 *EBSVolume
 	EBSBlockDevices                 []*EBSVolume
@@ -191,6 +193,13 @@ func (a *Instance) computeCostComponent() *schema.CostComponent {
 		purchaseOptionLabel = "reserved"
 	}
 
+	// Spot instances are charged the spot price of their region, but never
+	// more than the max price.
+	var maxPrice *decimal.Decimal
+	if a.PurchaseOption == "spot" && a.ReservedInstanceType == nil && a.SpotMaxPrice != nil {
+		maxPrice = decimalPtr(decimal.NewFromFloat(*a.SpotMaxPrice))
+	}
+
 	qty := decimal.NewFromFloat(730)
 	if a.MonthlyHours != nil {
 		qty = decimal.NewFromFloat(*a.MonthlyHours)
@@ -216,6 +225,7 @@ func (a *Instance) computeCostComponent() *schema.CostComponent {
 			},
 		},
 		PriceFilter: priceFilter,
+		MaxPrice:    maxPrice,
 	}
 }
 
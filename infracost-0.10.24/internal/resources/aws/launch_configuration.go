@@ -22,6 +22,7 @@ type LaunchConfiguration struct {
 	CPUCredits       string
 
 	// "optional" args, that may be empty depending on the resource config
+	SpotMaxPrice                    *float64
 	ElasticInferenceAcceleratorType *string
 	RootBlockDevice                 *EBSVolume
 	EBSBlockDevices                 []*EBSVolume
@@ -57,6 +58,7 @@ func (a *LaunchConfiguration) BuildResource() *schema.Resource {
 		Region:                          a.Region,
 		Tenancy:                         a.Tenancy,
 		PurchaseOption:                  a.PurchaseOption,
+		SpotMaxPrice:                    a.SpotMaxPrice,
 		AMI:                             a.AMI,
 		InstanceType:                    a.InstanceType,
 		EBSOptimized:                    a.EBSOptimized,
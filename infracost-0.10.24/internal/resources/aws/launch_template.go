@@ -23,6 +23,7 @@ type LaunchTemplate struct {
 	CPUCredits                       string
 
 	// "optional" args, that may be empty depending on the resource config
+	SpotMaxPrice                    *float64
 	ElasticInferenceAcceleratorType *string
 	RootBlockDevice                 *EBSVolume
 	EBSBlockDevices                 []*EBSVolume
@@ -36,9 +37,15 @@ type LaunchTemplate struct {
 	ReservedInstancePaymentOption *string `infracost_usage:"reserved_instance_payment_option"`
 	MonthlyCPUCreditHours         *int64  `infracost_usage:"monthly_cpu_credit_hrs"`
 	VCPUCount                     *int64  `infracost_usage:"vcpu_count"`
+	// SpotPercentage overrides the split between on-demand and spot instances,
+	// e.g. when a mixed instances policy can't always get spot capacity. It is
+	// clamped to between 0 and 100.
+	SpotPercentage *float64 `infracost_usage:"spot_percentage"`
 }
 
-var LaunchTemplateUsageSchema = InstanceUsageSchema
+var LaunchTemplateUsageSchema = append([]*schema.UsageItem{
+	{Key: "spot_percentage", DefaultValue: 0.0, ValueType: schema.Float64},
+}, InstanceUsageSchema...)
 
 func (a *LaunchTemplate) PopulateUsage(u *schema.UsageData) {
 	resources.PopulateArgsWithUsage(a, u)
@@ -64,6 +71,7 @@ func (a *LaunchTemplate) BuildResource() *schema.Resource {
 		EBSOptimized:                    a.EBSOptimized,
 		EnableMonitoring:                a.EnableMonitoring,
 		CPUCredits:                      a.CPUCredits,
+		SpotMaxPrice:                    a.SpotMaxPrice,
 		ElasticInferenceAcceleratorType: a.ElasticInferenceAcceleratorType,
 		OperatingSystem:                 a.OperatingSystem,
 		RootBlockDevice:                 a.RootBlockDevice,
@@ -123,6 +131,18 @@ func (a *LaunchTemplate) calculateOnDemandAndSpotInstanceCounts() (int64, int64)
 		instanceCount = *a.InstanceCount
 	}
 
+	if a.SpotPercentage != nil {
+		spotPerc := decimal.NewFromFloat(*a.SpotPercentage)
+		if spotPerc.LessThan(decimal.Zero) || spotPerc.GreaterThan(decimal.NewFromInt(100)) {
+			log.Warnf("Invalid spot_percentage %s for %s, it must be between 0 and 100", spotPerc, a.Address)
+			spotPerc = decimal.Max(decimal.Zero, decimal.Min(spotPerc, decimal.NewFromInt(100)))
+		}
+
+		onDemandPerc := decimal.NewFromInt(100).Sub(spotPerc).Div(decimal.NewFromInt(100))
+		onDemandInstanceCount := decimal.NewFromInt(instanceCount).Mul(onDemandPerc).Ceil().IntPart()
+		return onDemandInstanceCount, instanceCount - onDemandInstanceCount
+	}
+
 	onDemandInstanceCount := a.OnDemandBaseCount
 	remainingCount := instanceCount - onDemandInstanceCount
 	percMultiplier := decimal.NewFromInt(a.OnDemandPercentageAboveBaseCount).Div(decimal.NewFromInt(100))
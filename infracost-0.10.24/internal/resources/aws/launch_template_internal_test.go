@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaunchTemplateOnDemandAndSpotInstanceCounts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                             string
+		instanceCount                    int64
+		onDemandBaseCount                int64
+		onDemandPercentageAboveBaseCount int64
+		spotPercentage                   *float64
+		expectedOnDemand                 int64
+		expectedSpot                     int64
+	}{
+		{"all on-demand", 4, 0, 100, nil, 4, 0},
+		{"all spot", 4, 0, 0, nil, 0, 4},
+		{"base count and percentage", 10, 2, 50, nil, 6, 4},
+		{"spot percentage overrides policy", 10, 2, 50, floatPtr(80), 2, 8},
+		{"spot percentage rounds on-demand up", 3, 0, 0, floatPtr(50), 2, 1},
+		{"zero spot percentage", 4, 0, 0, floatPtr(0), 4, 0},
+		{"spot percentage above 100", 4, 0, 100, floatPtr(150), 0, 4},
+		{"negative spot percentage", 4, 0, 0, floatPtr(-20), 4, 0},
+	}
+
+	for _, test := range tests {
+		lt := &LaunchTemplate{
+			InstanceCount:                    &test.instanceCount,
+			OnDemandBaseCount:                test.onDemandBaseCount,
+			OnDemandPercentageAboveBaseCount: test.onDemandPercentageAboveBaseCount,
+			SpotPercentage:                   test.spotPercentage,
+		}
+
+		onDemand, spot := lt.calculateOnDemandAndSpotInstanceCounts()
+		assert.Equal(t, test.expectedOnDemand, onDemand, test.name)
+		assert.Equal(t, test.expectedSpot, spot, test.name)
+	}
+}
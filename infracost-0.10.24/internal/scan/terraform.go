@@ -13,33 +13,40 @@ import (
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/prices"
 	"github.com/infracost/infracost/internal/schema"
 )
 
-// GetPricesFunc fetches a price for the given resource r using client c.
+// GetPricesFunc fetches a price for the given resource r using pricing source c.
 // This interface is extracted to avoid circular deps and ease of testing.
-type GetPricesFunc func(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.Resource) error
+type GetPricesFunc func(ctx *config.RunContext, c prices.PricingSource, r *schema.Resource) error
 
 // TerraformPlanScanner scans a plan for Infracost Cloud cost optimizations. These optimizations are provided by the
 // policy API and the scanner links any suggestions to raw resources. It attempts to find cost estimates for any
 // policies that are found.
 type TerraformPlanScanner struct {
-	pricingAPIClient *apiclient.PricingAPIClient
-	policyAPIClient  apiclient.PolicyClient
-	logger           *log.Entry
-	ctx              *config.RunContext
-	getPrices        GetPricesFunc
+	pricingSource   prices.PricingSource
+	policyAPIClient apiclient.PolicyClient
+	logger          *log.Entry
+	ctx             *config.RunContext
+	getPrices       GetPricesFunc
 }
 
-// NewTerraformPlanScanner returns an initialised TerraformPlanScanner.
-func NewTerraformPlanScanner(ctx *config.RunContext, logger *log.Entry, getPrices GetPricesFunc) *TerraformPlanScanner {
-	return &TerraformPlanScanner{
-		pricingAPIClient: apiclient.NewPricingAPIClient(ctx),
-		policyAPIClient:  apiclient.NewPolicyClient(ctx.Config, logger),
-		logger:           logger,
-		ctx:              ctx,
-		getPrices:        getPrices,
+// NewTerraformPlanScanner returns an initialised TerraformPlanScanner. It
+// returns an error if the pricing sources of the run are misconfigured.
+func NewTerraformPlanScanner(ctx *config.RunContext, logger *log.Entry, getPrices GetPricesFunc) (*TerraformPlanScanner, error) {
+	source, err := prices.NewPricingSource(ctx)
+	if err != nil {
+		return nil, err
 	}
+
+	return &TerraformPlanScanner{
+		pricingSource:   source,
+		policyAPIClient: apiclient.NewPolicyClient(ctx.Config, logger),
+		logger:          logger,
+		ctx:             ctx,
+		getPrices:       getPrices,
+	}, nil
 }
 
 // ScanPlan scans the provided projectPlan for the project, if any Policies are found for the plan
@@ -182,7 +189,7 @@ func (s *TerraformPlanScanner) buildResource(coreResource schema.CoreResource, u
 	coreResource.PopulateUsage(usage)
 	r := coreResource.BuildResource()
 
-	err := s.getPrices(s.ctx, s.pricingAPIClient, r)
+	err := s.getPrices(s.ctx, s.pricingSource, r)
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch prices for core resource %s %w", coreResource.CoreType(), err)
 	}
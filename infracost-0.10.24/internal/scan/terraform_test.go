@@ -12,13 +12,24 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/prices"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/scan"
 	"github.com/infracost/infracost/internal/schema"
 )
 
+func TestNewTerraformPlanScanner_InvalidPricingSource(t *testing.T) {
+	runCtx := &config.RunContext{
+		Config: &config.Config{
+			PricingSources: []*config.PricingSource{{Type: "invalid"}},
+		},
+	}
+
+	_, err := scan.NewTerraformPlanScanner(runCtx, newDiscardLogger(), prices.GetPrices)
+	assert.EqualError(t, err, "Invalid pricing source type invalid")
+}
+
 func TestTerraformPlanScanner_ScanPlan(t *testing.T) {
 	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/policy" {
@@ -56,7 +67,7 @@ func TestTerraformPlanScanner_ScanPlan(t *testing.T) {
 	newCost := decimal.NewFromInt(5)
 
 	var called int
-	ps := scan.NewTerraformPlanScanner(runCtx, newDiscardLogger(), func(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.Resource) error {
+	ps, err := scan.NewTerraformPlanScanner(runCtx, newDiscardLogger(), func(ctx *config.RunContext, c prices.PricingSource, r *schema.Resource) error {
 		t.Helper()
 
 		if called == 0 {
@@ -78,6 +89,7 @@ func TestTerraformPlanScanner_ScanPlan(t *testing.T) {
 		called += 1
 		return nil
 	})
+	require.NoError(t, err)
 
 	ctx := config.NewProjectContext(&config.RunContext{Config: &config.Config{}}, &config.Project{Path: "./testdata/simple_project"}, logrus.Fields{})
 	hclp, err := terraform.NewHCLProvider(
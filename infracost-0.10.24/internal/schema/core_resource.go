@@ -1,5 +1,11 @@
 package schema
 
+import (
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
 type CoreResourceFunc func(*ResourceData) CoreResource
 
 // CoreResource is the new/preferred way to represent provider-agnostic resources that
@@ -89,9 +95,53 @@ func BuildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource
 	res.ResourceType = partial.ResourceData.Type
 	res.Tags = partial.ResourceData.Tags
 	res.Metadata = partial.ResourceData.Metadata
+
+	if region := resourceRegion(partial.ResourceData, res); region != "" {
+		res.Metadata = withMetadataValue(res.Metadata, "region", region)
+	}
+
 	return res
 }
 
+// resourceRegion returns the region the provider parsed for the resource,
+// otherwise the region of the products its costs are for. It's recorded in
+// the resource metadata so that outputs can use it without the prices.
+func resourceRegion(d *ResourceData, r *Resource) string {
+	if region := d.Get("region").String(); region != "" {
+		return region
+	}
+
+	return costComponentsRegion(r)
+}
+
+func costComponentsRegion(r *Resource) string {
+	for _, c := range r.CostComponents {
+		if c.ProductFilter != nil && StrValue(c.ProductFilter.Region) != "" {
+			return *c.ProductFilter.Region
+		}
+	}
+
+	for _, s := range r.SubResources {
+		if region := costComponentsRegion(s); region != "" {
+			return region
+		}
+	}
+
+	return ""
+}
+
+// withMetadataValue returns a copy of the metadata with the string value set,
+// so that the metadata of the resource data isn't changed.
+func withMetadataValue(metadata map[string]gjson.Result, key, value string) map[string]gjson.Result {
+	m := make(map[string]gjson.Result, len(metadata)+1)
+	for k, v := range metadata {
+		m[k] = v
+	}
+
+	m[key] = gjson.Result{Type: gjson.String, Str: value, Raw: strconv.Quote(value)}
+	return m
+}
+
 func BuildResources(projects []*Project, projectPtrToUsageMap map[*Project]UsageMap) {
 	for _, project := range projects {
 		usageMap := projectPtrToUsageMap[project]
@@ -21,19 +21,58 @@ type CostComponent struct {
 	price                decimal.Decimal
 	customPrice          *decimal.Decimal
 	priceHash            string
+	priceSource          string
+	priceSku             string
+	discount             *Discount
+	priceCandidates      []*PriceCandidate
+	priceNotFound        string
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
+	// MaxPrice caps the price found by the pricing source, e.g. to the maximum
+	// price bid for a spot instance. It is in USD so is only applied when the
+	// prices are looked up in USD.
+	MaxPrice *decimal.Decimal
+}
+
+// Discount records a discount rule that was applied to the price of a cost
+// component, along with the list price and costs before it was applied.
+type Discount struct {
+	// Name is the name of the discount rule.
+	Name            string
+	ListPrice       decimal.Decimal
+	ListHourlyCost  *decimal.Decimal
+	ListMonthlyCost *decimal.Decimal
+}
+
+// PriceCandidate is a product and price that matched the filters of a cost
+// component. The price and unit are as returned by the pricing source, before
+// any unit multiplier is applied.
+type PriceCandidate struct {
+	Sku        string
+	Attributes map[string]string
+	Price      decimal.Decimal
+	Unit       string
 }
 
 func (c *CostComponent) CalculateCosts() {
 	c.fillQuantities()
+	c.HourlyCost, c.MonthlyCost = c.costsForPrice(c.price)
+
+	if c.discount != nil {
+		c.discount.ListHourlyCost, c.discount.ListMonthlyCost = c.costsForPrice(c.discount.ListPrice)
+	}
+}
+
+func (c *CostComponent) costsForPrice(price decimal.Decimal) (hourlyCost *decimal.Decimal, monthlyCost *decimal.Decimal) {
 	if c.HourlyQuantity != nil {
-		c.HourlyCost = decimalPtr(c.price.Mul(*c.HourlyQuantity))
+		hourlyCost = decimalPtr(price.Mul(*c.HourlyQuantity))
 	}
 	if c.MonthlyQuantity != nil {
 		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
-		c.MonthlyCost = decimalPtr(c.price.Mul(*c.MonthlyQuantity).Mul(discountMul))
+		monthlyCost = decimalPtr(price.Mul(*c.MonthlyQuantity).Mul(discountMul))
 	}
+
+	return hourlyCost, monthlyCost
 }
 
 func (c *CostComponent) fillQuantities() {
@@ -60,6 +99,86 @@ func (c *CostComponent) PriceHash() string {
 	return c.priceHash
 }
 
+// SetPriceSource sets the name of the pricing source that the price was found in.
+func (c *CostComponent) SetPriceSource(source string) {
+	c.priceSource = source
+}
+
+// PriceSource returns the name of the pricing source that the price was found in.
+func (c *CostComponent) PriceSource() string {
+	return c.priceSource
+}
+
+// SetPriceSku sets the sku of the product that the price was found for.
+func (c *CostComponent) SetPriceSku(sku string) {
+	c.priceSku = sku
+}
+
+// PriceSku returns the sku of the product that the price was found for, this
+// is empty if the sku wasn't returned by the pricing source.
+func (c *CostComponent) PriceSku() string {
+	return c.priceSku
+}
+
+// ApplyDiscount sets the price to the discounted price given by the named
+// discount rule. The current price is kept as the list price, so applying
+// another discount replaces the previous one rather than compounding it.
+func (c *CostComponent) ApplyDiscount(name string, price decimal.Decimal) {
+	listPrice := c.price
+	if c.discount != nil {
+		listPrice = c.discount.ListPrice
+	}
+
+	c.discount = &Discount{Name: name, ListPrice: listPrice}
+	c.price = price
+}
+
+// SetDiscount sets the discount of the cost component without changing its price.
+func (c *CostComponent) SetDiscount(discount *Discount) {
+	c.discount = discount
+}
+
+// listPriceAndCosts returns the price and costs of the cost component before
+// any discount was applied.
+func (c *CostComponent) listPriceAndCosts() (decimal.Decimal, *decimal.Decimal, *decimal.Decimal) {
+	if c.discount == nil {
+		return c.price, c.HourlyCost, c.MonthlyCost
+	}
+
+	return c.discount.ListPrice, c.discount.ListHourlyCost, c.discount.ListMonthlyCost
+}
+
+// Discount returns the discount applied to the cost component, this is nil if
+// no discount was applied.
+func (c *CostComponent) Discount() *Discount {
+	return c.discount
+}
+
+// SetPriceCandidates records the candidates found when the filters of the
+// cost component matched more than one price. The first candidate is the one
+// that was used.
+func (c *CostComponent) SetPriceCandidates(candidates []*PriceCandidate) {
+	c.priceCandidates = candidates
+}
+
+// PriceCandidates returns the candidates if the filters of the cost component
+// matched more than one price.
+func (c *CostComponent) PriceCandidates() []*PriceCandidate {
+	return c.priceCandidates
+}
+
+// SetPriceNotFound records the reason no price was found for the cost
+// component, e.g. "No products found".
+func (c *CostComponent) SetPriceNotFound(reason string) {
+	c.priceNotFound = reason
+}
+
+// PriceNotFound returns the reason no price was found for the cost component,
+// or an empty string if a price was found.
+func (c *CostComponent) PriceNotFound() string {
+	return c.priceNotFound
+}
+
 func (c *CostComponent) SetCustomPrice(price *decimal.Decimal) {
 	c.customPrice = price
 }
@@ -73,6 +192,16 @@ func (c *CostComponent) UnitMultiplierPrice() decimal.Decimal {
 	return c.Price().Mul(c.UnitMultiplier)
 }
 
+// UnitMultiplierListPrice returns the list price multiplied by the unit
+// multiplier, or nil if no discount was applied.
+func (c *CostComponent) UnitMultiplierListPrice() *decimal.Decimal {
+	if c.discount == nil {
+		return nil
+	}
+
+	return decimalPtr(c.discount.ListPrice.Mul(c.UnitMultiplier))
+}
+
 func (c *CostComponent) UnitMultiplierHourlyQuantity() *decimal.Decimal {
 	if c.HourlyQuantity == nil {
 		return nil
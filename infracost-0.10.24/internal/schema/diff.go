@@ -186,6 +186,7 @@ func diffCostComponents(past *CostComponent, current *CostComponent) (bool, *Cos
 		ProductFilter:        baseCostComponent.ProductFilter,
 		PriceFilter:          baseCostComponent.PriceFilter,
 		priceHash:            baseCostComponent.priceHash,
+		priceSource:          baseCostComponent.priceSource,
 
 		HourlyQuantity:      diffDecimals(current.HourlyQuantity, past.HourlyQuantity),
 		MonthlyQuantity:     diffDecimals(current.MonthlyQuantity, past.MonthlyQuantity),
@@ -194,6 +195,8 @@ func diffCostComponents(past *CostComponent, current *CostComponent) (bool, *Cos
 		HourlyCost:          diffDecimals(current.HourlyCost, past.HourlyCost),
 		MonthlyCost:         diffDecimals(current.MonthlyCost, past.MonthlyCost),
 	}
+	diff.discount = diffDiscounts(past, current)
+
 	if !diff.HourlyQuantity.IsZero() || !diff.MonthlyQuantity.IsZero() ||
 		diff.MonthlyDiscountPerc != 0 || !diff.price.IsZero() ||
 		!diff.HourlyCost.IsZero() || !diff.MonthlyCost.IsZero() {
@@ -203,6 +206,34 @@ func diffCostComponents(past *CostComponent, current *CostComponent) (bool, *Cos
 	return changed, diff
 }
 
+// diffDiscounts returns the discount of the diff of the two cost components.
+// It is nil if neither cost component was discounted, otherwise its list
+// price and costs are the diff of the list prices and costs of the cost
+// components, using the price and costs of a cost component that wasn't
+// discounted as its list price and costs.
+func diffDiscounts(past *CostComponent, current *CostComponent) *Discount {
+	if past.discount == nil && current.discount == nil {
+		return nil
+	}
+
+	name := ""
+	if current.discount != nil {
+		name = current.discount.Name
+	} else {
+		name = past.discount.Name
+	}
+
+	pastListPrice, pastListHourlyCost, pastListMonthlyCost := past.listPriceAndCosts()
+	listPrice, listHourlyCost, listMonthlyCost := current.listPriceAndCosts()
+
+	return &Discount{
+		Name:            name,
+		ListPrice:       *diffDecimals(&listPrice, &pastListPrice),
+		ListHourlyCost:  diffDecimals(listHourlyCost, pastListHourlyCost),
+		ListMonthlyCost: diffDecimals(listMonthlyCost, pastListMonthlyCost),
+	}
+}
+
 // findMatchingCostComponent finds a matching cost component by first looking for an exact match by name
 // and if that's not found, looking for a match of everything before any brackets.
 func findMatchingCostComponent(costComponents []*CostComponent, name string) *CostComponent {
@@ -243,6 +243,48 @@ func TestDiffCostComponentsByResource(t *testing.T) {
 	assert.Equal(t, expectedDiff, diff)
 }
 
+func TestDiffCostComponentsDiscount(t *testing.T) {
+	past := &CostComponent{
+		Name:        "Instance usage",
+		price:       decimal.NewFromInt(2),
+		HourlyCost:  decimalPtr(decimal.NewFromInt(2)),
+		MonthlyCost: decimalPtr(decimal.NewFromInt(1460)),
+	}
+	current := &CostComponent{
+		Name:        "Instance usage",
+		price:       decimal.NewFromInt(3),
+		HourlyCost:  decimalPtr(decimal.NewFromInt(3)),
+		MonthlyCost: decimalPtr(decimal.NewFromInt(2190)),
+		discount: &Discount{
+			Name:            "EDP",
+			ListPrice:       decimal.NewFromInt(4),
+			ListHourlyCost:  decimalPtr(decimal.NewFromInt(4)),
+			ListMonthlyCost: decimalPtr(decimal.NewFromInt(2920)),
+		},
+	}
+
+	changed, diff := diffCostComponents(past, current)
+	assert.True(t, changed)
+	assert.Equal(t, &Discount{
+		Name:            "EDP",
+		ListPrice:       decimal.NewFromInt(2),
+		ListHourlyCost:  decimalPtr(decimal.NewFromInt(2)),
+		ListMonthlyCost: decimalPtr(decimal.NewFromInt(1460)),
+	}, diff.Discount())
+
+	// Removed discounted cost components have a negative list price and costs.
+	_, diff = diffCostComponents(current, nil)
+	assert.Equal(t, &Discount{
+		Name:            "EDP",
+		ListPrice:       decimal.NewFromInt(-4),
+		ListHourlyCost:  decimalPtr(decimal.NewFromInt(-4)),
+		ListMonthlyCost: decimalPtr(decimal.NewFromInt(-2920)),
+	}, diff.Discount())
+
+	_, diff = diffCostComponents(past, past)
+	assert.Nil(t, diff.Discount())
+}
+
 func TestDiffDecimals(t *testing.T) {
 	dc1 := decimalPtr(decimal.NewFromInt(10))
 	dc2 := decimalPtr(decimal.NewFromInt(20))
@@ -26,3 +26,13 @@ type AttributeFilter struct {
 	Value      *string `json:"value,omitempty"`
 	ValueRegex *string `json:"value_regex,omitempty"`
 }
+
+// StrValue returns the value of a filter field, or an empty string if it
+// isn't set.
+func StrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
 
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/vcs"
@@ -131,6 +134,15 @@ type Project struct {
 	Resources            []*Resource
 	Diff                 []*Resource
 	HasDiff              bool
+	// Projection is the projected monthly cost of the resources for each month
+	// of the projection period, if a projection was requested.
+	Projection []*ProjectedCost
+}
+
+// ProjectedCost is the projected cost of a project for a month.
+type ProjectedCost struct {
+	Month       time.Time
+	MonthlyCost decimal.Decimal
 }
 
 func NewProject(name string, metadata *ProjectMetadata) *Project {
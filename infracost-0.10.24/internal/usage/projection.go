@@ -0,0 +1,144 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Projection holds the assumptions used when projecting costs over several
+// months, it's defined in the projection section of the usage file:
+//
+//	projection:
+//	  start_date: 2024-01-01
+//	  resource_usage:
+//	    aws_s3_bucket.logs:
+//	      monthly_growth:
+//	        standard.storage_gb: 5%
+//	    aws_instance.new_service:
+//	      start_date: 2024-06-01
+type Projection struct {
+	// StartDate is the first month of the projection, it defaults to the
+	// current month.
+	StartDate *time.Time
+	// Resources are the projection assumptions for each resource address.
+	// Addresses can end with [*] to match all the instances of a resource.
+	Resources map[string]*ResourceProjection
+}
+
+// ResourceProjection holds the projection assumptions for a resource.
+type ResourceProjection struct {
+	// StartDate is when the resource is created, the resource has no cost
+	// before this date and its usage growth starts from it.
+	StartDate *time.Time
+	// MonthlyGrowth is the compound monthly growth rate of each usage key,
+	// e.g. 0.05 for a key that grows by 5% a month.
+	MonthlyGrowth map[string]decimal.Decimal
+}
+
+type rawProjection struct {
+	StartDate     string                           `yaml:"start_date"`
+	ResourceUsage map[string]rawResourceProjection `yaml:"resource_usage"`
+}
+
+type rawResourceProjection struct {
+	StartDate     string            `yaml:"start_date"`
+	MonthlyGrowth map[string]string `yaml:"monthly_growth"`
+}
+
+// Projection returns the projection assumptions of the usage file, or nil if
+// it doesn't have a projection section.
+func (u *UsageFile) Projection() (*Projection, error) {
+	if u.RawProjection.IsZero() {
+		return nil, nil
+	}
+
+	var raw rawProjection
+	if err := u.RawProjection.Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "Error parsing usage file projection")
+	}
+
+	p := &Projection{Resources: make(map[string]*ResourceProjection, len(raw.ResourceUsage))}
+
+	var err error
+	p.StartDate, err = parseProjectionDate(raw.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	for address, rawResource := range raw.ResourceUsage {
+		r := &ResourceProjection{MonthlyGrowth: make(map[string]decimal.Decimal, len(rawResource.MonthlyGrowth))}
+
+		r.StartDate, err = parseProjectionDate(rawResource.StartDate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing projection for %s", address)
+		}
+
+		for key, rate := range rawResource.MonthlyGrowth {
+			r.MonthlyGrowth[key], err = parseGrowthRate(rate)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error parsing projection for %s", address)
+			}
+		}
+
+		p.Resources[address] = r
+	}
+
+	return p, nil
+}
+
+// Resource returns the projection assumptions for the resource address, or
+// nil if there are none.
+func (p *Projection) Resource(address string) *ResourceProjection {
+	if p == nil {
+		return nil
+	}
+
+	if r, ok := p.Resources[address]; ok {
+		return r
+	}
+
+	if i := strings.LastIndex(address, "["); i > 0 && strings.HasSuffix(address, "]") {
+		return p.Resources[address[:i]+"[*]"]
+	}
+
+	return nil
+}
+
+func parseProjectionDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid projection start date %s, expected the format YYYY-MM-DD", s)
+	}
+
+	return &t, nil
+}
+
+// parseGrowthRate parses a growth rate given either as a percentage, e.g. 5%,
+// or as a fraction, e.g. 0.05.
+func parseGrowthRate(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+
+	percent := strings.HasSuffix(s, "%")
+	d, err := decimal.NewFromString(strings.TrimSpace(strings.TrimSuffix(s, "%")))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("Invalid monthly growth %s, expected a percentage such as 5%% or a fraction such as 0.05", s)
+	}
+
+	if percent {
+		d = d.Div(decimal.NewFromInt(100))
+	}
+
+	if d.LessThanOrEqual(decimal.NewFromInt(-1)) {
+		return decimal.Zero, fmt.Errorf("Invalid monthly growth %s, usage can't shrink by 100%% or more a month", s)
+	}
+
+	return d, nil
+}
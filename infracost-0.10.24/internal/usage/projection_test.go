@@ -0,0 +1,109 @@
+package usage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func loadUsageFile(t *testing.T, contents string) *usage.UsageFile {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+
+	u, err := usage.LoadUsageFile(path)
+	require.NoError(t, err)
+
+	return u
+}
+
+func TestUsageFileProjection(t *testing.T) {
+	u := loadUsageFile(t, `version: 0.1
+resource_usage:
+  aws_s3_bucket.logs:
+    standard:
+      storage_gb: 1000
+projection:
+  start_date: 2024-01-01
+  resource_usage:
+    aws_s3_bucket.logs:
+      monthly_growth:
+        standard.storage_gb: 5%
+    aws_instance.web[*]:
+      start_date: 2024-06-01
+      monthly_growth:
+        monthly_hrs: 0.1
+`)
+
+	p, err := u.Projection()
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	assert.Equal(t, "2024-01-01", p.StartDate.Format("2006-01-02"))
+
+	logs := p.Resource("aws_s3_bucket.logs")
+	require.NotNil(t, logs)
+	assert.Nil(t, logs.StartDate)
+	assert.Equal(t, "0.05", logs.MonthlyGrowth["standard.storage_gb"].String())
+
+	web := p.Resource("aws_instance.web[2]")
+	require.NotNil(t, web)
+	assert.Equal(t, "2024-06-01", web.StartDate.Format("2006-01-02"))
+	assert.Equal(t, "0.1", web.MonthlyGrowth["monthly_hrs"].String())
+
+	assert.Nil(t, p.Resource("aws_instance.db"))
+}
+
+func TestUsageFileProjectionMissing(t *testing.T) {
+	u := loadUsageFile(t, `version: 0.1
+resource_usage:
+  aws_s3_bucket.logs:
+    standard:
+      storage_gb: 1000
+`)
+
+	p, err := u.Projection()
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	assert.Nil(t, p.Resource("aws_s3_bucket.logs"))
+}
+
+func TestUsageFileProjectionInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		projection string
+		err        string
+	}{
+		{
+			name:       "invalid start date",
+			projection: "  start_date: 01/01/2024\n",
+			err:        "Invalid projection start date 01/01/2024",
+		},
+		{
+			name:       "invalid growth",
+			projection: "  resource_usage:\n    aws_s3_bucket.logs:\n      monthly_growth:\n        standard.storage_gb: fast\n",
+			err:        "Invalid monthly growth fast",
+		},
+		{
+			name:       "shrinks too much",
+			projection: "  resource_usage:\n    aws_s3_bucket.logs:\n      monthly_growth:\n        standard.storage_gb: -100%\n",
+			err:        "usage can't shrink by 100% or more a month",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := loadUsageFile(t, "version: 0.1\nprojection:\n"+tt.projection)
+
+			_, err := u.Projection()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -28,6 +28,9 @@ type UsageFile struct { // nolint:revive
 	RawResourceUsage yamlv3.Node `yaml:"resource_usage"`
 	// The raw usage is then parsed into this struct
 	ResourceUsages []*ResourceUsage `yaml:"-"`
+	// RawProjection is kept as a YAML node so that it is written back as is
+	// when the usage file is synced.
+	RawProjection yamlv3.Node `yaml:"projection,omitempty"`
 }
 
 // CreateUsageFile creates a blank usage file if it does not exists
@@ -144,6 +147,16 @@ See https://infracost.io/usage-file/ for docs`,
 		&u.RawResourceUsage,
 	)
 
+	if !u.RawProjection.IsZero() {
+		root.Content = append(root.Content,
+			&yamlv3.Node{
+				Kind:  yamlv3.ScalarNode,
+				Value: "projection",
+			},
+			&u.RawProjection,
+		)
+	}
+
 	// Add a comment to the first commented-out resource
 	for _, node := range u.RawResourceTypeUsage.Content {
 		if isNodeMarkedAsCommented(node) {